@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/search"
+)
+
+// postAllianceSelection watches for the playoff alliances once quals are over, posts who picked who
+// and seeds the bracket tracker with them so playoff matches land in the right slots.
+// After a restart the tracker is seeded again without posting. event is the tracker's copy, it's saved by the caller.
+func postAllianceSelection(session interactions.Session, event *EventTracked, eventName string, dataProvider provider.Provider, matches []ftcscout.Match) {
+	tracker := GetOrCreateBracketTracker(dataProvider, event.Year, event.EventCode)
+	tracker.mu.Lock()
+	published := tracker.AlliancesPublished
+	tracker.mu.Unlock()
+	if published || !qualsFinished(matches) {
+		return
+	}
+
+	alliances, err := dataProvider.EventAlliances(context.Background(), event.Year, event.EventCode)
+	if err != nil {
+		eventLog(event).Error("Failed to fetch alliances", "err", err)
+		return
+	}
+	if len(alliances) == 0 {
+		// selection isn't over yet
+		return
+	}
+	tracker.SeedAlliances(alliances)
+
+	if event.AlliancesAnnounced {
+		return
+	}
+
+	// ranks make the summary more useful but it's still worth posting without them
+	teams, err := dataProvider.EventRankings(context.Background(), event.Year, event.EventCode)
+	if err != nil {
+		eventLog(event).Warn("Failed to fetch rankings", "err", err)
+	}
+
+	embed := createAllianceSelectionEmbed(eventName, alliances, teams)
+	if _, err := session.ChannelMessageSendEmbed(event.UpdateChannelId, embed); HandleErr(err) {
+		return
+	}
+	event.AlliancesAnnounced = true
+}
+
+// qualsFinished is whether there were quals and all of them have been played, which is when selection starts
+func qualsFinished(matches []ftcscout.Match) bool {
+	quals := 0
+	for _, match := range matches {
+		if match.TournamentLevel != "Quals" {
+			continue
+		}
+		if !match.HasBeenPlayed {
+			return false
+		}
+		quals++
+	}
+	return quals > 0
+}
+
+func createAllianceSelectionEmbed(eventName string, alliances []ftcscout.EventAlliance, teams []ftcscout.EventTeam) *discordgo.MessageEmbed {
+	byNumber := make(map[int]ftcscout.EventTeam)
+	for _, team := range teams {
+		byNumber[team.TeamNumber] = team
+	}
+
+	describe := func(role string, teamNumber int) string {
+		line := fmt.Sprintf("%s: **%d**", role, teamNumber)
+		team := byNumber[teamNumber]
+		name := team.TeamName
+		if name == "" {
+			name, _ = search.GetTeamNameFromAnyRegion(strconv.Itoa(teamNumber))
+		}
+		if name != "" {
+			line += " " + name
+		}
+		if team.Stats != nil && team.Stats.Rank > 0 {
+			line += fmt.Sprintf(" (#%d)", team.Stats.Rank)
+		}
+		return line
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🤝 Alliance Selection Results",
+		Description: fmt.Sprintf("The playoff alliances for **%s** are set! Qual ranks are in brackets.", eventName),
+		Color:       0x72cfdd,
+	}
+	for _, alliance := range alliances {
+		lines := []string{
+			describe("Captain", alliance.Captain),
+			describe("1st pick", alliance.FirstPick),
+		}
+		if alliance.SecondPick != 0 {
+			lines = append(lines, describe("2nd pick", alliance.SecondPick))
+		}
+		if alliance.Backup != 0 {
+			lines = append(lines, describe("Backup", alliance.Backup))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("Alliance %d", alliance.Seed),
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+	return embed
+}
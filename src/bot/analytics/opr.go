@@ -0,0 +1,299 @@
+// Package analytics computes team ratings from an event's match results.
+//
+// OPR (offensive power rating) is the least squares estimate of how many points a team adds to its
+// alliance's score, DPR is the same for the points the opposing alliance scores, and CCWM (calculated
+// contribution to winning margin) is OPR - DPR. Everything is computed for the total score and for
+// the auto, teleop and foul parts of it separately.
+package analytics
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+)
+
+var ErrNoMatches = errors.New("no played matches to compute ratings from")
+
+// Component is one part of an alliance's score
+type Component int
+
+const (
+	Total Component = iota
+	Auto
+	TeleOp
+	// points the alliance got from the other alliance's fouls
+	Fouls
+)
+
+var Components = []Component{Total, Auto, TeleOp, Fouls}
+
+func (c Component) String() string {
+	switch c {
+	case Total:
+		return "Total"
+	case Auto:
+		return "Auto"
+	case TeleOp:
+		return "TeleOp"
+	case Fouls:
+		return "Fouls"
+	default:
+		return "Unknown"
+	}
+}
+
+func (c Component) of(score ftcscout.AllianceScore) float64 {
+	switch c {
+	case Auto:
+		return float64(score.Auto)
+	case TeleOp:
+		return float64(score.TeleOp)
+	case Fouls:
+		return float64(score.Fouls)
+	default:
+		return float64(score.Total)
+	}
+}
+
+type Rating struct {
+	OPR  float64
+	DPR  float64
+	CCWM float64
+}
+
+type TeamRating struct {
+	TeamNumber    int
+	MatchesPlayed int
+
+	// indexed by Component
+	Ratings [4]Rating
+}
+
+func (t TeamRating) Get(c Component) Rating {
+	return t.Ratings[c]
+}
+
+// MatchFilter picks which matches go into the computation
+type MatchFilter func(match ftcscout.Match) bool
+
+func AllMatches(match ftcscout.Match) bool {
+	return true
+}
+
+func QualsOnly(match ftcscout.Match) bool {
+	return match.TournamentLevel == "Quals"
+}
+
+// if the matrix is singular (e.g. a team only ever played with the same partner)
+// this nudges the solve towards splitting the points evenly instead of blowing up
+const ridge = 1e-6
+
+// Model is the result of fitting OPR to a set of matches, it can be used to predict matches too
+type Model struct {
+	// sorted by total OPR, highest first
+	Ratings []TeamRating
+
+	// how many alliance scores went into the fit
+	Samples int
+
+	// variance of the total scores the fit didn't explain, used for win probabilities
+	ResidualVariance float64
+
+	byTeam map[int]int
+}
+
+// Compute solves OPR, DPR and CCWM for every team that played in one of the played matches picked by filter.
+// The result is sorted by total OPR, highest first.
+func Compute(matches []ftcscout.Match, filter MatchFilter) ([]TeamRating, error) {
+	model, err := Fit(matches, filter)
+	if err != nil {
+		return nil, err
+	}
+	return model.Ratings, nil
+}
+
+// Fit is Compute but keeps what's needed to make predictions
+func Fit(matches []ftcscout.Match, filter MatchFilter) (*Model, error) {
+	if filter == nil {
+		filter = AllMatches
+	}
+
+	type allianceRow struct {
+		teams    []int
+		own, opp ftcscout.AllianceScore
+	}
+
+	var rows []allianceRow
+	index := make(map[int]int)
+	var teamNumbers []int
+	played := make(map[int]int)
+
+	for _, match := range matches {
+		if !match.HasBeenPlayed || !filter(match) {
+			continue
+		}
+
+		var red, blue []int
+		for _, team := range match.Teams {
+			if _, ok := index[team.TeamNumber]; !ok {
+				index[team.TeamNumber] = len(teamNumbers)
+				teamNumbers = append(teamNumbers, team.TeamNumber)
+			}
+			played[team.TeamNumber]++
+
+			switch team.AllianceColor {
+			case "Red":
+				red = append(red, index[team.TeamNumber])
+			case "Blue":
+				blue = append(blue, index[team.TeamNumber])
+			}
+		}
+
+		if len(red) > 0 {
+			rows = append(rows, allianceRow{teams: red, own: match.Scores.Red, opp: match.Scores.Blue})
+		}
+		if len(blue) > 0 {
+			rows = append(rows, allianceRow{teams: blue, own: match.Scores.Blue, opp: match.Scores.Red})
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrNoMatches
+	}
+
+	// normal equations (A^T A) x = A^T b, where A has a row per alliance per match with a 1 for each team on it.
+	// every component's OPR and DPR share A so they're solved together, with a column of b each
+	n := len(teamNumbers)
+	columns := 2 * len(Components)
+	ata := make([][]float64, n)
+	atb := make([][]float64, n)
+	for i := range ata {
+		ata[i] = make([]float64, n)
+		atb[i] = make([]float64, columns)
+	}
+
+	for _, row := range rows {
+		for _, a := range row.teams {
+			for _, b := range row.teams {
+				ata[a][b]++
+			}
+			for c, component := range Components {
+				atb[a][c] += component.of(row.own)
+				atb[a][len(Components)+c] += component.of(row.opp)
+			}
+		}
+	}
+
+	x, err := solve(ata, atb)
+	if err != nil {
+		return nil, err
+	}
+
+	// residuals of the total score fit, with the usual n - p correction when there's enough data for it
+	squaredError := 0.0
+	for _, row := range rows {
+		predicted := 0.0
+		for _, team := range row.teams {
+			predicted += x[team][Total]
+		}
+		diff := Total.of(row.own) - predicted
+		squaredError += diff * diff
+	}
+	degreesOfFreedom := len(rows) - n
+	if degreesOfFreedom <= 0 {
+		degreesOfFreedom = len(rows)
+	}
+
+	model := &Model{
+		Ratings:          make([]TeamRating, n),
+		Samples:          len(rows),
+		ResidualVariance: squaredError / float64(degreesOfFreedom),
+		byTeam:           make(map[int]int, n),
+	}
+	for i, teamNumber := range teamNumbers {
+		model.Ratings[i] = TeamRating{TeamNumber: teamNumber, MatchesPlayed: played[teamNumber]}
+		for c := range Components {
+			opr := x[i][c]
+			dpr := x[i][len(Components)+c]
+			model.Ratings[i].Ratings[c] = Rating{OPR: opr, DPR: dpr, CCWM: opr - dpr}
+		}
+	}
+
+	sort.SliceStable(model.Ratings, func(i, j int) bool {
+		return model.Ratings[i].Ratings[Total].OPR > model.Ratings[j].Ratings[Total].OPR
+	})
+	for i, rating := range model.Ratings {
+		model.byTeam[rating.TeamNumber] = i
+	}
+	return model, nil
+}
+
+// Team returns a team's rating, and false if they haven't played in any of the fitted matches
+func (m *Model) Team(teamNumber int) (TeamRating, bool) {
+	i, ok := m.byTeam[teamNumber]
+	if !ok {
+		return TeamRating{}, false
+	}
+	return m.Ratings[i], true
+}
+
+// solve does gaussian elimination with partial pivoting on m x = rhs for every column of rhs at once.
+// m is symmetric positive semi-definite here, so adding ridge to the diagonal always makes it solvable.
+func solve(m [][]float64, rhs [][]float64) ([][]float64, error) {
+	n := len(m)
+	columns := 0
+	if n > 0 {
+		columns = len(rhs[0])
+	}
+
+	// work on copies so callers' matrices aren't changed
+	a := make([][]float64, n)
+	b := make([][]float64, n)
+	for i := range m {
+		a[i] = append([]float64(nil), m[i]...)
+		a[i][i] += ridge
+		b[i] = append([]float64(nil), rhs[i]...)
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, errors.New("could not solve ratings, the match schedule doesn't have enough information")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			if factor == 0 {
+				continue
+			}
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			for k := 0; k < columns; k++ {
+				b[row][k] -= factor * b[col][k]
+			}
+		}
+	}
+
+	x := make([][]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		x[row] = make([]float64, columns)
+		for k := 0; k < columns; k++ {
+			sum := b[row][k]
+			for j := row + 1; j < n; j++ {
+				sum -= a[row][j] * x[j][k]
+			}
+			x[row][k] = sum / a[row][row]
+		}
+	}
+	return x, nil
+}
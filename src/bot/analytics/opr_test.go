@@ -0,0 +1,226 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+)
+
+// ridge keeps the fit from being exact, this is plenty close for scores in the tens
+const tolerance = 1e-3
+
+// played builds a played quals match with auto being half of each alliance's score
+func played(red, blue []int, redScore, blueScore int) ftcscout.Match {
+	match := ftcscout.Match{
+		HasBeenPlayed:   true,
+		TournamentLevel: "Quals",
+		Scores: ftcscout.MatchScores{
+			Red:  ftcscout.AllianceScore{Total: redScore, Auto: redScore / 2, TeleOp: redScore - redScore/2},
+			Blue: ftcscout.AllianceScore{Total: blueScore, Auto: blueScore / 2, TeleOp: blueScore - blueScore/2},
+		},
+	}
+	for _, team := range red {
+		match.Teams = append(match.Teams, ftcscout.MatchTeam{AllianceColor: "Red", TeamNumber: team})
+	}
+	for _, team := range blue {
+		match.Teams = append(match.Teams, ftcscout.MatchTeam{AllianceColor: "Blue", TeamNumber: team})
+	}
+	return match
+}
+
+// teams 1-4 are worth 10, 20, 30 and 40 points and every pair plays together once,
+// so OPR is exactly that, DPR is 50 - OPR (the other two teams) and CCWM is 2 OPR - 50
+var roundRobin = []ftcscout.Match{
+	played([]int{1, 2}, []int{3, 4}, 30, 70),
+	played([]int{1, 3}, []int{2, 4}, 40, 60),
+	played([]int{1, 4}, []int{2, 3}, 50, 50),
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name    string
+		matches []ftcscout.Match
+		filter  MatchFilter
+
+		wantTeams  int
+		wantTotal  map[int]Rating
+		wantPlayed map[int]int
+	}{
+		{
+			name:      "hand solved round robin",
+			matches:   roundRobin,
+			wantTeams: 4,
+			wantTotal: map[int]Rating{
+				1: {OPR: 10, DPR: 40, CCWM: -30},
+				2: {OPR: 20, DPR: 30, CCWM: -10},
+				3: {OPR: 30, DPR: 20, CCWM: 10},
+				4: {OPR: 40, DPR: 10, CCWM: 30},
+			},
+			wantPlayed: map[int]int{1: 3, 2: 3, 3: 3, 4: 3},
+		},
+		{
+			// 1 and 2 only ever play together so only their sum is known, the ridge splits it evenly
+			name:      "underdetermined schedule",
+			matches:   []ftcscout.Match{played([]int{1, 2}, []int{3, 4}, 30, 70)},
+			wantTeams: 4,
+			wantTotal: map[int]Rating{
+				1: {OPR: 15, DPR: 35, CCWM: -20},
+				2: {OPR: 15, DPR: 35, CCWM: -20},
+				3: {OPR: 35, DPR: 15, CCWM: 20},
+				4: {OPR: 35, DPR: 15, CCWM: 20},
+			},
+			wantPlayed: map[int]int{1: 1, 2: 1, 3: 1, 4: 1},
+		},
+		{
+			// 5 plays once next to 1, whose OPR the other matches pin down, so 5 gets the rest of that score
+			name:      "team in only one match",
+			matches:   append(append([]ftcscout.Match{}, roundRobin...), played([]int{1, 5}, []int{2, 3}, 55, 50)),
+			wantTeams: 5,
+			wantTotal: map[int]Rating{
+				1: {OPR: 10},
+				5: {OPR: 45},
+			},
+			wantPlayed: map[int]int{1: 4, 2: 4, 3: 4, 4: 3, 5: 1},
+		},
+		{
+			name: "unplayed and filtered matches are skipped",
+			matches: append(append([]ftcscout.Match{}, roundRobin...),
+				ftcscout.Match{HasBeenPlayed: false, TournamentLevel: "Quals", Teams: []ftcscout.MatchTeam{{AllianceColor: "Red", TeamNumber: 6}}},
+				func() ftcscout.Match {
+					match := played([]int{1, 2}, []int{3, 4}, 500, 0)
+					match.TournamentLevel = "DoubleElim"
+					return match
+				}(),
+			),
+			filter:    QualsOnly,
+			wantTeams: 4,
+			wantTotal: map[int]Rating{
+				1: {OPR: 10, DPR: 40, CCWM: -30},
+				4: {OPR: 40, DPR: 10, CCWM: 30},
+			},
+			wantPlayed: map[int]int{1: 3, 4: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := Fit(tt.matches, tt.filter)
+			if err != nil {
+				t.Fatalf("Fit: %v", err)
+			}
+
+			if len(model.Ratings) != tt.wantTeams {
+				t.Fatalf("%d teams rated, want %d", len(model.Ratings), tt.wantTeams)
+			}
+			for idx := 1; idx < len(model.Ratings); idx++ {
+				if model.Ratings[idx].Get(Total).OPR > model.Ratings[idx-1].Get(Total).OPR+tolerance {
+					t.Errorf("ratings aren't sorted by OPR: %+v", model.Ratings)
+					break
+				}
+			}
+
+			for team, want := range tt.wantTotal {
+				rating, ok := model.Team(team)
+				if !ok {
+					t.Fatalf("no rating for team %d", team)
+				}
+				got := rating.Get(Total)
+				if math.Abs(got.OPR-want.OPR) > tolerance {
+					t.Errorf("team %d OPR = %.4f, want %v", team, got.OPR, want.OPR)
+				}
+				if want.DPR != 0 && math.Abs(got.DPR-want.DPR) > tolerance {
+					t.Errorf("team %d DPR = %.4f, want %v", team, got.DPR, want.DPR)
+				}
+				if want.CCWM != 0 && math.Abs(got.CCWM-want.CCWM) > tolerance {
+					t.Errorf("team %d CCWM = %.4f, want %v", team, got.CCWM, want.CCWM)
+				}
+				if auto := rating.Get(Auto).OPR; math.Abs(auto-want.OPR/2) > 1 {
+					t.Errorf("team %d auto OPR = %.4f, want about %v", team, auto, want.OPR/2)
+				}
+			}
+			for team, want := range tt.wantPlayed {
+				if rating, _ := model.Team(team); rating.MatchesPlayed != want {
+					t.Errorf("team %d played %d, want %d", team, rating.MatchesPlayed, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFitWithoutPlayedMatches(t *testing.T) {
+	unplayed := played([]int{1, 2}, []int{3, 4}, 0, 0)
+	unplayed.HasBeenPlayed = false
+
+	for _, matches := range [][]ftcscout.Match{nil, {unplayed}} {
+		if _, err := Fit(matches, nil); !errors.Is(err, ErrNoMatches) {
+			t.Errorf("Fit(%d matches) err = %v, want ErrNoMatches", len(matches), err)
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name string
+		m    [][]float64
+		rhs  [][]float64
+		want [][]float64
+	}{
+		{
+			name: "2x2 with two columns",
+			m:    [][]float64{{2, 1}, {1, 3}},
+			rhs:  [][]float64{{5, 3}, {10, 4}},
+			want: [][]float64{{1, 1}, {3, 1}},
+		},
+		{
+			// the first pivot is 0 so the rows have to be swapped
+			name: "needs pivoting",
+			m:    [][]float64{{0, 1}, {1, 0}},
+			rhs:  [][]float64{{2}, {7}},
+			want: [][]float64{{7}, {2}},
+		},
+		{
+			// both equations say the same thing, the ridge picks the even split
+			name: "singular",
+			m:    [][]float64{{1, 1}, {1, 1}},
+			rhs:  [][]float64{{4}, {4}},
+			want: [][]float64{{2}, {2}},
+		},
+		{
+			name: "empty",
+			m:    [][]float64{},
+			rhs:  [][]float64{},
+			want: [][]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diagonal []float64
+			for i := range tt.m {
+				diagonal = append(diagonal, tt.m[i][i])
+			}
+
+			got, err := solve(tt.m, tt.rhs)
+			if err != nil {
+				t.Fatalf("solve: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				for k := range tt.want[i] {
+					if math.Abs(got[i][k]-tt.want[i][k]) > tolerance {
+						t.Errorf("x[%d][%d] = %.4f, want %v", i, k, got[i][k], tt.want[i][k])
+					}
+				}
+			}
+			for i := range tt.m {
+				if tt.m[i][i] != diagonal[i] {
+					t.Errorf("solve changed the caller's matrix")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package analytics
+
+import (
+	"math"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/util"
+)
+
+type AlliancePrediction struct {
+	// expected points, indexed by Component
+	Expected [4]float64
+
+	// chance this alliance wins, from 0 to 1
+	WinProbability float64
+
+	// teams on the alliance that haven't played yet, they're counted as an average team
+	UnknownTeams []int
+}
+
+func (a AlliancePrediction) Get(c Component) float64 {
+	return a.Expected[c]
+}
+
+type Prediction struct {
+	Red  AlliancePrediction
+	Blue AlliancePrediction
+
+	// how many alliance scores the prediction is based on
+	Samples int
+}
+
+// Predict forecasts a match from the teams on it. Each alliance's expected score is the sum of its teams'
+// component OPRs and the scores are treated as normally distributed with the fit's residual variance.
+func (m *Model) Predict(match ftcscout.Match) Prediction {
+	prediction := Prediction{Samples: m.Samples}
+
+	for _, team := range match.Teams {
+		var alliance *AlliancePrediction
+		switch team.AllianceColor {
+		case "Red":
+			alliance = &prediction.Red
+		case "Blue":
+			alliance = &prediction.Blue
+		default:
+			continue
+		}
+
+		rating, ok := m.Team(team.TeamNumber)
+		if !ok {
+			alliance.UnknownTeams = append(alliance.UnknownTeams, team.TeamNumber)
+			for c := range Components {
+				alliance.Expected[c] += m.averageOPR(Component(c))
+			}
+			continue
+		}
+		for c := range Components {
+			alliance.Expected[c] += rating.Ratings[c].OPR
+		}
+	}
+
+	// margin = red - blue, the two alliance scores are independent so their variances add
+	margin := prediction.Red.Expected[Total] - prediction.Blue.Expected[Total]
+	marginStdDev := math.Sqrt(2 * m.ResidualVariance)
+	switch {
+	case marginStdDev > 0:
+		prediction.Red.WinProbability = normalCDF(margin / marginStdDev)
+	case margin > 0:
+		prediction.Red.WinProbability = 1
+	case margin < 0:
+		prediction.Red.WinProbability = 0
+	default:
+		prediction.Red.WinProbability = 0.5
+	}
+	// it's still a robot competition, nothing is ever certain
+	prediction.Red.WinProbability = util.Clamp(prediction.Red.WinProbability, 0.01, 0.99)
+	prediction.Blue.WinProbability = 1 - prediction.Red.WinProbability
+
+	return prediction
+}
+
+func (m *Model) averageOPR(c Component) float64 {
+	if len(m.Ratings) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, rating := range m.Ratings {
+		sum += rating.Ratings[c].OPR
+	}
+	return sum / float64(len(m.Ratings))
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * (1 + math.Erf(z/math.Sqrt2))
+}
@@ -0,0 +1,129 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// newModel builds a model with the given total OPRs, auto is a quarter of each
+func newModel(residualVariance float64, oprs map[int]float64) *Model {
+	model := &Model{Samples: 2 * len(oprs), ResidualVariance: residualVariance, byTeam: make(map[int]int)}
+	for team, opr := range oprs {
+		rating := TeamRating{TeamNumber: team, MatchesPlayed: 1}
+		rating.Ratings[Total].OPR = opr
+		rating.Ratings[Auto].OPR = opr / 4
+		model.byTeam[team] = len(model.Ratings)
+		model.Ratings = append(model.Ratings, rating)
+	}
+	return model
+}
+
+func TestPredict(t *testing.T) {
+	oprs := map[int]float64{1: 10, 2: 20, 3: 30, 4: 40}
+
+	tests := []struct {
+		name      string
+		model     *Model
+		red, blue []int
+
+		wantRed, wantBlue float64
+		wantRedWin        float64
+		wantUnknownRed    []int
+		wantUnknownBlue   []int
+	}{
+		{
+			// margin 40 with a standard deviation of sqrt(2 * 400), so z = sqrt(2)
+			name:       "known OPRs",
+			model:      newModel(400, oprs),
+			red:        []int{3, 4},
+			blue:       []int{1, 2},
+			wantRed:    70,
+			wantBlue:   30,
+			wantRedWin: 0.92135,
+		},
+		{
+			name:       "even match",
+			model:      newModel(400, oprs),
+			red:        []int{1, 4},
+			blue:       []int{2, 3},
+			wantRed:    50,
+			wantBlue:   50,
+			wantRedWin: 0.5,
+		},
+		{
+			// a perfect fit would make red certain to win, it's capped instead
+			name:       "no residual variance",
+			model:      newModel(0, oprs),
+			red:        []int{1, 2},
+			blue:       []int{3, 4},
+			wantRed:    30,
+			wantBlue:   70,
+			wantRedWin: 0.01,
+		},
+		{
+			// 99 hasn't played so it counts as the average team, 25
+			name:           "team with no data",
+			model:          newModel(400, oprs),
+			red:            []int{4, 99},
+			blue:           []int{1, 2},
+			wantRed:        65,
+			wantBlue:       30,
+			wantRedWin:     normalCDF(35 / math.Sqrt(800)),
+			wantUnknownRed: []int{99},
+		},
+		{
+			name:            "nobody has data",
+			model:           newModel(0, nil),
+			red:             []int{98, 99},
+			blue:            []int{1, 2},
+			wantRedWin:      0.5,
+			wantUnknownRed:  []int{98, 99},
+			wantUnknownBlue: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prediction := tt.model.Predict(played(tt.red, tt.blue, 0, 0))
+
+			if got := prediction.Red.Get(Total); math.Abs(got-tt.wantRed) > tolerance {
+				t.Errorf("red expected %.2f, want %v", got, tt.wantRed)
+			}
+			if got := prediction.Blue.Get(Total); math.Abs(got-tt.wantBlue) > tolerance {
+				t.Errorf("blue expected %.2f, want %v", got, tt.wantBlue)
+			}
+			if got := prediction.Red.Get(Auto); math.Abs(got-tt.wantRed/4) > tolerance {
+				t.Errorf("red auto expected %.2f, want %v", got, tt.wantRed/4)
+			}
+			if got := prediction.Red.WinProbability; math.Abs(got-tt.wantRedWin) > tolerance {
+				t.Errorf("red win probability %.4f, want %.4f", got, tt.wantRedWin)
+			}
+			if sum := prediction.Red.WinProbability + prediction.Blue.WinProbability; math.Abs(sum-1) > 1e-9 {
+				t.Errorf("win probabilities add up to %v", sum)
+			}
+			if fmt.Sprint(prediction.Red.UnknownTeams, prediction.Blue.UnknownTeams) != fmt.Sprint(tt.wantUnknownRed, tt.wantUnknownBlue) {
+				t.Errorf("unknown teams %v %v, want %v %v", prediction.Red.UnknownTeams, prediction.Blue.UnknownTeams, tt.wantUnknownRed, tt.wantUnknownBlue)
+			}
+			if prediction.Samples != tt.model.Samples {
+				t.Errorf("samples = %d, want %d", prediction.Samples, tt.model.Samples)
+			}
+		})
+	}
+}
+
+// predicting from a real fit, the round robin teams are rated exactly so a rematch comes out as its score
+func TestPredictFromFit(t *testing.T) {
+	model, err := Fit(roundRobin, nil)
+	if err != nil {
+		t.Fatalf("Fit: %v", err)
+	}
+
+	prediction := model.Predict(played([]int{1, 2}, []int{3, 4}, 0, 0))
+	if red, blue := prediction.Red.Get(Total), prediction.Blue.Get(Total); math.Abs(red-30) > tolerance || math.Abs(blue-70) > tolerance {
+		t.Errorf("expected %.2f - %.2f, want 30 - 70", red, blue)
+	}
+	if prediction.Blue.WinProbability < 0.9 {
+		t.Errorf("blue win probability %.4f, want them to be the clear favorite", prediction.Blue.WinProbability)
+	}
+}
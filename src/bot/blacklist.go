@@ -0,0 +1,245 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/blacklist"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/pagination"
+)
+
+var blacklistPaginator *pagination.Paginator[blacklist.Ban]
+
+func init() {
+	adminPermissions := int64(discordgo.PermissionAdministrator)
+
+	interactions.RegisterCommand(
+		&discordgo.ApplicationCommand{
+			Name:                     "blacklist",
+			Description:              "Stop members from using Bjorn's commands in this server (admin only).",
+			DefaultMemberPermissions: &adminPermissions,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "add",
+					Description: "Blacklist a member.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionUser,
+							Name:        "user",
+							Description: "The member to blacklist.",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "reason",
+							Description: "Why they're being blacklisted.",
+							Required:    false,
+							MaxLength:   500,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "duration",
+							Description: "How long the blacklist lasts, e.g. 12h, 3d or 2w. Leave empty for forever.",
+							Required:    false,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "remove",
+					Description: "Take a member off the blacklist.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionUser,
+							Name:        "user",
+							Description: "The member to remove.",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "list",
+					Description: "Show everyone who is blacklisted in this server.",
+				},
+			},
+		},
+		blacklistCommandHandler,
+	)
+
+	blacklistPaginator = pagination.New[blacklist.Ban]("blacklist;list").
+		ItemsPerPage(10).
+		WithDataGetter(func(state pagination.PaginationState) ([]blacklist.Ban, error) {
+			return blacklist.List(state.ExtraData["guildId"]), nil
+		}).
+		AddExtraKey("guildId").
+		OnUpdate(updateBlacklistEmbed).
+		Register()
+}
+
+func blacklistCommandHandler(s interactions.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" || i.Member == nil {
+		interactions.SendEphemeralMessage(s, i, "This command can only be used in a server.")
+		return
+	}
+
+	isAdminUser, err := isAdmin(s, i.GuildID, i.Member.User.ID)
+	if err != nil {
+		interactions.SendEphemeralMessage(s, i, "Unable to check permissions.")
+		return
+	}
+	if !isAdminUser {
+		interactions.SendEphemeralMessage(s, i, "You do not have permission to run this command.")
+		return
+	}
+
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		interactions.SendMessage(s, i, "", "Please provide a subcommand for blacklist.")
+		return
+	}
+
+	sub := data.Options[0]
+	// user options come through as the user's ID
+	userID := interactions.GetStringOption(sub.Options, "user")
+	switch sub.Name {
+	case "add":
+		addToBlacklist(s, i, userID, interactions.GetStringOption(sub.Options, "reason"), interactions.GetStringOption(sub.Options, "duration"))
+	case "remove":
+		removeFromBlacklist(s, i, userID)
+	case "list":
+		err := blacklistPaginator.Setup(s, i, "", map[string]string{"guildId": i.GuildID})
+		if HandleErr(err) {
+			interactions.SendMessage(s, i, "", fmt.Sprintf("Failed to show the blacklist: %v", err))
+		}
+	default:
+		interactions.SendMessage(s, i, "", "Unknown subcommand for blacklist.")
+	}
+}
+
+func addToBlacklist(s interactions.Session, i *discordgo.InteractionCreate, userID, reason, duration string) {
+	if userID == "" {
+		interactions.SendMessage(s, i, "", "Please provide a user to blacklist.")
+		return
+	}
+	if userID == i.Member.User.ID {
+		interactions.SendMessage(s, i, "", "You can't blacklist yourself.")
+		return
+	}
+
+	ban := blacklist.Ban{
+		GuildId:     i.GuildID,
+		UserId:      userID,
+		Reason:      reason,
+		ModeratorId: i.Member.User.ID,
+		CreatedAt:   time.Now(),
+	}
+	if duration != "" {
+		d, err := blacklist.ParseDuration(duration)
+		if err != nil {
+			interactions.SendMessage(s, i, "", err.Error())
+			return
+		}
+		ban.ExpiresAt = ban.CreatedAt.Add(d)
+	}
+
+	if err := blacklist.Add(ban); HandleErr(err) {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("Failed to save the blacklist: %v", err))
+		return
+	}
+
+	interactions.SendMessage(s, i, "", fmt.Sprintf("<@%s> has been blacklisted %s.", userID, describeBanExpiry(ban)))
+}
+
+func removeFromBlacklist(s interactions.Session, i *discordgo.InteractionCreate, userID string) {
+	if userID == "" {
+		interactions.SendMessage(s, i, "", "Please provide a user to remove from the blacklist.")
+		return
+	}
+
+	removed, err := blacklist.Remove(i.GuildID, userID)
+	if HandleErr(err) {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("Failed to save the blacklist: %v", err))
+		return
+	}
+	if !removed {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("<@%s> isn't blacklisted.", userID))
+		return
+	}
+
+	interactions.SendMessage(s, i, "", fmt.Sprintf("<@%s> has been removed from the blacklist.", userID))
+}
+
+func describeBanExpiry(ban blacklist.Ban) string {
+	if ban.ExpiresAt.IsZero() {
+		return "permanently"
+	}
+	return fmt.Sprintf("until <t:%d:f>", ban.ExpiresAt.Unix())
+}
+
+// implements PageRenderer
+func updateBlacklistEmbed(state pagination.PaginationState, pageBans []blacklist.Ban, embed *discordgo.MessageEmbed) (*discordgo.MessageEmbed, error) {
+	embed.Title = "Blacklisted Members"
+	embed.Color = 0xe74c3c
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("Page %d of %d", state.CurrentPage+1, state.TotalPages),
+	}
+
+	embed.Fields = []*discordgo.MessageEmbedField{}
+	if len(pageBans) == 0 {
+		embed.Description = "Nobody is blacklisted in this server."
+		return embed, nil
+	}
+
+	embed.Description = ""
+	for _, ban := range pageBans {
+		reason := ban.Reason
+		if reason == "" {
+			reason = "*no reason given*"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: fmt.Sprintf("Blacklisted %s", describeBanExpiry(ban)),
+			Value: fmt.Sprintf("User: <@%s>\nReason: %s\nBy: <@%s> <t:%d:R>",
+				ban.UserId, reason, ban.ModeratorId, ban.CreatedAt.Unix()),
+		})
+	}
+	return embed, nil
+}
+
+// checkBlacklist tells the author they're blacklisted and returns true if they are.
+// the dispatchers in bot.go call it before any command, component or modal is handled
+func checkBlacklist(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate) bool {
+	ban, banned := blacklistedAuthor(message, i)
+	if !banned {
+		return false
+	}
+
+	response := "Sorry, but you are banned from using this command."
+	if ban.Reason != "" {
+		response += " Reason: " + ban.Reason
+	}
+	if !ban.ExpiresAt.IsZero() {
+		response += fmt.Sprintf(" (expires <t:%d:R>)", ban.ExpiresAt.Unix())
+	}
+	// nothing has responded to the interaction yet so it can't be edited like SendMessage does
+	if i != nil {
+		interactions.SendEphemeralMessage(session, i, response)
+	} else {
+		interactions.SendMessage(session, nil, message.ChannelID, response)
+	}
+	return true
+}
+
+// blacklistedAuthor looks up the author's ban without telling them, for autocomplete which can't reply
+func blacklistedAuthor(message *discordgo.MessageCreate, i *discordgo.InteractionCreate) (blacklist.Ban, bool) {
+	authorID, ok := interactions.GetAuthorId(message, i)
+	if !ok {
+		return blacklist.Ban{}, false
+	}
+	guildID, _ := interactions.GetGuildId(message, i)
+	return blacklist.Check(guildID, authorID)
+}
@@ -0,0 +1,207 @@
+// Package blacklist keeps track of members who are banned from using Bjorn's commands in a server.
+// Bans are per guild, have a reason and the moderator who issued them, and can expire.
+package blacklist
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+var logger = logging.For("blacklist")
+
+type Ban struct {
+	GuildId     string    `json:"guildId"`
+	UserId      string    `json:"userId"`
+	Reason      string    `json:"reason"`
+	ModeratorId string    `json:"moderatorId"`
+	CreatedAt   time.Time `json:"createdAt"`
+
+	// zero means the ban never expires
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+func (b Ban) Expired(now time.Time) bool {
+	return !b.ExpiresAt.IsZero() && !now.Before(b.ExpiresAt)
+}
+
+// the old blacklist was a file of sha256 hashed user IDs that applied everywhere,
+// those can't be turned back into IDs so they're still checked as global bans
+const legacyFile = "src/bot/data/blacklist.txt"
+
+var (
+	banStore = store.New[map[string]map[string]Ban]("blacklist")
+
+	// guild ID -> user ID -> ban
+	bans         map[string]map[string]Ban
+	legacyHashes map[string]bool
+	mu           sync.Mutex
+
+	// set when the stored bans couldn't be read, saving then would wipe every other ban
+	loadErr error
+)
+
+func load() {
+	if bans != nil {
+		return
+	}
+
+	loaded, err := banStore.Load()
+	loadErr = err
+	if err != nil {
+		logger.Error("Failed to load blacklist", "err", err)
+	}
+	if loaded == nil {
+		loaded = make(map[string]map[string]Ban)
+	}
+	bans = loaded
+	legacyHashes = loadLegacyHashes()
+}
+
+// loadForSave is load for anything that saves, it tries again after a failed load and
+// returns an error instead if the bans still can't be read
+func loadForSave() error {
+	if loadErr != nil {
+		bans = nil
+	}
+	load()
+	if loadErr != nil {
+		return fmt.Errorf("the blacklist couldn't be loaded so nothing was saved: %w", loadErr)
+	}
+	return nil
+}
+
+func loadLegacyHashes() map[string]bool {
+	hashes := make(map[string]bool)
+	file, err := os.Open(legacyFile)
+	if err != nil {
+		logger.Debug("No legacy blacklist loaded", "err", err)
+		return hashes
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			hashes[line] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Error("Failed to read legacy blacklist", "err", err)
+	}
+	return hashes
+}
+
+func hash(ID string) string {
+	hash := sha256.Sum256([]byte(ID))
+	return hex.EncodeToString(hash[:])
+}
+
+// Check returns the ban for the user in this guild if there's one that hasn't expired
+func Check(guildID, userID string) (Ban, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+
+	if legacyHashes[hash(userID)] {
+		return Ban{UserId: userID, Reason: "Banned from using Bjorn."}, true
+	}
+
+	ban, ok := bans[guildID][userID]
+	if !ok || ban.Expired(time.Now()) {
+		return Ban{}, false
+	}
+	return ban, true
+}
+
+// Add saves the ban, replacing any existing ban for the same user in the guild
+func Add(ban Ban) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if err := loadForSave(); err != nil {
+		return err
+	}
+
+	if ban.CreatedAt.IsZero() {
+		ban.CreatedAt = time.Now()
+	}
+	if bans[ban.GuildId] == nil {
+		bans[ban.GuildId] = make(map[string]Ban)
+	}
+	bans[ban.GuildId][ban.UserId] = ban
+	return banStore.Save(bans)
+}
+
+// Remove deletes the user's ban in the guild, returning false if there wasn't one
+func Remove(guildID, userID string) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if err := loadForSave(); err != nil {
+		return false, err
+	}
+
+	if _, ok := bans[guildID][userID]; !ok {
+		return false, nil
+	}
+	delete(bans[guildID], userID)
+	return true, banStore.Save(bans)
+}
+
+// List returns the guild's active bans, newest first. Expired bans are cleaned up along the way.
+func List(guildID string) []Ban {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+
+	now := time.Now()
+	result := make([]Ban, 0, len(bans[guildID]))
+	removedExpired := false
+	for userID, ban := range bans[guildID] {
+		if ban.Expired(now) {
+			delete(bans[guildID], userID)
+			removedExpired = true
+			continue
+		}
+		result = append(result, ban)
+	}
+
+	if removedExpired {
+		if err := banStore.Save(bans); err != nil {
+			logger.Error("Failed to save blacklist after removing expired bans", "err", err)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result
+}
+
+// ParseDuration is time.ParseDuration but also understands days and weeks ("3d", "2w")
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, ok := strings.CutSuffix(s, suffix); ok {
+			var n int
+			if _, err := fmt.Sscanf(number, "%d", &n); err != nil || n <= 0 || fmt.Sprint(n) != number {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q, try something like 12h, 3d or 2w", s)
+	}
+	return d, nil
+}
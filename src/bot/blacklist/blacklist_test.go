@@ -0,0 +1,45 @@
+package blacklist
+
+import (
+	"os"
+	"testing"
+
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+func TestAddRefusesToSaveAfterFailedLoad(t *testing.T) {
+	dir := store.Dir
+	store.Dir = t.TempDir()
+	bans, loadErr = nil, nil
+	t.Cleanup(func() {
+		store.Dir = dir
+		bans, loadErr = nil, nil
+	})
+
+	const broken = `{"guild": {"banned": {"userId": "banned"`
+	if err := os.WriteFile(banStore.Path(), []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Add(Ban{GuildId: "guild", UserId: "new"}); err == nil {
+		t.Fatal("Add saved even though the blacklist failed to load")
+	}
+	if _, err := Remove("guild", "banned"); err == nil {
+		t.Fatal("Remove saved even though the blacklist failed to load")
+	}
+	if data, _ := os.ReadFile(banStore.Path()); string(data) != broken {
+		t.Fatalf("file was overwritten with %s", data)
+	}
+
+	if err := os.WriteFile(banStore.Path(), []byte(`{"guild": {"banned": {"guildId": "guild", "userId": "banned"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Add(Ban{GuildId: "guild", UserId: "new"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	for _, userID := range []string{"banned", "new"} {
+		if _, banned := Check("guild", userID); !banned {
+			t.Errorf("%s isn't banned after the reload", userID)
+		}
+	}
+}
@@ -1,16 +1,22 @@
 package bot
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
-	"github.com/shuban-789/bjorn/src/bot/util"
+	"github.com/shuban-789/bjorn/src/bot/lifecycle"
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/search"
 )
 
 // This should be blank in production. However, it takes ~2 hours for commands
@@ -19,71 +25,201 @@ import (
 //	test server's ID for testing so that commands register instantly.
 var GuildId string = os.Getenv("GUILD_ID")
 
+// OwnerId is the Discord user ID of whoever runs the bot. Commands that change the bot for every
+// server, like /mech loglevel, only work for this user and nobody can run them if it's blank.
+var OwnerId string = os.Getenv("BJORN_OWNER_ID")
+
 /**
  * Returns true if there was an error, returns false otherwise.
  */
 func HandleErr(err error) bool {
 	if err != nil {
-		fmt.Println(util.Fail("Error: %v", err))
+		botLog.Error("Unexpected error", "err", err)
 		return true
 	}
 
 	return false
 }
 
-var inScopeToken string
+var (
+	botLog     = logging.For("bot")
+	commandLog = logging.For("commands")
+	trackerLog = logging.For("tracker")
+)
+
+// shutdownTimeout is how long shutdown waits for interactions and then workers before giving up on them
+const shutdownTimeout = 30 * time.Second
+
+var (
+	// the lifecycle of the connected session, handlers use it to mark interactions as in flight
+	currentLifecycle atomic.Pointer[lifecycle.Manager]
 
+	// /mech restart asks Deploy to cycle the session through here
+	restartRequests = make(chan struct{}, 1)
+
+	// commands only need registering once per process, a restart reconnects with the same ones
+	commandsRegistered bool
+)
+
+// Deploy runs the bot until it's interrupted, reconnecting whenever /mech restart asks it to
 func Deploy(token string) {
-	inScopeToken = token
+	loadTrackedEvents()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	for {
+		restart, err := run(token, stop)
+		if err != nil {
+			botLog.Error("Bot stopped", "err", err)
+			return
+		}
+		if !restart {
+			return
+		}
+		botLog.Info("Restarting bot")
+	}
+}
+
+// run connects a session and keeps it up until a signal or a restart request, returning true for a restart
+func run(token string, stop <-chan os.Signal) (bool, error) {
 	session, err := discordgo.New("Bot " + token)
-	HandleErr(err)
+	if err != nil {
+		return false, err
+	}
 
 	session.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsDirectMessages | discordgo.IntentsGuildMembers
 
 	session.AddHandler(Tree)
-	session.AddHandler(interactionCreateHandler)
+	// discordgo picks the event from the handler's exact signature, so the Session one gets wrapped
+	session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) { interactionCreateHandler(s, i) })
 	session.AddHandler(memberJoinListener)
 
-	err = session.Open()
-	defer session.Close()
-	HandleErr(err)
-	fmt.Println(util.Success("Bot is running"))
+	// set up before connecting so the first interactions already count as in flight
+	lc := lifecycle.New(context.Background())
+	currentLifecycle.Store(lc)
+	lc.OnStop(saveTrackedEventsOnStop)
+
+	if err := session.Open(); err != nil {
+		lc.Stop(shutdownTimeout)
+		return false, fmt.Errorf("cannot connect to discord: %w", err)
+	}
+	botLog.Info("Bot is running", "user", session.State.User.Username)
+
+	if !commandsRegistered {
+		registerCommands(session)
+	}
 
-	_, err = session.ApplicationCommandBulkOverwrite(session.State.Application.ID, GuildId, interactions.Commands)
+	startMatchEventUpdater(lc, session, 2*time.Second)
+	lc.Go("team fetcher", search.RunTeamFetcher)
+	lc.Go("event fetcher", search.RunEventFetcher)
+	if MetricsAddr != "" {
+		lc.Go("metrics", func(ctx context.Context) { serveMetrics(ctx, MetricsAddr) })
+	}
+
+	restart := false
+	select {
+	case <-stop:
+		botLog.Info("Shutting down bot")
+	case <-restartRequests:
+		restart = true
+	}
+
+	if err := lc.Stop(shutdownTimeout); err != nil {
+		botLog.Warn("Shutdown didn't finish cleanly", "err", err)
+	}
+	if !restart {
+		removeTestCommands(session)
+	}
+	if err := session.Close(); err != nil {
+		botLog.Error("Failed to close session", "err", err)
+	}
+	return restart, nil
+}
+
+func registerCommands(session *discordgo.Session) {
+	_, err := session.ApplicationCommandBulkOverwrite(session.State.Application.ID, GuildId, interactions.Commands)
 	if err != nil {
-		fmt.Println(util.Fail("Cannot register commands: %v", err))
+		botLog.Error("Cannot register commands", "err", err)
+		return
 	}
-	fmt.Println(util.Success("Application commands registered"))
+	commandsRegistered = true
+	botLog.Info("Application commands registered", "count", len(interactions.Commands))
 
-	// TODO delete later, temporarily print all registered command handlers for debugging
-	fmt.Println(util.Info("Registered handlers: %d", len(interactions.CommandHandlers)))
+	handlerNames := make([]string, 0, len(interactions.CommandHandlers))
 	for name := range interactions.CommandHandlers {
-		fmt.Println(util.Info("  - %s", name))
+		handlerNames = append(handlerNames, name)
 	}
+	sort.Strings(handlerNames)
+	botLog.Debug("Registered handlers", "count", len(handlerNames), "commands", handlerNames)
+}
 
-	startMatchEventUpdater(session, 2*time.Second)
+// removeTestCommands cleans up the commands on a test server (GUILD_ID) when the bot shuts down for good.
+// Global commands are left alone, they take hours to come back and the next start overwrites them anyway.
+func removeTestCommands(session *discordgo.Session) {
+	if GuildId == "" {
+		return
+	}
 
-	allCommands, err := session.ApplicationCommands(session.State.User.ID, GuildId)
-	HandleErr(err)
+	commands, err := session.ApplicationCommands(session.State.User.ID, GuildId)
+	if err != nil {
+		botLog.Error("Cannot list commands to remove", "guild", GuildId, "err", err)
+		return
+	}
+	for _, cmd := range commands {
+		if err := session.ApplicationCommandDelete(session.State.User.ID, GuildId, cmd.ID); err != nil {
+			botLog.Error("Cannot delete command", "guild", GuildId, "command", cmd.Name, "err", err)
+		}
+	}
+}
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt)
-	<-stop
-	fmt.Println(util.Info("Shutting down bot..."))
+// requestRestart has Deploy cycle the session. It can't happen in the handler that asks for it since shutdown
+// waits for that handler to finish.
+func requestRestart() {
+	select {
+	case restartRequests <- struct{}{}:
+	default:
+		// one is already on the way
+	}
+}
 
-	for _, cmd := range allCommands {
-		err := session.ApplicationCommandDelete(session.State.User.ID, "", cmd.ID)
-		if err != nil {
-			log.Fatalf("Cannot delete %q command: %v", cmd.Name, err)
-		}
+// trackInteraction marks an interaction or message as in flight, it's false when the bot is shutting down
+func trackInteraction() (done func(), ok bool) {
+	lc := currentLifecycle.Load()
+	if lc == nil {
+		return func() {}, true
 	}
+	return lc.Track()
 }
 
-func interactionCreateHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func interactionCreateHandler(s interactions.Session, i *discordgo.InteractionCreate) {
+	done, ok := trackInteraction()
+	if !ok {
+		logging.WithInteraction(commandLog, i).Debug("Dropping interaction during shutdown")
+		return
+	}
+	defer done()
+
+	// blacklisted members get told once per command, autocomplete just gets no suggestions
+	if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		if _, banned := blacklistedAuthor(nil, i); banned {
+			return
+		}
+	} else if checkBlacklist(s, nil, i) {
+		logging.WithInteraction(commandLog, i).Info("Ignoring interaction from a blacklisted member")
+		return
+	}
+
 	switch i.Type {
 	case discordgo.InteractionApplicationCommand:
-		if h, ok := interactions.CommandHandlers[i.ApplicationCommandData().Name]; ok {
+		name := i.ApplicationCommandData().Name
+		if h, ok := interactions.CommandHandlers[name]; ok {
+			started := time.Now()
 			h(s, i)
+			commandInvocations.Inc(name)
+			commandDuration.Observe(time.Since(started).Seconds(), name)
+			logging.WithInteraction(commandLog, i).Info("Handled command", "command", name, "duration", time.Since(started))
 		}
 
 	case discordgo.InteractionApplicationCommandAutocomplete:
@@ -96,11 +232,12 @@ func interactionCreateHandler(s *discordgo.Session, i *discordgo.InteractionCrea
 		interactions.HandleAutocomplete(s, i)
 
 	case discordgo.InteractionMessageComponent:
-		authorid, _ := interactions.GetAuthorName(nil, i)
-		fmt.Println(util.Info("Received component interaction: CustomID='%s', User='%s'", i.MessageComponentData().CustomID, authorid))
+		logging.WithInteraction(commandLog, i).Debug("Received component interaction", "customID", i.MessageComponentData().CustomID)
 		// NOTE: See src/bot/README.md for the format used in custom IDs
 		fields := strings.Fields(i.MessageComponentData().CustomID)
-		if h, ok := interactions.ComponentHandlers[fields[0]]; ok {
+		h, ok := interactions.ComponentHandlers[fields[0]]
+		componentDispatches.Inc(dispatchLabel(fields[0], ok))
+		if ok {
 			if len(fields) == 1 {
 				h(s, i, []string{""})
 			} else {
@@ -109,13 +246,14 @@ func interactionCreateHandler(s *discordgo.Session, i *discordgo.InteractionCrea
 		}
 
 	case discordgo.InteractionModalSubmit:
-		authorid, _ := interactions.GetAuthorName(nil, i)
-		fmt.Println(util.Info("Received modal submit interaction: CustomID='%s', User='%s'", i.ModalSubmitData().CustomID, authorid))
+		logging.WithInteraction(commandLog, i).Debug("Received modal submit interaction", "customID", i.ModalSubmitData().CustomID)
 
 		// NOTE: See src/bot/README.md for the format used in custom IDs
 		var modalData discordgo.ModalSubmitInteractionData = i.ModalSubmitData()
 		fields := strings.Fields(modalData.CustomID)
-		if h, ok := interactions.ModalHandlers[fields[0]]; ok {
+		h, ok := interactions.ModalHandlers[fields[0]]
+		modalDispatches.Inc(dispatchLabel(fields[0], ok))
+		if ok {
 			if len(fields) == 1 { // no extra data
 				h(s, i, []string{}, modalData)
 			} else {
@@ -130,24 +268,35 @@ func Tree(session *discordgo.Session, message *discordgo.MessageCreate) {
 		return
 	}
 
-	fmt.Println(util.Info("Message Details: Content='%s', Author='%s', Channel='%s'",
-		message.Content, message.Author.Username, message.ChannelID))
+	done, ok := trackInteraction()
+	if !ok {
+		return
+	}
+	defer done()
 
+	logger := logging.WithMessage(commandLog, message)
+	logger.Debug("Received message", "content", message.Content)
 	if strings.TrimSpace(message.Content) == "" {
-		fmt.Println(util.Info("Message content is empty. Ignoring."))
+		logger.Debug("Message content is empty, ignoring")
 		return
 	}
 
+	prefix := guildconfig.Get(message.GuildID).CommandPrefix
 	content := strings.TrimSpace(message.Content)
-	if after, ok := strings.CutPrefix(content, interactions.CmdPrefix); ok {
+	if after, ok := strings.CutPrefix(content, prefix); ok {
 		content = after
 		args := strings.Fields(content)
 		if len(args) == 0 {
 			return
 		}
 
+		if checkBlacklist(session, message, nil) {
+			logger.Info("Ignoring text command from a blacklisted member")
+			return
+		}
+
 		cmd := strings.ToLower(args[0])
-		fmt.Println(util.Info("Processing command: '%s' with arguments %s", cmd, args[1:]))
+		logger.Info("Processing text command", "command", cmd, "args", args[1:])
 
 		switch cmd {
 		case "help":
@@ -162,10 +311,16 @@ func Tree(session *discordgo.Session, message *discordgo.MessageCreate) {
 			matchcmd(session, message, nil, args[1:])
 		case "lead":
 			leadcmd(session, message, nil, args[1:])
+		case "event":
+			eventcmd(session, message, nil, args[1:])
+		case "watch":
+			watchcmd(session, message, nil, args[1:])
+		case "scout":
+			scoutcmd(session, message, nil, args[1:])
 		case "mech":
 			mechcmd(session, message, nil, args[1:])
 		default:
-			session.ChannelMessageSend(message.ChannelID, "Unknown command. Use `>>help` for a list of commands.")
+			session.ChannelMessageSend(message.ChannelID, fmt.Sprintf("Unknown command. Use `%shelp` for a list of commands.", prefix))
 		}
 	}
 }
@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+// the handler tests run against sessiontest and fakeData, nothing reaches Discord or the real APIs
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "bjorn-test")
+	if err != nil {
+		panic(err)
+	}
+	store.Dir = dir
+
+	// the region list is read relative to the repo root, the same as when the bot runs
+	if err := os.Chdir("../.."); err != nil {
+		panic(err)
+	}
+
+	// the team name lookups in search still go through the ftcscout client
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/teams/search" && r.URL.Query().Get("region") == "USCASD" {
+			w.Write([]byte(`[{"number": 16271, "name": "Roboknights"}, {"number": 11111, "name": "Gearheads"}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	ftcscout.Default.BaseURL = server.URL
+
+	// registered too so the caches keyed by provider name can find it again
+	provider.Register(fakeData)
+	provider.SetDefault(fakeData)
+
+	code := m.Run()
+	server.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// fakeProvider serves whatever the test put in it, keyed by "season eventCode" or "season teamNumber"
+// (teams are keyed by just the team number)
+type fakeProvider struct {
+	name        string
+	teams       map[string]*ftcscout.Team
+	events      map[string]*ftcscout.Event
+	matches     map[string][]ftcscout.Match
+	rankings    map[string][]ftcscout.EventTeam
+	teamEvents  map[string][]ftcscout.EventTeam
+	awardErrors map[string]error
+}
+
+var fakeData = &fakeProvider{
+	name:        "fake",
+	teams:       make(map[string]*ftcscout.Team),
+	events:      make(map[string]*ftcscout.Event),
+	matches:     make(map[string][]ftcscout.Match),
+	rankings:    make(map[string][]ftcscout.EventTeam),
+	teamEvents:  make(map[string][]ftcscout.EventTeam),
+	awardErrors: make(map[string]error),
+}
+
+func (f *fakeProvider) Name() string {
+	return f.name
+}
+
+func (f *fakeProvider) Team(ctx context.Context, teamNumber string) (*ftcscout.Team, error) {
+	team, ok := f.teams[teamNumber]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	return team, nil
+}
+
+func (f *fakeProvider) TeamAwards(ctx context.Context, teamNumber string) ([]ftcscout.Award, error) {
+	if err, ok := f.awardErrors[teamNumber]; ok {
+		return nil, err
+	}
+	return []ftcscout.Award{}, nil
+}
+
+func (f *fakeProvider) TeamEvents(ctx context.Context, teamNumber, season string) ([]ftcscout.EventTeam, error) {
+	return f.teamEvents[season+" "+teamNumber], nil
+}
+
+func (f *fakeProvider) Event(ctx context.Context, season, eventCode string) (*ftcscout.Event, error) {
+	event, ok := f.events[season+" "+eventCode]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	return event, nil
+}
+
+func (f *fakeProvider) EventMatches(ctx context.Context, season, eventCode string) ([]ftcscout.Match, error) {
+	matches, ok := f.matches[season+" "+eventCode]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	return matches, nil
+}
+
+func (f *fakeProvider) EventRankings(ctx context.Context, season, eventCode string) ([]ftcscout.EventTeam, error) {
+	rankings, ok := f.rankings[season+" "+eventCode]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	return rankings, nil
+}
+
+func (f *fakeProvider) EventAwards(ctx context.Context, season, eventCode string) ([]ftcscout.Award, error) {
+	return []ftcscout.Award{}, nil
+}
+
+func (f *fakeProvider) EventAlliances(ctx context.Context, season, eventCode string) ([]ftcscout.EventAlliance, error) {
+	return []ftcscout.EventAlliance{}, nil
+}
+
+// stringOptions builds the options of a slash command from name/value pairs
+func stringOptions(pairs ...string) []*discordgo.ApplicationCommandInteractionDataOption {
+	var options []*discordgo.ApplicationCommandInteractionDataOption
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		options = append(options, &discordgo.ApplicationCommandInteractionDataOption{
+			Name:  pairs[idx],
+			Type:  discordgo.ApplicationCommandOptionString,
+			Value: pairs[idx+1],
+		})
+	}
+	return options
+}
@@ -2,97 +2,201 @@ package bot
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"image"
 	"image/color"
-	"image/draw"
-	"image/png"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/bracket"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/render"
 	"github.com/shuban-789/bjorn/src/bot/search"
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/basicfont"
-	"golang.org/x/image/math/fixed"
 )
 
 type BracketTracker struct {
-	Year              string
-	EventCode         string
-	EventName         string
-	Matches           map[int]*BracketMatch
+	Year      string
+	EventCode string
+	EventName string
+
+	// how many alliances are in the playoffs, 0 means guess from the matches
+	AllianceCount int
+
 	Alliances         map[int]*Alliance
-	Champion          *Alliance
+	Games             []BracketGame
 	ProcessedMatchIDs map[int]bool
 	mu                sync.Mutex
+
+	// whether Alliances came from alliance selection instead of being guessed from the matches
+	AlliancesPublished bool
 }
 
 type Alliance struct {
-	Seed      int
-	Captain   int
-	FirstPick int
+	Seed       int
+	Captain    int
+	FirstPick  int
+	SecondPick int
 }
 
-type BracketMatch struct {
+// BracketGame is one played playoff match
+type BracketGame struct {
 	MatchID      int
 	Series       int
 	RedAlliance  *Alliance
 	BlueAlliance *Alliance
 	RedScore     int
 	BlueScore    int
-	Winner       *Alliance
-	Loser        *Alliance
-	Played       bool
 }
 
-var (
-	bracketBgColor     = color.RGBA{32, 34, 37, 255}
-	bracketLineColor   = color.RGBA{185, 187, 190, 255}
-	bracketBoxColor    = color.RGBA{64, 68, 75, 255}
-	bracketBoxBorder   = color.RGBA{114, 137, 218, 255}
-	bracketRedColor    = color.RGBA{255, 82, 82, 255}
-	bracketBlueColor   = color.RGBA{85, 170, 255, 255}
-	bracketWinnerColor = color.RGBA{67, 181, 129, 255}
-	bracketTextColor   = color.RGBA{255, 255, 255, 255}
-	bracketGrayColor   = color.RGBA{185, 187, 190, 255}
-	bracketTitleColor  = color.RGBA{255, 215, 0, 255}
-	bracketLoserColor  = color.RGBA{240, 71, 71, 255}
-)
+// BracketMatch is the state of a series in the bracket, which can take more than one game for the finals
+type BracketMatch struct {
+	MatchID      int
+	Series       int
+	RedAlliance  *Alliance
+	BlueAlliance *Alliance
+	// scores of the latest game
+	RedScore  int
+	BlueScore int
+	RedWins   int
+	BlueWins  int
+	Games     int
+	Winner    *Alliance
+	Loser     *Alliance
+	// whether the series has been decided
+	Played bool
+}
 
 var (
 	bracketTrackers = make(map[string]*BracketTracker)
 	bracketMu       sync.RWMutex
 )
 
-func GetOrCreateBracketTracker(year, eventCode string) *BracketTracker {
+// GetOrCreateBracketTracker returns the event's tracker, a new one gets the event name from dataProvider
+func GetOrCreateBracketTracker(dataProvider provider.Provider, year, eventCode string) *BracketTracker {
 	key := year + "-" + eventCode
+	bracketMu.RLock()
+	tracker, exists := bracketTrackers[key]
+	bracketMu.RUnlock()
+	if exists {
+		return tracker
+	}
+
+	// look the name up directly so this works for events in any region
+	eventName := eventCode
+	if eventData, err := search.FetchEventDataFrom(dataProvider, year, eventCode); err == nil && eventData.Name != "" {
+		eventName = eventData.Name
+	}
+
 	bracketMu.Lock()
 	defer bracketMu.Unlock()
-
 	if tracker, exists := bracketTrackers[key]; exists {
 		return tracker
 	}
 
-	// todo: hardcoding san diego for now, not ideal though
-	var eventName string = search.GetEventNameFromCode("USCASD", eventCode)
-	if eventName == "" {
-		eventName = eventCode
+	tracker = &BracketTracker{
+		Year:              year,
+		EventCode:         eventCode,
+		EventName:         eventName,
+		Alliances:         make(map[int]*Alliance),
+		ProcessedMatchIDs: make(map[int]bool),
+	}
+	bracketTrackers[key] = tracker
+	return tracker
+}
+
+// RebuildBracketTracker replays every played playoff match of the event into a fresh tracker,
+// so the bracket is right even for events nobody tracked or after a restart
+func RebuildBracketTracker(dataProvider provider.Provider, year, eventCode string) (*BracketTracker, error) {
+	matches, err := dataProvider.EventMatches(context.Background(), year, eventCode)
+	if err != nil {
+		return nil, err
 	}
 
+	// the bracket still works off the matches alone if the alliances can't be had
+	if alliances, err := dataProvider.EventAlliances(context.Background(), year, eventCode); err == nil && len(alliances) > 0 {
+		GetOrCreateBracketTracker(dataProvider, year, eventCode).SeedAlliances(alliances)
+	}
+	return rebuildBracketTrackerFromMatches(dataProvider, year, eventCode, matches), nil
+}
+
+func rebuildBracketTrackerFromMatches(dataProvider provider.Provider, year, eventCode string, matches []ftcscout.Match) *BracketTracker {
+	var playoffs []ftcscout.Match
+	for _, match := range matches {
+		if match.TournamentLevel == "DoubleElim" && match.HasBeenPlayed {
+			playoffs = append(playoffs, match)
+		}
+	}
+	sort.Slice(playoffs, func(i, j int) bool {
+		if playoffs[i].Series != playoffs[j].Series {
+			return playoffs[i].Series < playoffs[j].Series
+		}
+		return playoffs[i].ID < playoffs[j].ID
+	})
+
+	// keeps the name and anything else known about the event from the old tracker
+	old := GetOrCreateBracketTracker(dataProvider, year, eventCode)
+	old.mu.Lock()
 	tracker := &BracketTracker{
 		Year:              year,
 		EventCode:         eventCode,
-		EventName:         eventName,
-		Matches:           make(map[int]*BracketMatch),
+		EventName:         old.EventName,
+		AllianceCount:     old.AllianceCount,
 		Alliances:         make(map[int]*Alliance),
 		ProcessedMatchIDs: make(map[int]bool),
 	}
-	bracketTrackers[key] = tracker
+	// published alliances are copied so the games below get the real seeds
+	if old.AlliancesPublished {
+		tracker.AlliancesPublished = true
+		for captain, alliance := range old.Alliances {
+			copied := *alliance
+			tracker.Alliances[captain] = &copied
+		}
+	}
+	old.mu.Unlock()
+
+	for _, match := range playoffs {
+		var redTeams, blueTeams []TeamDTO
+		for _, team := range match.Teams {
+			if team.AllianceColor == "Red" {
+				redTeams = append(redTeams, team)
+			} else if team.AllianceColor == "Blue" {
+				blueTeams = append(blueTeams, team)
+			}
+		}
+		tracker.UpdateBracketWithMatch(match.ID, match.Series, redTeams, blueTeams, match.Scores.Red.Total, match.Scores.Blue.Total)
+	}
+
+	bracketMu.Lock()
+	bracketTrackers[year+"-"+eventCode] = tracker
+	bracketMu.Unlock()
 	return tracker
 }
 
+// SeedAlliances sets the alliances from alliance selection, so seeds are right before any playoff match is played
+func (bt *BracketTracker) SeedAlliances(alliances []ftcscout.EventAlliance) {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+
+	bt.Alliances = make(map[int]*Alliance)
+	for _, alliance := range alliances {
+		bt.Alliances[alliance.Captain] = &Alliance{
+			Seed:       alliance.Seed,
+			Captain:    alliance.Captain,
+			FirstPick:  alliance.FirstPick,
+			SecondPick: alliance.SecondPick,
+		}
+	}
+	if _, err := bracket.ForAlliances(len(alliances)); err == nil {
+		bt.AllianceCount = len(alliances)
+	}
+	bt.AlliancesPublished = true
+}
+
 func (bt *BracketTracker) UpdateBracketWithMatch(matchID, series int, redTeams, blueTeams []TeamDTO, redScore, blueScore int) {
 	bt.mu.Lock()
 	defer bt.mu.Unlock()
@@ -100,35 +204,127 @@ func (bt *BracketTracker) UpdateBracketWithMatch(matchID, series int, redTeams,
 		return
 	}
 
-	match, exists := bt.Matches[series]
-	if !exists {
-		match = &BracketMatch{Series: series}
-		bt.Matches[series] = match
+	bt.Games = append(bt.Games, BracketGame{
+		MatchID:      matchID,
+		Series:       series,
+		RedAlliance:  bt.findOrCreateAlliance(redTeams),
+		BlueAlliance: bt.findOrCreateAlliance(blueTeams),
+		RedScore:     redScore,
+		BlueScore:    blueScore,
+	})
+	bt.ProcessedMatchIDs[matchID] = true
+	bt.seedFromFirstRound()
+}
+
+// seedFromFirstRound works out the seeds of guessed alliances from the first round slots they played in,
+// again after every game since the format can change as more series show up. Seeds stay 0 until the
+// format is certain so an 8 alliance event isn't seeded like a 4 alliance one. The caller holds bt.mu.
+func (bt *BracketTracker) seedFromFirstRound() {
+	if bt.AlliancesPublished {
+		return
+	}
+	format, certain := bt.format()
+	if !certain {
+		return
 	}
+	for _, game := range bt.Games {
+		series, ok := format.Get(game.Series)
+		if !ok || series.Side != bracket.Upper || series.Round != 1 {
+			continue
+		}
+		game.RedAlliance.Seed = series.Red.Seed
+		game.BlueAlliance.Seed = series.Blue.Seed
+	}
+}
 
-	match.RedAlliance = bt.findOrCreateAlliance(redTeams)
-	match.BlueAlliance = bt.findOrCreateAlliance(blueTeams)
-	match.MatchID = matchID
-	match.RedScore = redScore
-	match.BlueScore = blueScore
-	match.Played = true
+// format is the bracket for this event, guessed from the games so far unless the alliance count is known.
+// certain is false while the games could still belong to more than one format. The caller holds bt.mu.
+func (bt *BracketTracker) format() (format *bracket.Format, certain bool) {
+	if bt.AllianceCount > 0 {
+		if format, err := bracket.ForAlliances(bt.AllianceCount); err == nil {
+			return format, true
+		}
+	}
 
-	if redScore > blueScore {
-		match.Winner = match.RedAlliance
-		match.Loser = match.BlueAlliance
-	} else {
-		match.Winner = match.BlueAlliance
-		match.Loser = match.RedAlliance
+	series := make([]int, 0, len(bt.Games))
+	for _, game := range bt.Games {
+		series = append(series, game.Series)
 	}
+	return bracket.Detect(len(bt.Alliances), series)
+}
 
-	bt.ProcessedMatchIDs[matchID] = true
+// results works out every series from the games played so far. The caller holds bt.mu.
+func (bt *BracketTracker) results() (*bracket.Format, map[int]*BracketMatch, *Alliance) {
+	format, _ := bt.format()
+	results := make(map[int]*BracketMatch)
 
-	// todo: make this properly handle double elim finals, currently just assumes series 9 and 10 are finals and that winner of either is champion
-	if series == 9 {
-		bt.Champion = match.Winner
-	} else if series == 10 {
-		bt.Champion = match.Winner
+	// with the seeds known the first round can be filled in before it's played
+	if bt.AlliancesPublished {
+		bySeed := make(map[int]*Alliance)
+		for _, alliance := range bt.Alliances {
+			bySeed[alliance.Seed] = alliance
+		}
+		for _, series := range format.InRound(bracket.Upper, 1) {
+			red, blue := bySeed[series.Red.Seed], bySeed[series.Blue.Seed]
+			if red != nil && blue != nil {
+				results[series.Number] = &BracketMatch{Series: series.Number, RedAlliance: red, BlueAlliance: blue}
+			}
+		}
+	}
+
+	games := append([]BracketGame(nil), bt.Games...)
+	sort.Slice(games, func(i, j int) bool { return games[i].MatchID < games[j].MatchID })
+
+	for _, game := range games {
+		series, ok := format.Get(game.Series)
+		if !ok {
+			continue
+		}
+
+		match, exists := results[series.Number]
+		if !exists {
+			match = &BracketMatch{
+				Series:       series.Number,
+				RedAlliance:  game.RedAlliance,
+				BlueAlliance: game.BlueAlliance,
+			}
+			results[series.Number] = match
+		}
+		if match.Played {
+			continue
+		}
+
+		match.MatchID = game.MatchID
+		match.RedScore = game.RedScore
+		match.BlueScore = game.BlueScore
+		match.Games++
+
+		// ties get replayed so they don't count for anyone
+		// (finals games can swap colors, so wins are counted by alliance rather than by color)
+		if game.RedScore != game.BlueScore {
+			gameWinner := game.RedAlliance
+			if game.BlueScore > game.RedScore {
+				gameWinner = game.BlueAlliance
+			}
+			if gameWinner == match.RedAlliance {
+				match.RedWins++
+			} else {
+				match.BlueWins++
+			}
+		}
+
+		if match.RedWins >= series.WinsNeeded {
+			match.Winner, match.Loser, match.Played = match.RedAlliance, match.BlueAlliance, true
+		} else if match.BlueWins >= series.WinsNeeded {
+			match.Winner, match.Loser, match.Played = match.BlueAlliance, match.RedAlliance, true
+		}
+	}
+
+	var champion *Alliance
+	if finals, ok := results[format.Finals().Number]; ok && finals.Played {
+		champion = finals.Winner
 	}
+	return format, results, champion
 }
 
 func (bt *BracketTracker) findOrCreateAlliance(teams []TeamDTO) *Alliance {
@@ -144,9 +340,18 @@ func (bt *BracketTracker) findOrCreateAlliance(teams []TeamDTO) *Alliance {
 	if alliance, exists := bt.Alliances[captain]; exists {
 		return alliance
 	}
-	seed := len(bt.Alliances) + 1
+	// the captain can sit out a match for the second pick or a backup
+	if bt.AlliancesPublished {
+		for _, alliance := range bt.Alliances {
+			for _, t := range teams {
+				if t.TeamNumber == alliance.Captain || t.TeamNumber == alliance.FirstPick || t.TeamNumber == alliance.SecondPick {
+					return alliance
+				}
+			}
+		}
+	}
+	// the seed stays 0 until seedFromFirstRound finds the alliance in the first round
 	alliance := &Alliance{
-		Seed:      seed,
 		Captain:   captain,
 		FirstPick: firstPick,
 	}
@@ -154,76 +359,53 @@ func (bt *BracketTracker) findOrCreateAlliance(teams []TeamDTO) *Alliance {
 	return alliance
 }
 
-func (bt *BracketTracker) GenerateBracketImage() (*bytes.Buffer, error) {
+// bracket images are drawn at 2x so they stay sharp when zoomed in on phones
+const bracketImageScale = 2
+
+// RenderBracket draws the bracket as it stands in the given image format and theme
+func (bt *BracketTracker) RenderBracket(imageFormat render.Format, theme render.Theme) (*bytes.Buffer, error) {
 	bt.mu.Lock()
 	defer bt.mu.Unlock()
 
-	width := 720
-	height := 340
-
-	img := image.NewRGBA(image.Rect(0, 0, width, height))
-
-	draw.Draw(img, img.Bounds(), &image.Uniform{bracketBgColor}, image.Point{}, draw.Src)
-
-	title := strings.ToUpper(bt.EventName) + " PLAYOFFS BRACKET"
-	drawTextOnBracket(img, 250, 18, title, bracketTitleColor)
-
-	drawTextOnBracket(img, 50, 45, "UPPER BRACKET", bracketGrayColor)
-	drawTextOnBracket(img, 50, 235, "LOWER BRACKET", bracketGrayColor)
-	drawTextOnBracket(img, 510, 125, "FINALS", bracketGrayColor)
-	positions := map[int]struct{ x, y int }{
-		1: {50, 55},
-		2: {50, 135},
-		3: {280, 95},
-		4: {50, 245},
-		5: {280, 245},
-		6: {510, 135},
-		7: {510, 215},
-	}
-
-	labels := map[int]string{
-		1: "Upper R1-A",
-		2: "Upper R1-B",
-		3: "Upper Final",
-		4: "Lower R1",
-		5: "Lower Final",
-		6: "Finals 1",
-		7: "Finals 2",
-	}
-
-	boxWidth := 160
-	boxHeight := 55
-
-	for series := 1; series <= 7; series++ {
-		pos := positions[series]
-		match := bt.Matches[series]
-		drawMatchBox(img, pos.x, pos.y, boxWidth, boxHeight, labels[series], match)
-	}
-
-	if bt.Champion != nil {
-		cx, cy := 510, 55
-		rect := image.Rect(cx, cy, cx+boxWidth, cy+boxHeight)
-		draw.Draw(img, rect, &image.Uniform{color.RGBA{40, 100, 60, 255}}, image.Point{}, draw.Src)
-		drawBoxBorder(img, cx, cy, boxWidth, boxHeight, bracketTitleColor, 3)
-		drawTextOnBracket(img, cx+30, cy+20, "CHAMPION", bracketTitleColor)
-		drawTextOnBracket(img, cx+20, cy+38, formatAlliance(bt.Champion), bracketWinnerColor)
-	}
-
-	drawThickLineOnBracket(img, 210, 82, 280, 122, bracketLineColor)
-	drawThickLineOnBracket(img, 210, 162, 280, 122, bracketLineColor)
-	drawThickLineOnBracket(img, 210, 272, 280, 272, bracketLineColor)
-	drawThickLineOnBracket(img, 360, 150, 360, 245, bracketLineColor)
-	drawThickLineOnBracket(img, 440, 122, 510, 162, bracketLineColor)
-	drawThickLineOnBracket(img, 440, 272, 485, 272, bracketLineColor)
-	drawThickLineOnBracket(img, 485, 272, 485, 190, bracketLineColor)
-	drawThickLineOnBracket(img, 485, 190, 510, 190, bracketLineColor)
-	var buf bytes.Buffer
-	err := png.Encode(&buf, img)
+	format, results, champion := bt.results()
+	spacing := bracket.DefaultSpacing
+	layout := format.Layout(spacing)
+
+	canvas, err := render.NewCanvas(imageFormat, layout.Width, layout.Height, bracketImageScale)
 	if err != nil {
 		return nil, err
 	}
+	canvas.Fill(theme.Background)
 
-	return &buf, nil
+	// long event names shrink to fit, then get cut off
+	title, titleSize := render.FitText(canvas, strings.ToUpper(bt.EventName)+" PLAYOFFS BRACKET", 16, 10, float64(layout.Width-2*spacing.Margin), true)
+	canvas.Text(float64(layout.Width)/2, float64(layout.TitleY), title, titleSize, true, theme.Title, render.AnchorMiddle)
+
+	for _, header := range layout.Headers {
+		canvas.Text(float64(header.X), float64(header.Y), header.Text, 11, true, theme.Muted, render.AnchorStart)
+	}
+
+	for _, line := range layout.Lines {
+		canvas.Line(float64(line.X1), float64(line.Y1), float64(line.X2), float64(line.Y2), theme.Line, 2)
+	}
+
+	for _, series := range format.Series {
+		drawMatchBox(canvas, theme, layout.Boxes[series.Number], series, results[series.Number])
+	}
+
+	box := layout.Champion
+	x, y, w, h := float64(box.X), float64(box.Y), float64(box.W), float64(box.H)
+	if champion != nil {
+		canvas.Rect(x, y, w, h, theme.Highlight, theme.Title, 3)
+		canvas.Text(x+w/2, y+22, "CHAMPION", 12, true, theme.Title, render.AnchorMiddle)
+		canvas.Text(x+w/2, y+41, formatAlliance(champion), 12, true, theme.Winner, render.AnchorMiddle)
+	} else {
+		canvas.Rect(x, y, w, h, theme.Box, theme.BoxBorder, 2)
+		canvas.Text(x+w/2, y+22, "CHAMPION", 12, true, theme.Muted, render.AnchorMiddle)
+		canvas.Text(x+w/2, y+41, "TBD", 12, false, theme.Muted, render.AnchorMiddle)
+	}
+
+	return render.Encode(canvas)
 }
 
 func formatAlliance(a *Alliance) string {
@@ -233,15 +415,20 @@ func formatAlliance(a *Alliance) string {
 	return fmt.Sprintf("%d & %d", a.Captain, a.FirstPick)
 }
 
-func drawMatchBox(img *image.RGBA, x, y, w, h int, label string, match *BracketMatch) {
-	rect := image.Rect(x, y, x+w, y+h)
-	draw.Draw(img, rect, &image.Uniform{bracketBoxColor}, image.Point{}, draw.Src)
-	drawBoxBorder(img, x, y, w, h, bracketBoxBorder, 2)
-	drawTextOnBracket(img, x+5, y+14, label, bracketGrayColor)
+func drawMatchBox(canvas render.Canvas, theme render.Theme, box bracket.Rect, series bracket.Series, match *BracketMatch) {
+	x, y, w, h := float64(box.X), float64(box.Y), float64(box.W), float64(box.H)
+	canvas.Rect(x, y, w, h, theme.Box, theme.BoxBorder, 2)
+	canvas.Text(x+6, y+15, series.Label, 10, true, theme.Muted, render.AnchorStart)
+
+	drawLine := func(lineY float64, text string, col color.Color, bold bool) {
+		text, size := render.FitText(canvas, text, 12, 8, w-12, bold)
+		canvas.Text(x+6, lineY, text, size, bold, col, render.AnchorStart)
+	}
 
 	if match == nil || !match.Played {
-		redText := "TBD"
-		blueText := "TBD"
+		// say where the alliances come from until they're known
+		redText := series.Red.String()
+		blueText := series.Blue.String()
 		if match != nil {
 			if match.RedAlliance != nil {
 				redText = formatAlliance(match.RedAlliance)
@@ -249,145 +436,111 @@ func drawMatchBox(img *image.RGBA, x, y, w, h int, label string, match *BracketM
 			if match.BlueAlliance != nil {
 				blueText = formatAlliance(match.BlueAlliance)
 			}
+			// finals in progress show the game count so far
+			if match.Games > 0 {
+				redText = fmt.Sprintf("%s [%d]", redText, match.RedWins)
+				blueText = fmt.Sprintf("%s [%d]", blueText, match.BlueWins)
+			}
 		}
-		drawTextOnBracket(img, x+5, y+30, redText, bracketRedColor)
-		drawTextOnBracket(img, x+5, y+46, blueText, bracketBlueColor)
+		drawLine(y+32, redText, theme.Red, false)
+		drawLine(y+48, blueText, theme.Blue, false)
 		return
 	}
 
 	redText := fmt.Sprintf("%s (%d)", formatAlliance(match.RedAlliance), match.RedScore)
 	blueText := fmt.Sprintf("%s (%d)", formatAlliance(match.BlueAlliance), match.BlueScore)
+	if series.WinsNeeded > 1 {
+		redText = fmt.Sprintf("%s [%d]", formatAlliance(match.RedAlliance), match.RedWins)
+		blueText = fmt.Sprintf("%s [%d]", formatAlliance(match.BlueAlliance), match.BlueWins)
+	}
 
-	redColor := bracketLoserColor
-	blueColor := bracketLoserColor
-	if match.Winner == match.RedAlliance {
+	redColor := theme.Loser
+	blueColor := theme.Loser
+	redWon := match.Winner == match.RedAlliance
+	if redWon {
 		redText = "W " + redText
-		redColor = bracketWinnerColor
+		redColor = theme.Winner
 		blueText = "L " + blueText
 	} else {
 		blueText = "W " + blueText
-		blueColor = bracketWinnerColor
+		blueColor = theme.Winner
 		redText = "L " + redText
 	}
 
-	drawTextOnBracket(img, x+5, y+30, redText, redColor)
-	drawTextOnBracket(img, x+5, y+46, blueText, blueColor)
-}
-
-func drawBoxBorder(img *image.RGBA, x, y, w, h int, col color.Color, thickness int) {
-	for i := 0; i < thickness; i++ {
-		drawLineOnBracket(img, x+i, y+i, x+w-i, y+i, col)
-		drawLineOnBracket(img, x+i, y+h-i, x+w-i, y+h-i, col)
-		drawLineOnBracket(img, x+i, y+i, x+i, y+h-i, col)
-		drawLineOnBracket(img, x+w-i, y+i, x+w-i, y+h-i, col)
-	}
+	drawLine(y+32, redText, redColor, redWon)
+	drawLine(y+48, blueText, blueColor, !redWon)
 }
 
-func drawTextOnBracket(img *image.RGBA, x, y int, text string, col color.Color) {
-	point := fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)}
-	face := basicfont.Face7x13
-	d := &font.Drawer{
-		Dst:  img,
-		Src:  image.NewUniform(col),
-		Face: face,
-		Dot:  point,
-	}
-	d.DrawString(text)
-}
+func handleBracketCommand(session interactions.Session, i *discordgo.InteractionCreate, year, eventCode, themeName, formatName string) {
+	channelID := i.ChannelID
 
-func drawLineOnBracket(img *image.RGBA, x1, y1, x2, y2 int, col color.Color) {
-	dx := absB(x2 - x1)
-	dy := absB(y2 - y1)
-	sx := 1
-	if x1 > x2 {
-		sx = -1
+	tracker, err := RebuildBracketTracker(guildconfig.Provider(i.GuildID), year, eventCode)
+	if provider.IsNotFound(err) {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Couldn't find event %s in %s.", eventCode, year))
+		return
 	}
-	sy := 1
-	if y1 > y2 {
-		sy = -1
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to fetch playoff matches: %v", err))
+		return
 	}
-	err := dx - dy
 
-	for {
-		img.Set(x1, y1, col)
-		if x1 == x2 && y1 == y2 {
-			break
-		}
-		e2 := err * 2
-		if e2 > -dy {
-			err -= dy
-			x1 += sx
-		}
-		if e2 < dx {
-			err += dx
-			y1 += sy
-		}
+	imageFormat, err := render.ParseFormat(formatName)
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, err.Error())
+		return
 	}
-}
-
-func drawThickLineOnBracket(img *image.RGBA, x1, y1, x2, y2 int, col color.Color) {
-	drawLineOnBracket(img, x1, y1, x2, y2, col)
-	drawLineOnBracket(img, x1, y1+1, x2, y2+1, col)
-	drawLineOnBracket(img, x1, y1-1, x2, y2-1, col)
-}
-
-func absB(x int) int {
-	if x < 0 {
-		return -x
+	theme := guildconfig.Theme(i.GuildID)
+	if themeName != "" {
+		theme = render.GetTheme(themeName)
 	}
-	return x
-}
-
-func handleBracketCommand(session *discordgo.Session, i *discordgo.InteractionCreate, year, eventCode string) {
-	channelID := i.ChannelID
 
-	tracker := GetOrCreateBracketTracker(year, eventCode)
-
-	imgBuf, err := tracker.GenerateBracketImage()
+	imgBuf, err := tracker.RenderBracket(imageFormat, theme)
 	if err != nil {
 		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to generate bracket: %v", err))
 		return
 	}
 
-	playedCount := 0
 	tracker.mu.Lock()
-	for _, m := range tracker.Matches {
-		if m.Played {
-			playedCount++
-		}
-	}
-	champion := tracker.Champion
+	format, results, champion := tracker.results()
 	tracker.mu.Unlock()
 
+	playedCount := 0
+	for _, m := range results {
+		playedCount += m.Games
+	}
+
 	var description string
 	if playedCount == 0 {
-		description = "*No playoff matches have been recorded yet.*\n\n" +
-			"Start event tracking with `/match eventstart` or `/match track` " +
-			"and the bracket will update automatically as playoff matches are played."
+		description = "*No playoff matches have been played yet.*\n\n" +
+			"Check back once playoffs start, or track the event with `/match track` " +
+			"to get the bracket posted as playoff matches are played."
 	} else {
-		description = fmt.Sprintf("**%d playoff match(es) completed**\n\n", playedCount)
+		description = fmt.Sprintf("**%d playoff match(es) completed** (%d alliance bracket)\n\n", playedCount, format.Alliances)
 		if champion != nil {
 			description += fmt.Sprintf("🏆 **Champion: %s**", formatAlliance(champion))
 		}
 	}
 
+	fileName := "bracket" + imageFormat.Extension()
 	embed := &discordgo.MessageEmbed{
 		Title:       fmt.Sprintf("🏆 %s Playoffs Bracket", eventCode),
 		Description: description,
 		Color:       0x7289DA,
-		Image: &discordgo.MessageEmbedImage{
-			URL: "attachment://bracket.png",
-		},
 	}
+	// discord doesn't preview svgs so those just go along as a file
+	if imageFormat == render.PNG {
+		embed.Image = &discordgo.MessageEmbedImage{
+			URL: "attachment://" + fileName,
+		}
+	}
+	image := interactions.Attachment{Name: fileName, ContentType: imageFormat.ContentType(), Data: imgBuf.Bytes()}
+	_ = interactions.EditOrSend(session, i, channelID, "", []*discordgo.MessageEmbed{embed}, image)
+}
 
-	_, err = session.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-		Embeds: &[]*discordgo.MessageEmbed{embed},
-		Files:  []*discordgo.File{{Name: "bracket.png", Reader: imgBuf}},
-	})
-	if err != nil {
-		_, _ = session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
-			Embed: embed,
-			Files: []*discordgo.File{{Name: "bracket.png", Reader: imgBuf}},
-		})
+func themeChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	for _, name := range render.ThemeNames() {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
 	}
+	return choices
 }
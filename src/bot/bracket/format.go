@@ -0,0 +1,235 @@
+// Package bracket describes FTC double elimination playoff brackets: which series exist,
+// where each alliance in them comes from, and where everything goes when it's drawn.
+// Series numbers follow the FTC game manual, which is also what the APIs report as a match's series.
+package bracket
+
+import (
+	"fmt"
+	"sort"
+)
+
+type Side int
+
+const (
+	Upper Side = iota
+	Lower
+	Finals
+)
+
+func (s Side) String() string {
+	switch s {
+	case Upper:
+		return "Upper"
+	case Lower:
+		return "Lower"
+	default:
+		return "Finals"
+	}
+}
+
+// Source is where the alliance in one slot of a series comes from,
+// either a seed in the first round or the winner/loser of an earlier series
+type Source struct {
+	Seed   int
+	Series int
+	Winner bool
+}
+
+func seed(n int) Source       { return Source{Seed: n} }
+func winnerOf(n int) Source   { return Source{Series: n, Winner: true} }
+func loserOf(n int) Source    { return Source{Series: n} }
+func (s Source) IsSeed() bool { return s.Seed > 0 }
+
+// String is what gets shown before the alliance is known, e.g. "Alliance 1" or "Winner M3"
+func (s Source) String() string {
+	if s.IsSeed() {
+		return fmt.Sprintf("Alliance %d", s.Seed)
+	}
+	if s.Winner {
+		return fmt.Sprintf("Winner M%d", s.Series)
+	}
+	return fmt.Sprintf("Loser M%d", s.Series)
+}
+
+type Series struct {
+	Number int
+	Side   Side
+	// 1 based round within the side
+	Round int
+	Label string
+	Red   Source
+	Blue  Source
+	// how many games it takes to win the series, only the finals go past 1
+	WinsNeeded int
+}
+
+type Format struct {
+	Alliances int
+	// ordered by series number
+	Series []Series
+}
+
+// Supported lists the alliance counts there is a format for
+var Supported = []int{4, 8}
+
+type seriesDef struct {
+	side      Side
+	round     int
+	red, blue Source
+}
+
+// straight from the game manual, red is the higher seed / the upper bracket side
+var definitions = map[int][]seriesDef{
+	4: {
+		{Upper, 1, seed(1), seed(4)},
+		{Upper, 1, seed(2), seed(3)},
+		{Lower, 1, loserOf(1), loserOf(2)},
+		{Upper, 2, winnerOf(1), winnerOf(2)},
+		{Lower, 2, loserOf(4), winnerOf(3)},
+		{Finals, 1, winnerOf(4), winnerOf(5)},
+	},
+	8: {
+		{Upper, 1, seed(1), seed(8)},
+		{Upper, 1, seed(4), seed(5)},
+		{Upper, 1, seed(2), seed(7)},
+		{Upper, 1, seed(3), seed(6)},
+		{Lower, 1, loserOf(1), loserOf(2)},
+		{Lower, 1, loserOf(3), loserOf(4)},
+		{Upper, 2, winnerOf(1), winnerOf(2)},
+		{Upper, 2, winnerOf(3), winnerOf(4)},
+		// losers drop in crossed over so alliances don't get an immediate rematch
+		{Lower, 2, loserOf(7), winnerOf(6)},
+		{Lower, 2, loserOf(8), winnerOf(5)},
+		{Lower, 3, winnerOf(9), winnerOf(10)},
+		{Upper, 3, winnerOf(7), winnerOf(8)},
+		{Lower, 4, loserOf(12), winnerOf(11)},
+		{Finals, 1, winnerOf(12), winnerOf(13)},
+	},
+}
+
+// ForAlliances builds the bracket for a playoff with this many alliances
+func ForAlliances(alliances int) (*Format, error) {
+	defs, ok := definitions[alliances]
+	if !ok {
+		return nil, fmt.Errorf("%d alliance playoffs aren't supported (supported: %v)", alliances, Supported)
+	}
+
+	format := &Format{Alliances: alliances}
+	for i, def := range defs {
+		format.Series = append(format.Series, Series{
+			Number:     i + 1,
+			Side:       def.side,
+			Round:      def.round,
+			Red:        def.red,
+			Blue:       def.blue,
+			WinsNeeded: 1,
+		})
+	}
+
+	// label after everything exists since labels depend on how many series share a round
+	for i := range format.Series {
+		series := &format.Series[i]
+		if series.Side == Finals {
+			series.WinsNeeded = 2
+		}
+		series.Label = format.label(*series)
+	}
+	return format, nil
+}
+
+// Detect picks the smallest supported format that fits the alliances and series seen in the match data so far.
+// Early on more than one format can fit, e.g. the first two series of an 8 alliance playoff look just like
+// the first round of a 4 alliance one, so certain is false until the data rules the others out.
+func Detect(allianceCount int, series []int) (format *Format, certain bool) {
+	var fits []*Format
+	for _, alliances := range Supported {
+		candidate, _ := ForAlliances(alliances)
+		if candidate.fits(allianceCount, series) {
+			fits = append(fits, candidate)
+		}
+	}
+	if len(fits) == 0 {
+		format, _ := ForAlliances(Supported[len(Supported)-1])
+		return format, false
+	}
+	return fits[0], len(fits) == 1
+}
+
+// fits is whether the format could have produced these alliances and series
+func (f *Format) fits(allianceCount int, series []int) bool {
+	if allianceCount > f.Alliances {
+		return false
+	}
+
+	firstRound := len(f.InRound(Upper, 1))
+	seenFirstRound := make(map[int]bool)
+	for _, number := range series {
+		// extra finals games can come back as their own series right after the finals
+		if number > len(f.Series)+2 {
+			return false
+		}
+		if number <= firstRound {
+			seenFirstRound[number] = true
+		}
+	}
+	// every first round series brings two alliances nobody has seen yet
+	return allianceCount >= 2*len(seenFirstRound)
+}
+
+// Get returns the series with this number. Numbers past the finals are extra finals games and give the finals.
+func (f *Format) Get(number int) (Series, bool) {
+	if number < 1 {
+		return Series{}, false
+	}
+	if number > len(f.Series) {
+		return f.Finals(), true
+	}
+	return f.Series[number-1], true
+}
+
+func (f *Format) Finals() Series {
+	return f.Series[len(f.Series)-1]
+}
+
+// Rounds is how many rounds a side of the bracket has
+func (f *Format) Rounds(side Side) int {
+	rounds := 0
+	for _, series := range f.Series {
+		if series.Side == side && series.Round > rounds {
+			rounds = series.Round
+		}
+	}
+	return rounds
+}
+
+// InRound returns the series in a round of a side, ordered by series number
+func (f *Format) InRound(side Side, round int) []Series {
+	var result []Series
+	for _, series := range f.Series {
+		if series.Side == side && series.Round == round {
+			result = append(result, series)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Number < result[j].Number })
+	return result
+}
+
+func (f *Format) label(series Series) string {
+	if series.Side == Finals {
+		return fmt.Sprintf("M%d Finals", series.Number)
+	}
+	if series.Round == f.Rounds(series.Side) {
+		return fmt.Sprintf("M%d %s Final", series.Number, series.Side)
+	}
+
+	label := fmt.Sprintf("M%d %s R%d", series.Number, series.Side, series.Round)
+	inRound := f.InRound(series.Side, series.Round)
+	if len(inRound) > 1 {
+		for i, other := range inRound {
+			if other.Number == series.Number {
+				label += fmt.Sprintf("-%c", 'A'+i)
+			}
+		}
+	}
+	return label
+}
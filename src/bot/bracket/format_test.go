@@ -0,0 +1,157 @@
+package bracket
+
+import (
+	"fmt"
+	"testing"
+)
+
+// describe sums up a series the way the game manual's bracket diagrams do
+func describe(series Series) string {
+	return fmt.Sprintf("%s: %s v %s, %d to win", series.Label, series.Red, series.Blue, series.WinsNeeded)
+}
+
+func TestForAlliances(t *testing.T) {
+	tests := map[int][]string{
+		4: {
+			"M1 Upper R1-A: Alliance 1 v Alliance 4, 1 to win",
+			"M2 Upper R1-B: Alliance 2 v Alliance 3, 1 to win",
+			"M3 Lower R1: Loser M1 v Loser M2, 1 to win",
+			"M4 Upper Final: Winner M1 v Winner M2, 1 to win",
+			"M5 Lower Final: Loser M4 v Winner M3, 1 to win",
+			"M6 Finals: Winner M4 v Winner M5, 2 to win",
+		},
+		8: {
+			"M1 Upper R1-A: Alliance 1 v Alliance 8, 1 to win",
+			"M2 Upper R1-B: Alliance 4 v Alliance 5, 1 to win",
+			"M3 Upper R1-C: Alliance 2 v Alliance 7, 1 to win",
+			"M4 Upper R1-D: Alliance 3 v Alliance 6, 1 to win",
+			"M5 Lower R1-A: Loser M1 v Loser M2, 1 to win",
+			"M6 Lower R1-B: Loser M3 v Loser M4, 1 to win",
+			"M7 Upper R2-A: Winner M1 v Winner M2, 1 to win",
+			"M8 Upper R2-B: Winner M3 v Winner M4, 1 to win",
+			"M9 Lower R2-A: Loser M7 v Winner M6, 1 to win",
+			"M10 Lower R2-B: Loser M8 v Winner M5, 1 to win",
+			"M11 Lower R3: Winner M9 v Winner M10, 1 to win",
+			"M12 Upper Final: Winner M7 v Winner M8, 1 to win",
+			"M13 Lower Final: Loser M12 v Winner M11, 1 to win",
+			"M14 Finals: Winner M12 v Winner M13, 2 to win",
+		},
+	}
+
+	for alliances, want := range tests {
+		format, err := ForAlliances(alliances)
+		if err != nil {
+			t.Fatalf("ForAlliances(%d): %v", alliances, err)
+		}
+		if format.Alliances != alliances || len(format.Series) != len(want) {
+			t.Fatalf("%d alliances: got %d alliances and %d series, want %d series", alliances, format.Alliances, len(format.Series), len(want))
+		}
+		for idx, series := range format.Series {
+			if series.Number != idx+1 {
+				t.Errorf("%d alliances: series %d is numbered %d", alliances, idx+1, series.Number)
+			}
+			if got := describe(series); got != want[idx] {
+				t.Errorf("%d alliances: got %q, want %q", alliances, got, want[idx])
+			}
+		}
+	}
+
+	if _, err := ForAlliances(6); err == nil {
+		t.Error("ForAlliances(6) should fail, there's no 6 alliance format")
+	}
+}
+
+// every alliance has to be able to get to the finals and be knocked out exactly twice
+func TestFormatsAreConsistent(t *testing.T) {
+	for _, alliances := range Supported {
+		format, _ := ForAlliances(alliances)
+
+		seeds := make(map[int]int)
+		winnerUsed := make(map[int]int)
+		loserUsed := make(map[int]int)
+		for _, series := range format.Series {
+			for _, source := range []Source{series.Red, series.Blue} {
+				switch {
+				case source.IsSeed():
+					seeds[source.Seed]++
+				case source.Series >= series.Number:
+					t.Errorf("%d alliances: %s is fed by a later series", alliances, series.Label)
+				case source.Winner:
+					winnerUsed[source.Series]++
+				default:
+					loserUsed[source.Series]++
+					if feeder, _ := format.Get(source.Series); feeder.Side != Upper {
+						t.Errorf("%d alliances: %s takes the loser of %s, who should be out", alliances, series.Label, feeder.Label)
+					}
+				}
+			}
+		}
+
+		for seed := 1; seed <= alliances; seed++ {
+			if seeds[seed] != 1 {
+				t.Errorf("%d alliances: seed %d starts in %d series, want 1", alliances, seed, seeds[seed])
+			}
+		}
+		for _, series := range format.Series {
+			if series.Side == Finals {
+				continue
+			}
+			if winnerUsed[series.Number] != 1 {
+				t.Errorf("%d alliances: the winner of %s goes to %d series, want 1", alliances, series.Label, winnerUsed[series.Number])
+			}
+			wantLoser := 0
+			if series.Side == Upper {
+				wantLoser = 1
+			}
+			if loserUsed[series.Number] != wantLoser {
+				t.Errorf("%d alliances: the loser of %s goes to %d series, want %d", alliances, series.Label, loserUsed[series.Number], wantLoser)
+			}
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	format, _ := ForAlliances(4)
+
+	for number := 1; number <= 6; number++ {
+		if series, ok := format.Get(number); !ok || series.Number != number {
+			t.Errorf("Get(%d) = %d, %v", number, series.Number, ok)
+		}
+	}
+	// the APIs can number extra finals games as the series after the finals
+	for _, number := range []int{7, 8} {
+		if series, ok := format.Get(number); !ok || series.Number != 6 || series.Side != Finals {
+			t.Errorf("Get(%d) = %s, %v, want the finals", number, series.Label, ok)
+		}
+	}
+	if _, ok := format.Get(0); ok {
+		t.Error("Get(0) should not find a series")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name          string
+		alliances     int
+		series        []int
+		wantAlliances int
+		wantCertain   bool
+	}{
+		{name: "nothing played", wantAlliances: 4},
+		{name: "one series", alliances: 2, series: []int{1}, wantAlliances: 4},
+		{name: "first two series could be either", alliances: 4, series: []int{1, 2}, wantAlliances: 4},
+		{name: "second series first", alliances: 2, series: []int{2}, wantAlliances: 4},
+		{name: "4 alliance lower bracket", alliances: 4, series: []int{1, 2, 3}, wantAlliances: 4, wantCertain: true},
+		{name: "new alliances in series 3", alliances: 6, series: []int{1, 2, 3}, wantAlliances: 8, wantCertain: true},
+		{name: "4 alliance extra finals", alliances: 4, series: []int{1, 2, 3, 4, 5, 6, 7, 8}, wantAlliances: 4, wantCertain: true},
+		{name: "past the 4 alliance finals", alliances: 4, series: []int{9}, wantAlliances: 8, wantCertain: true},
+		{name: "more alliances than any format", alliances: 10, series: []int{1}, wantAlliances: 8},
+	}
+
+	for _, tt := range tests {
+		format, certain := Detect(tt.alliances, tt.series)
+		if format.Alliances != tt.wantAlliances || certain != tt.wantCertain {
+			t.Errorf("%s: got %d alliances, certain %v, want %d, %v", tt.name, format.Alliances, certain, tt.wantAlliances, tt.wantCertain)
+		}
+	}
+}
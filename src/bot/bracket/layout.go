@@ -0,0 +1,156 @@
+package bracket
+
+type Spacing struct {
+	BoxWidth     int
+	BoxHeight    int
+	ColumnGap    int
+	RowGap       int
+	Margin       int
+	TitleHeight  int
+	HeaderHeight int
+}
+
+var DefaultSpacing = Spacing{
+	BoxWidth:     160,
+	BoxHeight:    55,
+	ColumnGap:    40,
+	RowGap:       25,
+	Margin:       20,
+	TitleHeight:  25,
+	HeaderHeight: 20,
+}
+
+type Rect struct {
+	X, Y, W, H int
+}
+
+func (r Rect) left() (int, int)  { return r.X, r.Y + r.H/2 }
+func (r Rect) right() (int, int) { return r.X + r.W, r.Y + r.H/2 }
+
+type Line struct {
+	X1, Y1, X2, Y2 int
+}
+
+// Text is a label at a position, Y is the baseline
+type Text struct {
+	X, Y int
+	Text string
+}
+
+// Layout is where every part of a bracket goes, renderers just draw what's in here
+type Layout struct {
+	Width  int
+	Height int
+
+	// baseline of the title, which should be centered horizontally
+	TitleY int
+
+	// box for each series, by series number
+	Boxes    map[int]Rect
+	Champion Rect
+	Lines    []Line
+	Headers  []Text
+}
+
+// Layout places every series in columns by round, upper bracket on top and lower bracket below,
+// with later rounds centered on the series that feed into them
+func (f *Format) Layout(spacing Spacing) Layout {
+	layout := Layout{
+		Boxes:  make(map[int]Rect),
+		TitleY: spacing.Margin + spacing.TitleHeight/2 + 5,
+	}
+
+	columnX := func(column int) int {
+		return spacing.Margin + column*(spacing.BoxWidth+spacing.ColumnGap)
+	}
+	box := func(column, y int) Rect {
+		return Rect{X: columnX(column), Y: y, W: spacing.BoxWidth, H: spacing.BoxHeight}
+	}
+	rowStep := spacing.BoxHeight + spacing.RowGap
+
+	// the y of a series is the average of the series on the same side whose winners feed into it
+	placeSide := func(side Side, top int) (bottom int) {
+		bottom = top
+		for round := 1; round <= f.Rounds(side); round++ {
+			stacked := 0
+			for _, series := range f.InRound(side, round) {
+				y, feeders := 0, 0
+				for _, source := range []Source{series.Red, series.Blue} {
+					if source.IsSeed() || !source.Winner {
+						continue
+					}
+					feeder, _ := f.Get(source.Series)
+					if feeder.Side != side {
+						continue
+					}
+					y += layout.Boxes[source.Series].Y
+					feeders++
+				}
+
+				if feeders > 0 {
+					y /= feeders
+				} else {
+					y = top + stacked*rowStep
+					stacked++
+				}
+
+				rect := box(round-1, y)
+				layout.Boxes[series.Number] = rect
+				bottom = max(bottom, rect.Y+rect.H)
+			}
+		}
+		return bottom
+	}
+
+	upperTop := spacing.Margin + spacing.TitleHeight + spacing.HeaderHeight
+	upperBottom := placeSide(Upper, upperTop)
+	lowerTop := upperBottom + spacing.RowGap + spacing.HeaderHeight
+	lowerBottom := placeSide(Lower, lowerTop)
+
+	finalsColumn := max(f.Rounds(Upper), f.Rounds(Lower))
+	finals := f.Finals()
+	upperFinal := layout.Boxes[finals.Red.Series]
+	lowerFinal := layout.Boxes[finals.Blue.Series]
+	finalsBox := box(finalsColumn, (upperFinal.Y+lowerFinal.Y)/2)
+	layout.Boxes[finals.Number] = finalsBox
+	layout.Champion = box(finalsColumn+1, finalsBox.Y)
+
+	layout.Headers = []Text{
+		{X: spacing.Margin, Y: upperTop - 8, Text: "UPPER BRACKET"},
+		{X: spacing.Margin, Y: lowerTop - 8, Text: "LOWER BRACKET"},
+		{X: finalsBox.X, Y: finalsBox.Y - 8, Text: "FINALS"},
+	}
+
+	// connect each series to the series its winner moves on to, losers dropping down aren't drawn
+	// since the box already says where they came from
+	for _, series := range f.Series {
+		target := layout.Boxes[series.Number]
+		for _, source := range []Source{series.Red, series.Blue} {
+			if source.IsSeed() || !source.Winner {
+				continue
+			}
+			layout.Lines = append(layout.Lines, elbow(layout.Boxes[source.Series], target, spacing.ColumnGap)...)
+		}
+	}
+	layout.Lines = append(layout.Lines, elbow(finalsBox, layout.Champion, spacing.ColumnGap)...)
+
+	layout.Width = layout.Champion.X + layout.Champion.W + spacing.Margin
+	layout.Height = lowerBottom + spacing.Margin
+	return layout
+}
+
+// elbow connects the right side of from to the left side of to, turning halfway through the gap before to
+func elbow(from, to Rect, columnGap int) []Line {
+	x1, y1 := from.right()
+	x2, y2 := to.left()
+	if y1 == y2 {
+		return []Line{{x1, y1, x2, y2}}
+	}
+
+	turnX := x2 - columnGap/2
+	return []Line{
+		{x1, y1, turnX, y1},
+		{turnX, y1, turnX, y2},
+		{turnX, y2, x2, y2},
+	}
+}
@@ -0,0 +1,103 @@
+package bracket
+
+import (
+	"testing"
+)
+
+func TestLayoutFourAlliances(t *testing.T) {
+	format, _ := ForAlliances(4)
+	layout := format.Layout(DefaultSpacing)
+
+	// columns are 200 apart starting at the 20 margin, rows are 80 apart,
+	// the upper bracket starts under the title and header at 65 and the lower one under its header at 245
+	wantBoxes := map[int]Rect{
+		1: {X: 20, Y: 65, W: 160, H: 55},
+		2: {X: 20, Y: 145, W: 160, H: 55},
+		4: {X: 220, Y: 105, W: 160, H: 55},
+		3: {X: 20, Y: 245, W: 160, H: 55},
+		5: {X: 220, Y: 245, W: 160, H: 55},
+		6: {X: 420, Y: 175, W: 160, H: 55},
+	}
+	if len(layout.Boxes) != len(wantBoxes) {
+		t.Errorf("%d boxes, want %d", len(layout.Boxes), len(wantBoxes))
+	}
+	for number, want := range wantBoxes {
+		if got := layout.Boxes[number]; got != want {
+			t.Errorf("series %d box = %+v, want %+v", number, got, want)
+		}
+	}
+
+	if want := (Rect{X: 620, Y: 175, W: 160, H: 55}); layout.Champion != want {
+		t.Errorf("champion box = %+v, want %+v", layout.Champion, want)
+	}
+	if layout.Width != 800 || layout.Height != 320 || layout.TitleY != 37 {
+		t.Errorf("size %dx%d with the title at %d, want 800x320 at 37", layout.Width, layout.Height, layout.TitleY)
+	}
+
+	wantHeaders := []Text{
+		{X: 20, Y: 57, Text: "UPPER BRACKET"},
+		{X: 20, Y: 237, Text: "LOWER BRACKET"},
+		{X: 420, Y: 167, Text: "FINALS"},
+	}
+	for idx, want := range wantHeaders {
+		if idx >= len(layout.Headers) || layout.Headers[idx] != want {
+			t.Errorf("headers = %+v, want %+v", layout.Headers, wantHeaders)
+			break
+		}
+	}
+
+	// M1 -> M4 turns halfway through the gap, M3 -> M5 is level so it's a single line
+	wantLines := []Line{
+		{180, 92, 200, 92}, {200, 92, 200, 132}, {200, 132, 220, 132},
+		{180, 172, 200, 172}, {200, 172, 200, 132}, {200, 132, 220, 132},
+		{180, 272, 220, 272},
+		{380, 132, 400, 132}, {400, 132, 400, 202}, {400, 202, 420, 202},
+		{380, 272, 400, 272}, {400, 272, 400, 202}, {400, 202, 420, 202},
+		{580, 202, 620, 202},
+	}
+	if len(layout.Lines) != len(wantLines) {
+		t.Fatalf("lines = %v, want %v", layout.Lines, wantLines)
+	}
+	for idx, want := range wantLines {
+		if layout.Lines[idx] != want {
+			t.Errorf("line %d = %v, want %v", idx, layout.Lines[idx], want)
+		}
+	}
+}
+
+func TestLayoutEightAlliances(t *testing.T) {
+	format, _ := ForAlliances(8)
+	layout := format.Layout(DefaultSpacing)
+
+	if len(layout.Boxes) != len(format.Series) {
+		t.Fatalf("%d boxes for %d series", len(layout.Boxes), len(format.Series))
+	}
+	for _, series := range format.Series {
+		box := layout.Boxes[series.Number]
+		if box.X+box.W > layout.Width || box.Y+box.H > layout.Height {
+			t.Errorf("%s at %+v is outside the %dx%d image", series.Label, box, layout.Width, layout.Height)
+		}
+		if series.Side != Finals && box.X != DefaultSpacing.Margin+(series.Round-1)*(DefaultSpacing.BoxWidth+DefaultSpacing.ColumnGap) {
+			t.Errorf("%s at x %d isn't in the column for round %d", series.Label, box.X, series.Round)
+		}
+		for _, other := range format.Series {
+			if other.Number != series.Number && overlaps(box, layout.Boxes[other.Number]) {
+				t.Errorf("%s and %s overlap", series.Label, other.Label)
+			}
+		}
+	}
+
+	// the upper final sits halfway between the two upper round 2 series
+	upper7, upper8, upperFinal := layout.Boxes[7], layout.Boxes[8], layout.Boxes[12]
+	if upperFinal.Y != (upper7.Y+upper8.Y)/2 {
+		t.Errorf("upper final at y %d, want %d", upperFinal.Y, (upper7.Y+upper8.Y)/2)
+	}
+	// and the whole lower bracket is under the upper one
+	if lower := layout.Boxes[5]; lower.Y <= layout.Boxes[4].Y+DefaultSpacing.BoxHeight {
+		t.Errorf("lower bracket starts at y %d, above the end of the upper bracket", lower.Y)
+	}
+}
+
+func overlaps(a, b Rect) bool {
+	return a.X < b.X+b.W && b.X < a.X+a.W && a.Y < b.Y+b.H && b.Y < a.Y+a.H
+}
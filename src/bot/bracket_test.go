@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+)
+
+var pngMagic = []byte("\x89PNG")
+
+func bracketInteraction() *discordgo.InteractionCreate {
+	return sessiontest.NewInteraction("bracket-guild", "member", discordgo.ApplicationCommandInteractionData{Name: "bracket"})
+}
+
+func TestBracketCommand(t *testing.T) {
+	fakeEvent(t, "USBRACKQ1", 0, 0, []ftcscout.Match{fakeQual(1, true)})
+	session := sessiontest.New()
+
+	handleBracketCommand(session, bracketInteraction(), "2025", "USBRACKQ1", "", "png")
+
+	sent, _ := session.Last()
+	if sent.Kind != "edit" || len(sent.Embeds) != 1 || len(sent.Files) != 1 {
+		t.Fatalf("sent %+v, want the bracket edited into the response", sent)
+	}
+	if sent.Embeds[0].Image == nil || sent.Embeds[0].Image.URL != "attachment://bracket.png" {
+		t.Errorf("embed image = %+v", sent.Embeds[0].Image)
+	}
+	image, _ := io.ReadAll(sent.Files[0].Reader)
+	if !bytes.HasPrefix(image, pngMagic) {
+		t.Errorf("attachment isn't a png (%d bytes)", len(image))
+	}
+}
+
+func playoffTeams(captain, firstPick int) []TeamDTO {
+	return []TeamDTO{
+		{TeamNumber: captain, AllianceRole: "Captain"},
+		{TeamNumber: firstPick, AllianceRole: "FirstPick"},
+	}
+}
+
+func TestBracketSeedsFromFirstRound(t *testing.T) {
+	tracker := &BracketTracker{
+		AllianceCount:     4,
+		Alliances:         make(map[int]*Alliance),
+		ProcessedMatchIDs: make(map[int]bool),
+	}
+
+	// series 2 (seed 2 against seed 3) is played before series 1 (seed 1 against seed 4)
+	tracker.UpdateBracketWithMatch(1002, 2, playoffTeams(200, 201), playoffTeams(300, 301), 90, 80)
+	tracker.UpdateBracketWithMatch(1001, 1, playoffTeams(100, 101), playoffTeams(400, 401), 120, 60)
+
+	want := map[int]int{100: 1, 200: 2, 300: 3, 400: 4}
+	for captain, seed := range want {
+		if got := tracker.Alliances[captain].Seed; got != seed {
+			t.Errorf("alliance %d has seed %d, want %d", captain, got, seed)
+		}
+	}
+
+	// reading the results doesn't touch the alliances
+	before := *tracker.Alliances[200]
+	tracker.mu.Lock()
+	_, results, _ := tracker.results()
+	tracker.mu.Unlock()
+	if *tracker.Alliances[200] != before {
+		t.Errorf("results changed alliance 200 from %+v to %+v", before, *tracker.Alliances[200])
+	}
+	if results[2].Winner != tracker.Alliances[200] {
+		t.Errorf("series 2 winner = %+v, want alliance 200", results[2].Winner)
+	}
+}
+
+// with the alliance count unknown the first two series of an 8 alliance playoff look like a 4 alliance first round,
+// the seeds have to wait until series 3 shows which one it is
+func TestBracketSeedsWaitForTheFormat(t *testing.T) {
+	eightAlliances := &BracketTracker{Alliances: make(map[int]*Alliance), ProcessedMatchIDs: make(map[int]bool)}
+	eightAlliances.UpdateBracketWithMatch(1001, 1, playoffTeams(100, 101), playoffTeams(800, 801), 120, 60)
+	eightAlliances.UpdateBracketWithMatch(1002, 2, playoffTeams(400, 401), playoffTeams(500, 501), 90, 80)
+	for captain, alliance := range eightAlliances.Alliances {
+		if alliance.Seed != 0 {
+			t.Errorf("alliance %d was seeded %d before the format was known", captain, alliance.Seed)
+		}
+	}
+
+	eightAlliances.UpdateBracketWithMatch(1003, 3, playoffTeams(200, 201), playoffTeams(700, 701), 100, 70)
+	for captain, seed := range map[int]int{100: 1, 800: 8, 400: 4, 500: 5, 200: 2, 700: 7} {
+		if got := eightAlliances.Alliances[captain].Seed; got != seed {
+			t.Errorf("8 alliances: alliance %d has seed %d, want %d", captain, got, seed)
+		}
+	}
+
+	fourAlliances := &BracketTracker{Alliances: make(map[int]*Alliance), ProcessedMatchIDs: make(map[int]bool)}
+	fourAlliances.UpdateBracketWithMatch(2001, 1, playoffTeams(100, 101), playoffTeams(400, 401), 120, 60)
+	fourAlliances.UpdateBracketWithMatch(2002, 2, playoffTeams(200, 201), playoffTeams(300, 301), 90, 80)
+	fourAlliances.UpdateBracketWithMatch(2003, 3, playoffTeams(400, 401), playoffTeams(300, 301), 70, 75)
+	for captain, seed := range map[int]int{100: 1, 400: 4, 200: 2, 300: 3} {
+		if got := fourAlliances.Alliances[captain].Seed; got != seed {
+			t.Errorf("4 alliances: alliance %d has seed %d, want %d", captain, got, seed)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/render"
+)
+
+const (
+	minCompareTeams = 2
+	maxCompareTeams = 4
+)
+
+var compareCategories = []string{"Total", "Auto", "DC", "Endgame"}
+
+type comparedTeam struct {
+	Info   *TeamInfo
+	Stats  *ftcscout.QuickStats
+	Awards []TeamAward
+}
+
+// values in the same order as compareCategories
+func (t comparedTeam) categories() []ftcscout.StatValue {
+	return []ftcscout.StatValue{t.Stats.Tot, t.Stats.Auto, t.Stats.Dc, t.Stats.Eg}
+}
+
+func (t comparedTeam) seasonAwards() int {
+	count := 0
+	for _, award := range t.Awards {
+		if award.Season == t.Stats.Season {
+			count++
+		}
+	}
+	return count
+}
+
+func teamCompare(channelID string, teamNumbers []string, session interactions.Session, i *discordgo.InteractionCreate, guildID string) {
+	// drop repeats so comparing a team to itself doesn't look like a tie
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, teamNumber := range teamNumbers {
+		teamNumber = strings.TrimSpace(teamNumber)
+		if teamNumber == "" || seen[teamNumber] {
+			continue
+		}
+		seen[teamNumber] = true
+		unique = append(unique, teamNumber)
+	}
+	if len(unique) < minCompareTeams || len(unique) > maxCompareTeams {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Please provide between %d and %d different team numbers.", minCompareTeams, maxCompareTeams))
+		return
+	}
+
+	teams, err := fetchComparedTeams(unique)
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	best := bestInCategories(teams)
+	embed := createCompareEmbed(teams, best)
+
+	var attachments []interactions.Attachment
+	imgBuf, err := compareChart(teams, best).Draw(render.PNG, guildconfig.Theme(guildID), 2)
+	if err != nil {
+		// the embed has everything the chart does so it can go out on its own
+		logging.WithInteraction(commandLog, i).Warn("Failed to render comparison chart", "err", err)
+	} else {
+		embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://compare.png"}
+		attachments = append(attachments, interactions.Attachment{Name: "compare.png", ContentType: render.PNG.ContentType(), Data: imgBuf.Bytes()})
+	}
+
+	if err := interactions.EditOrSend(session, i, channelID, "", []*discordgo.MessageEmbed{embed}, attachments...); err != nil {
+		logging.WithInteraction(commandLog, i).Error("Failed to send team comparison", "channel", channelID, "err", err)
+	}
+}
+
+// fetchComparedTeams grabs info, quick stats and awards for every team at once, keeping the requested order
+func fetchComparedTeams(teamNumbers []string) ([]comparedTeam, error) {
+	teams := make([]comparedTeam, len(teamNumbers))
+	errs := make([]error, len(teamNumbers))
+
+	var wg sync.WaitGroup
+	for idx, teamNumber := range teamNumbers {
+		wg.Add(1)
+		go func(idx int, teamNumber string) {
+			defer wg.Done()
+			info, err := fetchTeamInfo(teamNumber)
+			if err != nil {
+				errs[idx] = err
+				return
+			}
+			stats, err := ftcscout.Default.TeamQuickStats(context.Background(), teamNumber)
+			if err != nil {
+				errs[idx] = fmt.Errorf("failed to fetch stats for Team %s: %v", teamNumber, err)
+				return
+			}
+			awards, err := awardsCache.GetOrFetch(teamNumber)
+			if err != nil {
+				errs[idx] = err
+				return
+			}
+			teams[idx] = comparedTeam{Info: info, Stats: stats, Awards: awards}
+		}(idx, teamNumber)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return teams, nil
+}
+
+// bestInCategories returns the index of the highest scoring team for each category
+func bestInCategories(teams []comparedTeam) []int {
+	best := make([]int, len(compareCategories))
+	for category := range compareCategories {
+		for idx, team := range teams {
+			if team.categories()[category].Value > teams[best[category]].categories()[category].Value {
+				best[category] = idx
+			}
+		}
+	}
+	return best
+}
+
+func createCompareEmbed(teams []comparedTeam, best []int) *discordgo.MessageEmbed {
+	names := []string{}
+	for _, team := range teams {
+		names = append(names, fmt.Sprintf("%d", team.Info.Number))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Comparing Teams %s", strings.Join(names, " vs ")),
+		Description: "⭐ marks the best team in each category.",
+		Color:       0x72cfdd,
+		Fields:      []*discordgo.MessageEmbedField{},
+	}
+
+	for idx, team := range teams {
+		lines := []string{fmt.Sprintf("*%s*", team.Info.Name)}
+		for category, stat := range team.categories() {
+			star := ""
+			if best[category] == idx {
+				star = " ⭐"
+			}
+			lines = append(lines, fmt.Sprintf("**%s:** %.2f (#%d)%s", compareCategories[category], stat.Value, stat.Rank, star))
+		}
+		lines = append(lines,
+			fmt.Sprintf("**Events:** %d", team.Stats.Count),
+			fmt.Sprintf("**Awards:** %d (%d this season)", len(team.Awards), team.seasonAwards()),
+		)
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("Team %d", team.Info.Number),
+			Value:  strings.Join(lines, "\n"),
+			Inline: true,
+		})
+	}
+
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("Season %d quick stats from FTCScout", teams[0].Stats.Season),
+	}
+	return embed
+}
+
+func compareChart(teams []comparedTeam, best []int) render.BarChart {
+	chart := render.BarChart{
+		Title:      "Team Comparison",
+		Categories: compareCategories,
+		Best:       best,
+	}
+	for _, team := range teams {
+		values := []float64{}
+		for _, stat := range team.categories() {
+			values = append(values, stat.Value)
+		}
+		chart.Series = append(chart.Series, render.BarSeries{
+			Name:   fmt.Sprintf("%d %s", team.Info.Number, team.Info.Name),
+			Values: values,
+		})
+	}
+	return chart
+}
@@ -0,0 +1,336 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/presets"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/search"
+)
+
+func init() {
+	adminPermissions := int64(discordgo.PermissionAdministrator)
+
+	providerChoices := []*discordgo.ApplicationCommandOptionChoice{}
+	for _, name := range provider.Names() {
+		providerChoices = append(providerChoices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+	}
+	providerChoices = append(providerChoices,
+		&discordgo.ApplicationCommandOptionChoice{Name: "ftcscout, falling back to ftcevents", Value: "ftcscout,ftcevents"},
+		&discordgo.ApplicationCommandOptionChoice{Name: "ftcevents, falling back to ftcscout", Value: "ftcevents,ftcscout"},
+	)
+
+	interactions.RegisterCommand(
+		&discordgo.ApplicationCommand{
+			Name:                     "config",
+			Description:              "View or change Bjorn's settings for this server (admin only).",
+			DefaultMemberPermissions: &adminPermissions,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "view",
+					Description: "Show this server's settings.",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "set",
+					Description: "Change one or more settings.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "region",
+							Description:  "Home region, used for /roleme and the welcome message.",
+							Autocomplete: true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "prefix",
+							Description: "Prefix for text commands (e.g. >>).",
+							MaxLength:   5,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "welcome_text",
+							Description: "Text shown in the welcome message new members get.",
+							MaxLength:   1000,
+						},
+						{
+							Type:         discordgo.ApplicationCommandOptionChannel,
+							Name:         "announcement_channel",
+							Description:  "Channel where match tracker updates are posted.",
+							ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "match_ping_role",
+							Description: "Role members take to get on deck pings for their team.",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "season",
+							Description: "Season used when a command's year is left out.",
+							Choices:     interactions.FtcYearChoices,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "provider",
+							Description: "Where team, event and match data comes from.",
+							Choices:     providerChoices,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "theme",
+							Description: "Color theme for brackets and other images.",
+							Choices:     themeChoices(),
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "on_deck_matches",
+							Description: "How many matches ahead teams get an on deck alert, 0 turns alerts off.",
+							MinValue:    &minOnDeckMatches,
+							MaxValue:    maxOnDeckMatches,
+						},
+						{
+							Type:         discordgo.ApplicationCommandOptionChannel,
+							Name:         "on_deck_channel",
+							Description:  "Channel or thread for on deck alerts, defaults to the tracker's channel.",
+							ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews, discordgo.ChannelTypeGuildPublicThread},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "reset",
+					Description: "Reset a setting back to the default.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "setting",
+							Description: "The setting to reset.",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "region", Value: "region"},
+								{Name: "prefix", Value: "prefix"},
+								{Name: "welcome_text", Value: "welcome_text"},
+								{Name: "announcement_channel", Value: "announcement_channel"},
+								{Name: "match_ping_role", Value: "match_ping_role"},
+								{Name: "season", Value: "season"},
+								{Name: "provider", Value: "provider"},
+								{Name: "theme", Value: "theme"},
+								{Name: "on_deck_matches", Value: "on_deck_matches"},
+								{Name: "on_deck_channel", Value: "on_deck_channel"},
+								{Name: "everything", Value: "all"},
+							},
+						},
+					},
+				},
+			},
+		},
+		configCommandHandler,
+	)
+
+	interactions.RegisterAutocomplete("config/set/region", presets.RegionAutocomplete)
+}
+
+var (
+	minOnDeckMatches = 0.0
+	maxOnDeckMatches = 10.0
+)
+
+func configCommandHandler(s interactions.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" || i.Member == nil {
+		interactions.SendEphemeralMessage(s, i, "This command can only be used in a server.")
+		return
+	}
+
+	isAdminUser, err := isAdmin(s, i.GuildID, i.Member.User.ID)
+	if err != nil {
+		interactions.SendEphemeralMessage(s, i, "Unable to check permissions.")
+		return
+	}
+	if !isAdminUser {
+		interactions.SendEphemeralMessage(s, i, "You do not have permission to run this command.")
+		return
+	}
+
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		interactions.SendMessage(s, i, "", "Please provide a subcommand for config.")
+		return
+	}
+
+	sub := data.Options[0]
+	switch sub.Name {
+	case "view":
+		interactions.SendEmbed(s, i, "", createConfigEmbed(i.GuildID))
+	case "set":
+		setConfig(s, i, sub.Options)
+	case "reset":
+		resetConfig(s, i, interactions.GetStringOption(sub.Options, "setting"))
+	default:
+		interactions.SendMessage(s, i, "", "Unknown subcommand for config.")
+	}
+}
+
+func setConfig(s interactions.Session, i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption) {
+	if len(opts) == 0 {
+		interactions.SendMessage(s, i, "", "Please provide at least one setting to change.")
+		return
+	}
+
+	region := interactions.GetStringOption(opts, "region")
+	if region != "" && !search.IsValidRegionCode(region) {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("`%s` is not a valid region, pick one from the list.", region))
+		return
+	}
+
+	prefix := interactions.GetStringOption(opts, "prefix")
+	if prefix != "" && strings.ContainsAny(prefix, " \t\n") {
+		interactions.SendMessage(s, i, "", "The prefix can't contain spaces.")
+		return
+	}
+
+	dataProvider := interactions.GetStringOption(opts, "provider")
+	if dataProvider != "" {
+		if _, err := provider.Parse(dataProvider); err != nil {
+			interactions.SendMessage(s, i, "", err.Error())
+			return
+		}
+	}
+
+	err := guildconfig.Update(i.GuildID, func(cfg *guildconfig.Config) {
+		if region != "" {
+			cfg.HomeRegion = region
+		}
+		if prefix != "" {
+			cfg.CommandPrefix = prefix
+		}
+		if text := interactions.GetStringOption(opts, "welcome_text"); text != "" {
+			cfg.WelcomeText = text
+		}
+		if channel := interactions.GetStringOption(opts, "announcement_channel"); channel != "" {
+			cfg.AnnouncementChannelId = channel
+		}
+		if role := interactions.GetStringOption(opts, "match_ping_role"); role != "" {
+			cfg.MatchPingRole = role
+		}
+		if season := interactions.GetStringOption(opts, "season"); season != "" {
+			cfg.DefaultSeason = season
+		}
+		if dataProvider != "" {
+			cfg.DataProvider = dataProvider
+		}
+		if theme := interactions.GetStringOption(opts, "theme"); theme != "" {
+			cfg.Theme = theme
+		}
+		if onDeckMatches, ok := interactions.GetIntOption(opts, "on_deck_matches"); ok {
+			matches := int(onDeckMatches)
+			cfg.OnDeckMatches = &matches
+		}
+		if channel := interactions.GetStringOption(opts, "on_deck_channel"); channel != "" {
+			cfg.OnDeckChannelId = channel
+		}
+	})
+	if HandleErr(err) {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("Failed to save settings: %v", err))
+		return
+	}
+
+	embed := createConfigEmbed(i.GuildID)
+	embed.Description = "Settings updated."
+	interactions.SendEmbed(s, i, "", embed)
+}
+
+func resetConfig(s interactions.Session, i *discordgo.InteractionCreate, setting string) {
+	err := guildconfig.Update(i.GuildID, func(cfg *guildconfig.Config) {
+		switch setting {
+		case "region":
+			cfg.HomeRegion = ""
+		case "prefix":
+			cfg.CommandPrefix = ""
+		case "welcome_text":
+			cfg.WelcomeText = ""
+		case "announcement_channel":
+			cfg.AnnouncementChannelId = ""
+		case "match_ping_role":
+			cfg.MatchPingRole = ""
+		case "season":
+			cfg.DefaultSeason = ""
+		case "provider":
+			cfg.DataProvider = ""
+		case "theme":
+			cfg.Theme = ""
+		case "on_deck_matches":
+			cfg.OnDeckMatches = nil
+		case "on_deck_channel":
+			cfg.OnDeckChannelId = ""
+		case "all":
+			*cfg = guildconfig.Config{}
+		}
+	})
+	if HandleErr(err) {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("Failed to save settings: %v", err))
+		return
+	}
+
+	embed := createConfigEmbed(i.GuildID)
+	embed.Description = fmt.Sprintf("Reset `%s` to the default.", setting)
+	interactions.SendEmbed(s, i, "", embed)
+}
+
+func createConfigEmbed(guildID string) *discordgo.MessageEmbed {
+	raw := guildconfig.Raw(guildID)
+	cfg := guildconfig.Get(guildID)
+
+	// marks which values are defaults so admins know what they've actually changed
+	show := func(value string, isSet bool) string {
+		if value == "" {
+			value = "*none*"
+		}
+		if !isSet {
+			return value + " (default)"
+		}
+		return value
+	}
+
+	announcementChannel := ""
+	if cfg.AnnouncementChannelId != "" {
+		announcementChannel = fmt.Sprintf("<#%s>", cfg.AnnouncementChannelId)
+	}
+
+	onDeckMatches := "off"
+	if *cfg.OnDeckMatches > 0 {
+		onDeckMatches = fmt.Sprintf("%d matches ahead", *cfg.OnDeckMatches)
+	}
+	onDeckChannel := "tracker channel"
+	if cfg.OnDeckChannelId != "" {
+		onDeckChannel = fmt.Sprintf("<#%s>", cfg.OnDeckChannelId)
+	}
+
+	dataProvider := cfg.DataProvider
+	if dataProvider == "" {
+		dataProvider = provider.Default().Name()
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: "Server Settings",
+		Color: 0x72cfdd,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Region", Value: show(fmt.Sprintf("%s (`%s`)", search.GetRegionName(cfg.HomeRegion), cfg.HomeRegion), raw.HomeRegion != ""), Inline: true},
+			{Name: "Prefix", Value: show(fmt.Sprintf("`%s`", cfg.CommandPrefix), raw.CommandPrefix != ""), Inline: true},
+			{Name: "Season", Value: show(cfg.DefaultSeason, raw.DefaultSeason != ""), Inline: true},
+			{Name: "Announcement Channel", Value: show(announcementChannel, raw.AnnouncementChannelId != ""), Inline: true},
+			{Name: "Match Ping Role", Value: show(cfg.MatchPingRole, raw.MatchPingRole != ""), Inline: true},
+			{Name: "Data Provider", Value: show(dataProvider, raw.DataProvider != ""), Inline: true},
+			{Name: "Theme", Value: show(cfg.Theme, raw.Theme != ""), Inline: true},
+			{Name: "On Deck Alerts", Value: show(onDeckMatches, raw.OnDeckMatches != nil), Inline: true},
+			{Name: "On Deck Channel", Value: show(onDeckChannel, raw.OnDeckChannelId != ""), Inline: true},
+			{Name: "Welcome Text", Value: show(cfg.WelcomeText, raw.WelcomeText != "")},
+		},
+	}
+}
@@ -0,0 +1,326 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/analytics"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/pagination"
+	"github.com/shuban-789/bjorn/src/bot/presets"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/util"
+)
+
+var (
+	oprPaginator *pagination.Paginator[analytics.TeamRating]
+
+	// keyed by "<provider> <year> <eventCode>", results are short lived since events update constantly
+	eventMatchesCache = util.NewCache(
+		100,
+		time.Minute*2,
+		getEventMatches,
+	)
+)
+
+func init() {
+	interactions.RegisterCommand(
+		&discordgo.ApplicationCommand{
+			Name:        "event",
+			Description: "Look at stats for an event.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "opr",
+					Description: "OPR, DPR and CCWM for every team at an event.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "event",
+							Description: "Event code to look up.",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
+							Choices:     interactions.FtcYearChoices,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "matches",
+							Description: "Which matches to use (defaults to quals only).",
+							Required:    false,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Quals only", Value: "quals"},
+								{Name: "All matches", Value: "all"},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "schedule",
+					Description: "Show the qualification schedule for an event or one team.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "event",
+							Description: "Event code to look up.",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
+							Choices:     interactions.FtcYearChoices,
+						},
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "team",
+							Description:  "Only show this team's matches.",
+							Required:     false,
+							Autocomplete: true,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "export",
+					Description: "Download an event's rankings, matches, awards or OPR as a file.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "event",
+							Description: "Event code to export.",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "data",
+							Description: "What to export.",
+							Required:    true,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Rankings", Value: "rankings"},
+								{Name: "Matches", Value: "matches"},
+								{Name: "Awards", Value: "awards"},
+								{Name: "OPR", Value: "opr"},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
+							Choices:     interactions.FtcYearChoices,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "format",
+							Description: "File format (defaults to CSV).",
+							Required:    false,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "CSV", Value: "csv"},
+								{Name: "JSON", Value: "json"},
+							},
+						},
+					},
+				},
+			},
+		},
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
+			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
+			data := i.ApplicationCommandData()
+			if len(data.Options) == 0 {
+				interactions.SendMessage(s, i, "", "Please provide a subcommand for event.")
+				return
+			}
+
+			sub := data.Options[0]
+			year := guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(sub.Options, "year"))
+			eventCode := interactions.GetStringOption(sub.Options, "event")
+			switch sub.Name {
+			case "opr":
+				if year == "" || eventCode == "" {
+					interactions.SendMessage(s, i, "", "Usage: /event opr <event> [year] [matches]")
+					return
+				}
+				eventcmd(s, nil, i, []string{"opr", year, eventCode, interactions.GetStringOption(sub.Options, "matches")})
+			case "schedule":
+				if year == "" || eventCode == "" {
+					interactions.SendMessage(s, i, "", "Usage: /event schedule <event> [year] [team]")
+					return
+				}
+				eventcmd(s, nil, i, []string{"schedule", year, eventCode, interactions.GetStringOption(sub.Options, "team")})
+			case "export":
+				if year == "" || eventCode == "" {
+					interactions.SendMessage(s, i, "", "Usage: /event export <event> <data> [year] [format]")
+					return
+				}
+				eventcmd(s, nil, i, []string{"export", year, eventCode, interactions.GetStringOption(sub.Options, "data"), interactions.GetStringOption(sub.Options, "format")})
+			default:
+				interactions.SendMessage(s, i, "", "Unknown subcommand for event.")
+			}
+		},
+	)
+
+	oprPaginator = pagination.New[analytics.TeamRating]("event;opr").
+		ItemsPerPage(5).
+		AddExtraKey("provider").
+		AddExtraKey("year").
+		AddExtraKey("eventCode").
+		AddExtraKey("matches").
+		OnUpdate(updateOprEmbed).
+		WithDataGetter(func(state pagination.PaginationState) ([]analytics.TeamRating, error) {
+			return fetchEventRatings(state.ExtraData["provider"], state.ExtraData["year"], state.ExtraData["eventCode"], state.ExtraData["matches"])
+		}).
+		Register()
+
+	interactions.RegisterAutocomplete("event/schedule/team", presets.TeamsAutocomplete)
+}
+
+func eventcmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
+	channelId := interactions.GetChannelId(message, i)
+	guildId, _ := interactions.GetGuildId(message, i)
+	if len(args) < 1 {
+		interactions.SendMessage(session, i, channelId, "Please provide a subcommand (e.g., 'opr').")
+		return
+	}
+
+	switch args[0] {
+	case "opr":
+		if len(args) < 3 {
+			interactions.SendMessage(session, i, channelId, fmt.Sprintf("Usage: `%sevent opr <year> <eventCode> [quals|all]`", guildconfig.Get(guildId).CommandPrefix))
+			return
+		}
+		matches := "quals"
+		if len(args) > 3 && args[3] == "all" {
+			matches = "all"
+		}
+
+		err := oprPaginator.Setup(session, i, channelId, map[string]string{
+			"provider":  guildconfig.Provider(guildId).Name(),
+			"year":      args[1],
+			"eventCode": strings.ToUpper(args[2]),
+			"matches":   matches,
+		})
+		if err != nil {
+			interactions.SendMessage(session, i, channelId, fmt.Sprintf("Error sending OPR: %v", err))
+		}
+	case "schedule":
+		if len(args) < 3 {
+			interactions.SendMessage(session, i, channelId, fmt.Sprintf("Usage: `%sevent schedule <year> <eventCode> [team]`", guildconfig.Get(guildId).CommandPrefix))
+			return
+		}
+		team := ""
+		if len(args) > 3 && args[3] != "" {
+			if _, err := strconv.Atoi(args[3]); err != nil {
+				interactions.SendMessage(session, i, channelId, "Please provide a valid team number.")
+				return
+			}
+			team = args[3]
+		}
+
+		err := schedulePaginator.Setup(session, i, channelId, map[string]string{
+			"provider":  guildconfig.Provider(guildId).Name(),
+			"year":      args[1],
+			"eventCode": strings.ToUpper(args[2]),
+			"team":      team,
+		})
+		if err != nil {
+			interactions.SendMessage(session, i, channelId, fmt.Sprintf("Error sending schedule: %v", err))
+		}
+	case "export":
+		if len(args) < 4 {
+			interactions.SendMessage(session, i, channelId, fmt.Sprintf("Usage: `%sevent export <year> <eventCode> <rankings|matches|awards|opr> [csv|json]`", guildconfig.Get(guildId).CommandPrefix))
+			return
+		}
+		format := ""
+		if len(args) > 4 {
+			format = args[4]
+		}
+		exportEvent(channelId, guildconfig.Provider(guildId), args[1], strings.ToUpper(args[2]), args[3], format, session, i)
+	default:
+		interactions.SendMessage(session, i, channelId, "Unknown subcommand. Available subcommands: `opr`, `schedule`, `export`")
+	}
+}
+
+func getEventMatches(key string) ([]ftcscout.Match, error) {
+	splitted := strings.Fields(key)
+	p, err := provider.Parse(splitted[0])
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := p.EventMatches(context.Background(), splitted[1], splitted[2])
+	if err != nil {
+		if provider.IsNotFound(err) {
+			return nil, fmt.Errorf("event %s was not found in %s", splitted[2], splitted[1])
+		}
+		return nil, fmt.Errorf("failed to fetch matches: %w", err)
+	}
+	return matches, nil
+}
+
+func fetchEventRatings(providerName, year, eventCode, matches string) ([]analytics.TeamRating, error) {
+	eventMatches, err := eventMatchesCache.GetOrFetch(fmt.Sprintf("%s %s %s", providerName, year, eventCode))
+	if err != nil {
+		return nil, err
+	}
+
+	filter := analytics.QualsOnly
+	if matches == "all" {
+		filter = analytics.AllMatches
+	}
+
+	ratings, err := analytics.Compute(eventMatches, filter)
+	if errors.Is(err, analytics.ErrNoMatches) {
+		return nil, fmt.Errorf("no matches have been played at %s yet", eventCode)
+	}
+	return ratings, err
+}
+
+func updateOprEmbed(state pagination.PaginationState, ratings []analytics.TeamRating, embed *discordgo.MessageEmbed) (*discordgo.MessageEmbed, error) {
+	matchesDescription := "quals only"
+	if state.ExtraData["matches"] == "all" {
+		matchesDescription = "all matches"
+	}
+
+	embed.Title = fmt.Sprintf("%s %s OPR", state.ExtraData["year"], state.ExtraData["eventCode"])
+	embed.Description = fmt.Sprintf("Computed from %s. CCWM is OPR - DPR.", matchesDescription)
+	embed.Color = 0x72cfdd
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("Page %d of %d", state.CurrentPage+1, state.TotalPages),
+	}
+
+	embed.Fields = []*discordgo.MessageEmbedField{}
+	for index, rating := range ratings {
+		rank := state.CurrentPage*oprPaginator.ItemsPerPage + index + 1
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("#%d Team %d (%d matches)", rank, rating.TeamNumber, rating.MatchesPlayed),
+			Value: formatRatingTable(rating),
+		})
+	}
+	return embed, nil
+}
+
+func formatRatingTable(rating analytics.TeamRating) string {
+	var table strings.Builder
+	table.WriteString("```\n")
+	fmt.Fprintf(&table, "%-7s %7s %7s %7s\n", "", "OPR", "DPR", "CCWM")
+	for _, component := range analytics.Components {
+		r := rating.Get(component)
+		fmt.Fprintf(&table, "%-7s %7.1f %7.1f %7.1f\n", component, r.OPR, r.DPR, r.CCWM)
+	}
+	table.WriteString("```")
+	return table.String()
+}
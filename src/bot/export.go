@@ -0,0 +1,370 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/analytics"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+)
+
+// Exports are meant to be read by spreadsheets and scripts, so the shape only changes with exportVersion.
+//
+// CSV files are a header row followed by one row per record, the header names are the same as the json keys.
+// JSON files wrap the records in an envelope:
+//
+//	{"version": 1, "kind": "rankings", "season": "2024", "event": "USCASDM", "generatedAt": "<RFC 3339>", "data": [...]}
+//
+// Columns for each kind, in order:
+//
+//	rankings: rank, teamNumber, teamName, rp, tbp, wins, losses, ties, matchesPlayed, opr (empty if unknown)
+//	matches:  id, tournamentLevel, series, name, played, startTime, redTeams, blueTeams,
+//	          redTotal, redAuto, redTeleOp, redFouls, blueTotal, blueAuto, blueTeleOp, blueFouls, winner (red, blue, tie or empty)
+//	awards:   teamNumber, award, placement, personName
+//	opr:      teamNumber, matchesPlayed, then opr/dpr/ccwm for total, auto, teleOp and fouls (totalOpr, totalDpr, ... foulsCcwm)
+//
+// In CSV the team lists are separated with ";", in JSON they are arrays of numbers.
+const exportVersion = 1
+
+var exportKinds = []string{"rankings", "matches", "awards", "opr"}
+
+type exportRecord interface {
+	csvRow() []string
+}
+
+type exportTable struct {
+	Columns []string
+	Records []exportRecord
+}
+
+type exportEnvelope struct {
+	Version     int            `json:"version"`
+	Kind        string         `json:"kind"`
+	Season      string         `json:"season"`
+	Event       string         `json:"event"`
+	GeneratedAt string         `json:"generatedAt"`
+	Data        []exportRecord `json:"data"`
+}
+
+func exportEvent(channelID string, dataProvider provider.Provider, year, eventCode, kind, format string, session interactions.Session, i *discordgo.InteractionCreate) {
+	kind = strings.ToLower(kind)
+	format = strings.ToLower(format)
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		interactions.SendMessage(session, i, channelID, "Unknown format, use 'csv' or 'json'.")
+		return
+	}
+
+	table, err := buildExportTable(dataProvider, year, eventCode, kind)
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	var buf *bytes.Buffer
+	contentType := "text/csv"
+	if format == "json" {
+		contentType = "application/json"
+		buf, err = encodeExportJSON(table, kind, year, eventCode, time.Now())
+	} else {
+		buf, err = encodeExportCSV(table)
+	}
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to build export: %v", err))
+		return
+	}
+
+	content := fmt.Sprintf("%s %s %s (%d rows)", year, eventCode, kind, len(table.Records))
+	export := interactions.Attachment{
+		Name:        fmt.Sprintf("%s-%s-%s.%s", year, eventCode, kind, format),
+		ContentType: contentType,
+		Data:        buf.Bytes(),
+	}
+	if err := interactions.EditOrSend(session, i, channelID, content, nil, export); err != nil {
+		logging.WithInteraction(commandLog, i).Error("Failed to send export", "channel", channelID, "err", err)
+	}
+}
+
+func buildExportTable(dataProvider provider.Provider, year, eventCode, kind string) (exportTable, error) {
+	switch kind {
+	case "rankings":
+		return exportRankings(dataProvider, year, eventCode)
+	case "matches":
+		return exportMatches(dataProvider, year, eventCode)
+	case "awards":
+		return exportAwards(dataProvider, year, eventCode)
+	case "opr":
+		return exportOPR(dataProvider, year, eventCode)
+	}
+	return exportTable{}, fmt.Errorf("unknown export %q, use one of: %s", kind, strings.Join(exportKinds, ", "))
+}
+
+func encodeExportCSV(table exportTable) (*bytes.Buffer, error) {
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+	if err := writer.Write(table.Columns); err != nil {
+		return nil, err
+	}
+	for _, record := range table.Records {
+		if err := writer.Write(record.csvRow()); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf, writer.Error()
+}
+
+func encodeExportJSON(table exportTable, kind, year, eventCode string, now time.Time) (*bytes.Buffer, error) {
+	records := table.Records
+	if records == nil {
+		// an empty event should still be a list for whatever reads it
+		records = []exportRecord{}
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(exportEnvelope{
+		Version:     exportVersion,
+		Kind:        kind,
+		Season:      year,
+		Event:       eventCode,
+		GeneratedAt: now.UTC().Format(time.RFC3339),
+		Data:        records,
+	})
+	return buf, err
+}
+
+func formatExportFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatExportTeams(teams []int) string {
+	numbers := []string{}
+	for _, team := range teams {
+		numbers = append(numbers, strconv.Itoa(team))
+	}
+	return strings.Join(numbers, ";")
+}
+
+// rankings come straight from the leaderboard
+func exportRankings(dataProvider provider.Provider, year, eventCode string) (exportTable, error) {
+	ranks, err := leadCache.GetOrFetch(fmt.Sprintf("%s %s %s", dataProvider.Name(), year, eventCode))
+	if err != nil {
+		return exportTable{}, err
+	}
+
+	table := exportTable{Columns: []string{"rank", "teamNumber", "teamName", "rp", "tbp", "wins", "losses", "ties", "matchesPlayed", "opr"}}
+	for _, rank := range ranks {
+		table.Records = append(table.Records, rank)
+	}
+	return table, nil
+}
+
+func (r TeamRank) csvRow() []string {
+	opr := ""
+	if r.OPR != nil {
+		opr = formatExportFloat(*r.OPR)
+	}
+	return []string{
+		strconv.Itoa(r.Rank), strconv.Itoa(r.TeamNumber), r.TeamName,
+		formatExportFloat(r.RP), formatExportFloat(r.TBP),
+		strconv.Itoa(r.Wins), strconv.Itoa(r.Losses), strconv.Itoa(r.Ties), strconv.Itoa(r.MatchesPlayed),
+		opr,
+	}
+}
+
+type matchRecord struct {
+	ID              int    `json:"id"`
+	TournamentLevel string `json:"tournamentLevel"`
+	Series          int    `json:"series"`
+	Name            string `json:"name"`
+	Played          bool   `json:"played"`
+	StartTime       string `json:"startTime"`
+	RedTeams        []int  `json:"redTeams"`
+	BlueTeams       []int  `json:"blueTeams"`
+	RedTotal        int    `json:"redTotal"`
+	RedAuto         int    `json:"redAuto"`
+	RedTeleOp       int    `json:"redTeleOp"`
+	RedFouls        int    `json:"redFouls"`
+	BlueTotal       int    `json:"blueTotal"`
+	BlueAuto        int    `json:"blueAuto"`
+	BlueTeleOp      int    `json:"blueTeleOp"`
+	BlueFouls       int    `json:"blueFouls"`
+	Winner          string `json:"winner"`
+}
+
+func exportMatches(dataProvider provider.Provider, year, eventCode string) (exportTable, error) {
+	matches, err := eventMatchesCache.GetOrFetch(fmt.Sprintf("%s %s %s", dataProvider.Name(), year, eventCode))
+	if err != nil {
+		return exportTable{}, err
+	}
+
+	table := exportTable{Columns: []string{
+		"id", "tournamentLevel", "series", "name", "played", "startTime", "redTeams", "blueTeams",
+		"redTotal", "redAuto", "redTeleOp", "redFouls", "blueTotal", "blueAuto", "blueTeleOp", "blueFouls", "winner",
+	}}
+	for _, match := range matches {
+		record := matchRecord{
+			ID:              match.ID,
+			TournamentLevel: match.TournamentLevel,
+			Series:          match.Series,
+			Name:            getMatchName(match),
+			Played:          match.HasBeenPlayed,
+			StartTime:       match.ActualStartTime,
+			RedTeams:        []int{},
+			BlueTeams:       []int{},
+		}
+		for _, team := range match.Teams {
+			if team.AllianceColor == "Red" {
+				record.RedTeams = append(record.RedTeams, team.TeamNumber)
+			} else if team.AllianceColor == "Blue" {
+				record.BlueTeams = append(record.BlueTeams, team.TeamNumber)
+			}
+		}
+
+		// unplayed matches keep zero scores and no winner
+		if match.HasBeenPlayed {
+			red, blue := match.Scores.Red, match.Scores.Blue
+			record.RedTotal, record.RedAuto, record.RedTeleOp, record.RedFouls = red.Total, red.Auto, red.TeleOp, red.Fouls
+			record.BlueTotal, record.BlueAuto, record.BlueTeleOp, record.BlueFouls = blue.Total, blue.Auto, blue.TeleOp, blue.Fouls
+			switch {
+			case red.Total > blue.Total:
+				record.Winner = "red"
+			case blue.Total > red.Total:
+				record.Winner = "blue"
+			default:
+				record.Winner = "tie"
+			}
+		}
+		table.Records = append(table.Records, record)
+	}
+	return table, nil
+}
+
+func (r matchRecord) csvRow() []string {
+	return []string{
+		strconv.Itoa(r.ID), r.TournamentLevel, strconv.Itoa(r.Series), r.Name, strconv.FormatBool(r.Played), r.StartTime,
+		formatExportTeams(r.RedTeams), formatExportTeams(r.BlueTeams),
+		strconv.Itoa(r.RedTotal), strconv.Itoa(r.RedAuto), strconv.Itoa(r.RedTeleOp), strconv.Itoa(r.RedFouls),
+		strconv.Itoa(r.BlueTotal), strconv.Itoa(r.BlueAuto), strconv.Itoa(r.BlueTeleOp), strconv.Itoa(r.BlueFouls),
+		r.Winner,
+	}
+}
+
+type awardRecord struct {
+	TeamNumber int    `json:"teamNumber"`
+	Award      string `json:"award"`
+	Placement  int    `json:"placement"`
+	PersonName string `json:"personName"`
+}
+
+func exportAwards(dataProvider provider.Provider, year, eventCode string) (exportTable, error) {
+	awards, err := dataProvider.EventAwards(context.Background(), year, eventCode)
+	if provider.IsNotFound(err) {
+		return exportTable{}, fmt.Errorf("event %s was not found in %s", eventCode, year)
+	}
+	if err != nil {
+		return exportTable{}, fmt.Errorf("failed to fetch awards: %w", err)
+	}
+
+	sort.SliceStable(awards, func(a, b int) bool {
+		if awards[a].Type != awards[b].Type {
+			return awards[a].Type < awards[b].Type
+		}
+		return awards[a].Placement < awards[b].Placement
+	})
+
+	table := exportTable{Columns: []string{"teamNumber", "award", "placement", "personName"}}
+	for _, award := range awards {
+		table.Records = append(table.Records, awardRecord{
+			TeamNumber: award.TeamNumber,
+			Award:      award.Type,
+			Placement:  award.Placement,
+			PersonName: award.PersonName,
+		})
+	}
+	return table, nil
+}
+
+func (r awardRecord) csvRow() []string {
+	return []string{strconv.Itoa(r.TeamNumber), r.Award, strconv.Itoa(r.Placement), r.PersonName}
+}
+
+type oprRecord struct {
+	TeamNumber    int     `json:"teamNumber"`
+	MatchesPlayed int     `json:"matchesPlayed"`
+	TotalOpr      float64 `json:"totalOpr"`
+	TotalDpr      float64 `json:"totalDpr"`
+	TotalCcwm     float64 `json:"totalCcwm"`
+	AutoOpr       float64 `json:"autoOpr"`
+	AutoDpr       float64 `json:"autoDpr"`
+	AutoCcwm      float64 `json:"autoCcwm"`
+	TeleOpOpr     float64 `json:"teleOpOpr"`
+	TeleOpDpr     float64 `json:"teleOpDpr"`
+	TeleOpCcwm    float64 `json:"teleOpCcwm"`
+	FoulsOpr      float64 `json:"foulsOpr"`
+	FoulsDpr      float64 `json:"foulsDpr"`
+	FoulsCcwm     float64 `json:"foulsCcwm"`
+}
+
+// opr is always from quals only, same as the /event opr default
+func exportOPR(dataProvider provider.Provider, year, eventCode string) (exportTable, error) {
+	ratings, err := fetchEventRatings(dataProvider.Name(), year, eventCode, "quals")
+	if err != nil {
+		return exportTable{}, err
+	}
+
+	table := exportTable{Columns: []string{"teamNumber", "matchesPlayed"}}
+	for _, component := range analytics.Components {
+		name := strings.ToLower(component.String()[:1]) + component.String()[1:]
+		table.Columns = append(table.Columns, name+"Opr", name+"Dpr", name+"Ccwm")
+	}
+
+	for _, rating := range ratings {
+		total, auto := rating.Get(analytics.Total), rating.Get(analytics.Auto)
+		teleOp, fouls := rating.Get(analytics.TeleOp), rating.Get(analytics.Fouls)
+		table.Records = append(table.Records, oprRecord{
+			TeamNumber:    rating.TeamNumber,
+			MatchesPlayed: rating.MatchesPlayed,
+			TotalOpr:      total.OPR,
+			TotalDpr:      total.DPR,
+			TotalCcwm:     total.CCWM,
+			AutoOpr:       auto.OPR,
+			AutoDpr:       auto.DPR,
+			AutoCcwm:      auto.CCWM,
+			TeleOpOpr:     teleOp.OPR,
+			TeleOpDpr:     teleOp.DPR,
+			TeleOpCcwm:    teleOp.CCWM,
+			FoulsOpr:      fouls.OPR,
+			FoulsDpr:      fouls.DPR,
+			FoulsCcwm:     fouls.CCWM,
+		})
+	}
+	return table, nil
+}
+
+func (r oprRecord) csvRow() []string {
+	row := []string{strconv.Itoa(r.TeamNumber), strconv.Itoa(r.MatchesPlayed)}
+	for _, value := range []float64{
+		r.TotalOpr, r.TotalDpr, r.TotalCcwm,
+		r.AutoOpr, r.AutoDpr, r.AutoCcwm,
+		r.TeleOpOpr, r.TeleOpDpr, r.TeleOpCcwm,
+		r.FoulsOpr, r.FoulsDpr, r.FoulsCcwm,
+	} {
+		row = append(row, formatExportFloat(value))
+	}
+	return row
+}
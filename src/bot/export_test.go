@@ -0,0 +1,34 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+)
+
+func exportInteraction() *discordgo.InteractionCreate {
+	return sessiontest.NewInteraction("export-guild", "member", discordgo.ApplicationCommandInteractionData{Name: "event"})
+}
+
+func TestExportRankingsUsesGivenProvider(t *testing.T) {
+	other := &fakeProvider{
+		name:     "fake-export",
+		rankings: map[string][]ftcscout.EventTeam{"2025 USEXPQ1": fakeRankings(3)},
+	}
+	provider.Register(other)
+	session := sessiontest.New()
+
+	exportEvent("channel", other, "2025", "USEXPQ1", "rankings", "csv", session, exportInteraction())
+
+	sent, _ := session.Last()
+	if sent.Kind != "edit" || len(sent.Files) != 1 || !strings.HasPrefix(sent.Content, "2025 USEXPQ1 rankings (3 rows)") {
+		t.Fatalf("sent %+v, want the rankings from the provider that was passed in", sent)
+	}
+	if sent.Files[0].Name != "2025-USEXPQ1-rankings.csv" {
+		t.Errorf("file name = %q", sent.Files[0].Name)
+	}
+}
@@ -0,0 +1,84 @@
+// Package ftcevents is a client for the official FIRST FTC Events API (https://ftc-events.firstinspires.org/services/API).
+// It's used as a backup data source for when FTCScout is down or behind on event day.
+// The API needs an account, set FTC_EVENTS_USERNAME and FTC_EVENTS_TOKEN to use it.
+package ftcevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/httpretry"
+)
+
+const DefaultBaseURL = "https://ftc-api.firstinspires.org/v2.0"
+
+type Client struct {
+	// e.g. "https://ftc-api.firstinspires.org/v2.0", no trailing slash
+	BaseURL string
+
+	// credentials for http basic auth
+	Username string
+	Token    string
+
+	// timeout, retries, backoff and the Observe hook
+	httpretry.Retrier
+}
+
+func NewClient(baseURL, username, token string) *Client {
+	return &Client{
+		BaseURL:  baseURL,
+		Username: username,
+		Token:    token,
+		Retrier: httpretry.Retrier{
+			HTTPClient: &http.Client{},
+			Timeout:    15 * time.Second,
+			MaxRetries: 2,
+			Backoff:    500 * time.Millisecond,
+		},
+	}
+}
+
+// Default reads FTC_EVENTS_API_URL, FTC_EVENTS_USERNAME and FTC_EVENTS_TOKEN from the environment
+var Default = NewClient(func() string {
+	if url := os.Getenv("FTC_EVENTS_API_URL"); url != "" {
+		return url
+	}
+	return DefaultBaseURL
+}(), os.Getenv("FTC_EVENTS_USERNAME"), os.Getenv("FTC_EVENTS_TOKEN"))
+
+// ErrNotFound is matched (with errors.Is) by any APIError with a 404 status
+var ErrNotFound = errors.New("not found")
+
+// APIError is returned when the FTC Events API responds with a non-200 status
+type APIError struct {
+	StatusCode int
+	Path       string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("FTC Events API returned status %d for %s", e.StatusCode, e.Path)
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	return c.GetJSON(ctx, httpretry.Request{
+		BaseURL: c.BaseURL,
+		Path:    path,
+		Prepare: func(req *http.Request) {
+			if c.Username != "" || c.Token != "" {
+				req.SetBasicAuth(c.Username, c.Token)
+			}
+		},
+		StatusError: func(status int, body []byte) error {
+			return &APIError{StatusCode: status, Path: path, Body: string(body)}
+		},
+	}, out)
+}
@@ -0,0 +1,203 @@
+package ftcevents
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type Team struct {
+	TeamNumber  int    `json:"teamNumber"`
+	NameFull    string `json:"nameFull"`  // the long sponsor list name
+	NameShort   string `json:"nameShort"` // the name everyone actually uses
+	SchoolName  string `json:"schoolName"`
+	City        string `json:"city"`
+	StateProv   string `json:"stateProv"`
+	Country     string `json:"country"`
+	Website     string `json:"website"`
+	RookieYear  int    `json:"rookieYear"`
+	HomeRegion  string `json:"homeRegion"`
+	DisplayTeam string `json:"displayTeamNumber"`
+}
+
+type Award struct {
+	AwardId    int    `json:"awardId"`
+	EventCode  string `json:"eventCode"`
+	TeamNumber int    `json:"teamNumber"`
+	PersonName string `json:"personName"`
+	Name       string `json:"name"`
+	Series     int    `json:"series"`
+}
+
+type Event struct {
+	Code          string `json:"code"`
+	DivisionCode  string `json:"divisionCode"`
+	Name          string `json:"name"`
+	Published     bool   `json:"published"`
+	TypeName      string `json:"typeName"`
+	RegionCode    string `json:"regionCode"`
+	LeagueCode    string `json:"leagueCode"`
+	DistrictCode  string `json:"districtCode"`
+	Venue         string `json:"venue"`
+	Address       string `json:"address"`
+	City          string `json:"city"`
+	StateProv     string `json:"stateprov"`
+	Country       string `json:"country"`
+	Website       string `json:"website"`
+	LiveStreamUrl string `json:"liveStreamUrl"`
+	Timezone      string `json:"timezone"`
+	DateStart     string `json:"dateStart"` // e.g. "2025-01-11T00:00:00"
+	DateEnd       string `json:"dateEnd"`
+}
+
+type MatchTeam struct {
+	TeamNumber int    `json:"teamNumber"`
+	Station    string `json:"station"` // "Red1", "Blue2", etc
+	Dq         bool   `json:"dq"`
+	OnField    bool   `json:"onField"`
+}
+
+// Match is used for both results and the schedule, StartTime is only in the schedule
+// and the scores are only in the results
+type Match struct {
+	StartTime       string      `json:"startTime"`
+	ActualStartTime string      `json:"actualStartTime"`
+	PostResultTime  string      `json:"postResultTime"`
+	Description     string      `json:"description"`
+	TournamentLevel string      `json:"tournamentLevel"` // "QUALIFICATION" or "PLAYOFF"
+	Series          int         `json:"series"`
+	MatchNumber     int         `json:"matchNumber"`
+	Field           string      `json:"field"`
+	ScoreRedFinal   int         `json:"scoreRedFinal"`
+	ScoreRedFoul    int         `json:"scoreRedFoul"`
+	ScoreRedAuto    int         `json:"scoreRedAuto"`
+	ScoreBlueFinal  int         `json:"scoreBlueFinal"`
+	ScoreBlueFoul   int         `json:"scoreBlueFoul"`
+	ScoreBlueAuto   int         `json:"scoreBlueAuto"`
+	Teams           []MatchTeam `json:"teams"`
+}
+
+type Ranking struct {
+	Rank          int     `json:"rank"`
+	TeamNumber    int     `json:"teamNumber"`
+	TeamName      string  `json:"teamName"`
+	SortOrder1    float64 `json:"sortOrder1"`
+	SortOrder2    float64 `json:"sortOrder2"`
+	Wins          int     `json:"wins"`
+	Losses        int     `json:"losses"`
+	Ties          int     `json:"ties"`
+	QualAverage   float64 `json:"qualAverage"`
+	MatchesPlayed int     `json:"matchesPlayed"`
+}
+
+// Alliance is a playoff alliance, the picks are null (so 0) until they're made
+type Alliance struct {
+	Number  int    `json:"number"`
+	Name    string `json:"name"`
+	Captain int    `json:"captain"`
+	Round1  int    `json:"round1"` // first pick
+	Round2  int    `json:"round2"` // second pick
+	Round3  int    `json:"round3"` // only used at events with 4 team alliances
+	Backup  int    `json:"backup"`
+}
+
+func (c *Client) Team(ctx context.Context, season, teamNumber string) (*Team, error) {
+	var resp struct {
+		Teams []Team `json:"teams"`
+	}
+	path := fmt.Sprintf("/%s/teams?teamNumber=%s", url.PathEscape(season), url.QueryEscape(teamNumber))
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Teams) == 0 {
+		return nil, &APIError{StatusCode: 404, Path: path}
+	}
+	return &resp.Teams[0], nil
+}
+
+// TeamAwards is every award a team won in a season, the event code is left out of the path so it isn't read as one
+func (c *Client) TeamAwards(ctx context.Context, season, teamNumber string) ([]Award, error) {
+	var resp struct {
+		Awards []Award `json:"awards"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/%s/awards?teamNumber=%s", url.PathEscape(season), url.QueryEscape(teamNumber)), &resp)
+	return resp.Awards, err
+}
+
+// TeamEvents is every event a team is registered for in a season
+func (c *Client) TeamEvents(ctx context.Context, season, teamNumber string) ([]Event, error) {
+	var resp struct {
+		Events []Event `json:"events"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/%s/events?teamNumber=%s", url.PathEscape(season), url.QueryEscape(teamNumber)), &resp)
+	return resp.Events, err
+}
+
+func (c *Client) EventAwards(ctx context.Context, season, eventCode string) ([]Award, error) {
+	var resp struct {
+		Awards []Award `json:"awards"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/%s/awards/%s", url.PathEscape(season), url.PathEscape(eventCode)), &resp)
+	return resp.Awards, err
+}
+
+func (c *Client) Event(ctx context.Context, season, eventCode string) (*Event, error) {
+	var resp struct {
+		Events []Event `json:"events"`
+	}
+	path := fmt.Sprintf("/%s/events?eventCode=%s", url.PathEscape(season), url.QueryEscape(eventCode))
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Events) == 0 {
+		return nil, &APIError{StatusCode: 404, Path: path}
+	}
+	return &resp.Events[0], nil
+}
+
+// Matches returns the results for one tournament level ("qual" or "playoff")
+func (c *Client) Matches(ctx context.Context, season, eventCode, tournamentLevel string) ([]Match, error) {
+	var resp struct {
+		Matches []Match `json:"matches"`
+	}
+	path := fmt.Sprintf("/%s/matches/%s?tournamentLevel=%s", url.PathEscape(season), url.PathEscape(eventCode), url.QueryEscape(tournamentLevel))
+	err := c.get(ctx, path, &resp)
+	return resp.Matches, err
+}
+
+// Schedule returns every scheduled match for one tournament level ("qual" or "playoff"), played or not
+func (c *Client) Schedule(ctx context.Context, season, eventCode, tournamentLevel string) ([]Match, error) {
+	var resp struct {
+		Schedule []Match `json:"schedule"`
+	}
+	path := fmt.Sprintf("/%s/schedule/%s?tournamentLevel=%s", url.PathEscape(season), url.PathEscape(eventCode), url.QueryEscape(tournamentLevel))
+	err := c.get(ctx, path, &resp)
+	return resp.Schedule, err
+}
+
+// Alliances returns the playoff alliances, which is empty until alliance selection is over
+func (c *Client) Alliances(ctx context.Context, season, eventCode string) ([]Alliance, error) {
+	var resp struct {
+		Alliances []Alliance `json:"alliances"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/%s/alliances/%s", url.PathEscape(season), url.PathEscape(eventCode)), &resp)
+	return resp.Alliances, err
+}
+
+func (c *Client) Rankings(ctx context.Context, season, eventCode string) ([]Ranking, error) {
+	var resp struct {
+		Rankings []Ranking `json:"rankings"`
+	}
+	err := c.get(ctx, fmt.Sprintf("/%s/rankings/%s", url.PathEscape(season), url.PathEscape(eventCode)), &resp)
+	return resp.Rankings, err
+}
+
+// TeamRanking is one team's row of the rankings, empty if the team hasn't played at the event yet
+func (c *Client) TeamRanking(ctx context.Context, season, eventCode, teamNumber string) ([]Ranking, error) {
+	var resp struct {
+		Rankings []Ranking `json:"rankings"`
+	}
+	path := fmt.Sprintf("/%s/rankings/%s?teamNumber=%s", url.PathEscape(season), url.PathEscape(eventCode), url.QueryEscape(teamNumber))
+	err := c.get(ctx, path, &resp)
+	return resp.Rankings, err
+}
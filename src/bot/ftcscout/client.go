@@ -0,0 +1,78 @@
+// Package ftcscout is a small typed client for the FTCScout REST API (https://ftcscout.org/api/rest).
+// Every command goes through this instead of calling http.Get directly so we get timeouts, retries
+// and consistent error handling in one place, and so the base URL can be pointed at a stub server.
+package ftcscout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/httpretry"
+)
+
+const DefaultBaseURL = "https://api.ftcscout.org/rest/v1"
+
+type Client struct {
+	// e.g. "https://api.ftcscout.org/rest/v1", no trailing slash
+	BaseURL string
+
+	// timeout, retries, backoff and the Observe hook
+	httpretry.Retrier
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL: baseURL,
+		Retrier: httpretry.Retrier{
+			HTTPClient: &http.Client{},
+			Timeout:    15 * time.Second,
+			MaxRetries: 3,
+			Backoff:    500 * time.Millisecond,
+		},
+	}
+}
+
+// Default is the client every command uses, set FTCSCOUT_API_URL to point it somewhere else
+var Default = NewClient(func() string {
+	if url := os.Getenv("FTCSCOUT_API_URL"); url != "" {
+		return url
+	}
+	return DefaultBaseURL
+}())
+
+// ErrNotFound is matched (with errors.Is) by any APIError with a 404 status
+var ErrNotFound = errors.New("not found")
+
+// APIError is returned when FTCScout responds with a non-200 status
+type APIError struct {
+	StatusCode int
+	Path       string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("FTCScout returned status %d for %s", e.StatusCode, e.Path)
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// get fetches BaseURL+path and decodes the JSON body into out, retrying with backoff when it makes sense
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	return c.GetJSON(ctx, httpretry.Request{
+		BaseURL: c.BaseURL,
+		Path:    path,
+		StatusError: func(status int, body []byte) error {
+			return &APIError{StatusCode: status, Path: path, Body: string(body)}
+		},
+	}, out)
+}
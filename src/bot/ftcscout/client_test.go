@@ -0,0 +1,179 @@
+package ftcscout
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient points a client at a stub server with short delays so retries don't slow the tests down
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL)
+	client.Backoff = time.Millisecond
+	client.Timeout = time.Second
+	return client
+}
+
+func TestTeamUsesBaseURL(t *testing.T) {
+	var gotPath, gotAccept string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAccept = r.Header.Get("Accept")
+		w.Write([]byte(`{"number": 16271, "name": "Roboknights"}`))
+	})
+
+	team, err := client.Team(context.Background(), "16271")
+	if err != nil {
+		t.Fatalf("Team: %v", err)
+	}
+	if gotPath != "/teams/16271" {
+		t.Errorf("path = %q, want /teams/16271", gotPath)
+	}
+	if gotAccept != "application/json" {
+		t.Errorf("Accept = %q, want application/json", gotAccept)
+	}
+	if team.Number != 16271 || team.Name != "Roboknights" {
+		t.Errorf("team = %+v", team)
+	}
+}
+
+func TestRetriesServerErrors(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusTooManyRequests} {
+		var calls atomic.Int32
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(status)
+				return
+			}
+			w.Write([]byte(`[]`))
+		})
+
+		if _, err := client.TeamAwards(context.Background(), "1"); err != nil {
+			t.Errorf("status %d: TeamAwards: %v", status, err)
+		}
+		if calls.Load() != 3 {
+			t.Errorf("status %d: %d calls, want 3", status, calls.Load())
+		}
+	}
+}
+
+func TestGivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client.MaxRetries = 2
+
+	_, err := client.TeamAwards(context.Background(), "1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want a 503 APIError", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("%d calls, want 3", calls.Load())
+	}
+}
+
+func TestDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	if _, err := client.TeamAwards(context.Background(), "1"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d calls, want 1", calls.Load())
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	_, err := client.Team(context.Background(), "99999")
+	if !IsNotFound(err) || !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var firstCall time.Time
+	var waited time.Duration
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			firstCall = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		waited = time.Since(firstCall)
+		w.Write([]byte(`[]`))
+	})
+	client.Timeout = 5 * time.Second
+
+	if _, err := client.TeamAwards(context.Background(), "1"); err != nil {
+		t.Fatalf("TeamAwards: %v", err)
+	}
+	if waited < time.Second {
+		t.Errorf("retried after %v, want at least the 1s from Retry-After", waited)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	client.Timeout = 50 * time.Millisecond
+
+	started := time.Now()
+	_, err := client.Team(context.Background(), "1")
+	if err == nil {
+		t.Fatal("expected a timeout")
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("took %v, the timeout should have stopped it", elapsed)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+
+	var statuses []int
+	client.Observe = func(path string, status int, elapsed time.Duration) {
+		if path != "/teams/1" {
+			t.Errorf("observed path %q", path)
+		}
+		statuses = append(statuses, status)
+	}
+
+	if _, err := client.Team(context.Background(), "1"); err != nil {
+		t.Fatalf("Team: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0] != 500 || statuses[1] != 200 {
+		t.Errorf("observed %v, want [500 200]", statuses)
+	}
+}
@@ -0,0 +1,76 @@
+package ftcscout
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+func (c *Client) Team(ctx context.Context, teamNumber string) (*Team, error) {
+	var team Team
+	if err := c.get(ctx, fmt.Sprintf("/teams/%s", url.PathEscape(teamNumber)), &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (c *Client) TeamAwards(ctx context.Context, teamNumber string) ([]Award, error) {
+	var awards []Award
+	err := c.get(ctx, fmt.Sprintf("/teams/%s/awards", url.PathEscape(teamNumber)), &awards)
+	return awards, err
+}
+
+func (c *Client) TeamQuickStats(ctx context.Context, teamNumber string) (*QuickStats, error) {
+	var stats QuickStats
+	if err := c.get(ctx, fmt.Sprintf("/teams/%s/quick-stats", url.PathEscape(teamNumber)), &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// TeamEvents returns every event a team attended in a season along with how they did there
+func (c *Client) TeamEvents(ctx context.Context, teamNumber, season string) ([]EventTeam, error) {
+	var events []EventTeam
+	err := c.get(ctx, fmt.Sprintf("/teams/%s/events/%s", url.PathEscape(teamNumber), url.PathEscape(season)), &events)
+	return events, err
+}
+
+// SearchTeams returns every team registered in a region (e.g. "USCASD", or "All")
+func (c *Client) SearchTeams(ctx context.Context, regionCode string) ([]Team, error) {
+	var teams []Team
+	err := c.get(ctx, "/teams/search?region="+url.QueryEscape(regionCode), &teams)
+	return teams, err
+}
+
+func (c *Client) Event(ctx context.Context, season, eventCode string) (*Event, error) {
+	var event Event
+	if err := c.get(ctx, fmt.Sprintf("/events/%s/%s", url.PathEscape(season), url.PathEscape(eventCode)), &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (c *Client) EventTeams(ctx context.Context, season, eventCode string) ([]EventTeam, error) {
+	var teams []EventTeam
+	err := c.get(ctx, fmt.Sprintf("/events/%s/%s/teams", url.PathEscape(season), url.PathEscape(eventCode)), &teams)
+	return teams, err
+}
+
+func (c *Client) EventMatches(ctx context.Context, season, eventCode string) ([]Match, error) {
+	var matches []Match
+	err := c.get(ctx, fmt.Sprintf("/events/%s/%s/matches", url.PathEscape(season), url.PathEscape(eventCode)), &matches)
+	return matches, err
+}
+
+func (c *Client) EventAwards(ctx context.Context, season, eventCode string) ([]Award, error) {
+	var awards []Award
+	err := c.get(ctx, fmt.Sprintf("/events/%s/%s/awards", url.PathEscape(season), url.PathEscape(eventCode)), &awards)
+	return awards, err
+}
+
+// SearchEvents returns every event in a season
+func (c *Client) SearchEvents(ctx context.Context, season string) ([]Event, error) {
+	var events []Event
+	err := c.get(ctx, fmt.Sprintf("/events/search/%s", url.PathEscape(season)), &events)
+	return events, err
+}
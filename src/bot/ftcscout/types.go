@@ -0,0 +1,159 @@
+package ftcscout
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Team struct {
+	Number     int      `json:"number"`
+	Name       string   `json:"name"`
+	SchoolName string   `json:"schoolName"`
+	Sponsors   []string `json:"sponsors"`
+	Country    string   `json:"country"`
+	State      string   `json:"state"`
+	City       string   `json:"city"`
+	RookieYear int      `json:"rookieYear"`
+	Website    string   `json:"website"`
+	CreatedAt  string   `json:"createdAt"`
+	UpdatedAt  string   `json:"updatedAt"`
+}
+
+type Award struct {
+	Season       int    `json:"season"`
+	EventCode    string `json:"eventCode"`
+	TeamNumber   int    `json:"teamNumber"`
+	Type         string `json:"type"`
+	Placement    int    `json:"placement"`
+	DivisionName string `json:"divisionName"`
+	PersonName   string `json:"personName"`
+	CreatedAt    string `json:"createdAt"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+type StatValue struct {
+	Value float64 `json:"value"`
+	Rank  int     `json:"rank"`
+}
+
+type QuickStats struct {
+	Season int       `json:"season"`
+	Number int       `json:"number"`
+	Tot    StatValue `json:"tot"`
+	Auto   StatValue `json:"auto"`
+	Dc     StatValue `json:"dc"`
+	Eg     StatValue `json:"eg"`
+	Count  int       `json:"count"`
+}
+
+type Event struct {
+	Season        int    `json:"season"`
+	Code          string `json:"code"`
+	DivisionCode  string `json:"divisionCode"`
+	Name          string `json:"name"`
+	FieldCount    int    `json:"fieldCount"`
+	Published     bool   `json:"published"`
+	Type          string `json:"type"`
+	RegionCode    string `json:"regionCode"`
+	LeagueCode    string `json:"leagueCode"`
+	DistrictCode  string `json:"districtCode"`
+	Venue         string `json:"venue"`
+	Address       string `json:"address"`
+	Country       string `json:"country"`
+	State         string `json:"state"`
+	City          string `json:"city"`
+	Website       string `json:"website"`
+	LiveStreamUrl string `json:"liveStreamUrl"`
+	Timezone      string `json:"timezone"`
+	Start         string `json:"start"`
+	End           string `json:"end"`
+	Ongoing       bool   `json:"ongoing"`
+	ModifiedRules bool   `json:"modifiedRules"`
+	HasMatches    bool   `json:"hasMatches"`
+}
+
+// EventTeam is one entry of /events/{season}/{code}/teams, stats is null until the team has played
+type EventTeam struct {
+	Season     int    `json:"season"`
+	EventCode  string `json:"eventCode"`
+	TeamNumber int    `json:"teamNumber"`
+	// ftcscout leaves this out, other backends fill it in when they have it
+	TeamName string          `json:"teamName,omitempty"`
+	Stats    *EventTeamStats `json:"stats"`
+}
+
+type EventTeamStats struct {
+	Rank          int       `json:"rank"`
+	RP            float64   `json:"rp"`
+	TBP           float64   `json:"tb1"`
+	Wins          int       `json:"wins"`
+	Losses        int       `json:"losses"`
+	Ties          int       `json:"ties"`
+	MatchesPlayed int       `json:"qualMatchesPlayed"`
+	Opr           *OPRStats `json:"opr"`
+}
+
+// OPRStats is FTCScout's per component OPR, only the total is the same across seasons
+type OPRStats struct {
+	TotalPoints float64 `json:"totalPoints"`
+}
+
+// MatchTeam is a team's slot in a match
+type MatchTeam struct {
+	AllianceColor string `json:"alliance"`
+	AllianceRole  string `json:"allianceRole"`
+	TeamNumber    int    `json:"teamNumber"`
+}
+
+type AllianceScore struct {
+	Total  int `json:"totalPoints"`
+	Auto   int `json:"autoPoints"`
+	TeleOp int `json:"dcPoints"` // "driver controlled"
+	Fouls  int `json:"penaltyPointsByOpp"`
+}
+
+type MatchScores struct {
+	Red  AllianceScore `json:"red"`
+	Blue AllianceScore `json:"blue"`
+}
+
+type Match struct {
+	ID                 int         `json:"id"`
+	MatchNumber        int         `json:"matchNum"`
+	HasBeenPlayed      bool        `json:"hasBeenPlayed"`
+	ScheduledStartTime string      `json:"scheduledStartTime"`
+	ActualStartTime    string      `json:"actualStartTime"`
+	TournamentLevel    string      `json:"tournamentLevel"`
+	Series             int         `json:"series"`
+	Field              Field       `json:"field"`
+	Scores             MatchScores `json:"scores"`
+	Teams              []MatchTeam `json:"teams"`
+}
+
+// EventAlliance is a playoff alliance, second pick and backup are 0 when there isn't one
+type EventAlliance struct {
+	Seed       int `json:"seed"`
+	Captain    int `json:"captain"`
+	FirstPick  int `json:"firstPick"`
+	SecondPick int `json:"secondPick"`
+	Backup     int `json:"backup"`
+}
+
+// Field is the field a match is played on. Some APIs send it as a number and some as a string,
+// so it accepts both and keeps the string.
+type Field string
+
+func (f *Field) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*f = Field(name)
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid field %s", string(data))
+	}
+	*f = Field(number.String())
+	return nil
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
-	"github.com/shuban-789/bjorn/src/bot/util"
+	"github.com/shuban-789/bjorn/src/bot/logging"
 )
 
 func init() {
@@ -48,8 +48,8 @@ func init() {
 	)
 }
 
-func sayCommandHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	fmt.Println(util.Info("Received /say command from %s#%s", i.Member.User.Username, i.Member.User.Discriminator))
+func sayCommandHandler(s interactions.Session, i *discordgo.InteractionCreate) {
+	logger := logging.WithInteraction(commandLog, i)
 
 	hasManageServer := i.Member != nil && i.Member.Permissions&discordgo.PermissionManageServer != 0
 	hasAdministrator := i.Member != nil && i.Member.Permissions&discordgo.PermissionAdministrator != 0
@@ -66,8 +66,9 @@ func sayCommandHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 	data := i.ApplicationCommandData()
 	text := data.Options[0].StringValue()
-	channel := data.Options[1].ChannelValue(s)
-	fmt.Println(util.Info("Sending message to channel %s: %s", channel.ID, text))
+	// only the ID is needed, with no session discordgo skips fetching the whole channel
+	channel := data.Options[1].ChannelValue(nil)
+	logger.Info("Sending /say message", "to", channel.ID, "text", text)
 
 	var messageID string
 	if len(data.Options) > 2 && data.Options[2].StringValue() != "" {
@@ -76,14 +77,17 @@ func sayCommandHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		parts := strings.Split(replyLink, "/")
 		if len(parts) >= 7 {
 			messageID = parts[len(parts)-1]
-			fmt.Println(util.Info("Replying to message: %s", messageID))
+			logger.Debug("Replying to message", "message", messageID)
 		}
 	}
 
 	var err error
 	if messageID != "" {
-		_, err = s.ChannelMessageSendReply(channel.ID, text, &discordgo.MessageReference{
-			MessageID: messageID,
+		_, err = s.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+			Content: text,
+			Reference: &discordgo.MessageReference{
+				MessageID: messageID,
+			},
 		})
 	} else {
 		_, err = s.ChannelMessageSend(channel.ID, text)
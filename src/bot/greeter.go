@@ -4,29 +4,38 @@ import (
 	"fmt"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/shuban-789/bjorn/src/bot/util"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/search"
 )
 
 func memberJoinListener(session *discordgo.Session, event *discordgo.GuildMemberAdd) {
-	fmt.Println(util.Info("New member joined: %s", event.User.Username))
+	logger := botLog.With("guild", event.GuildID, "user", event.User.ID)
+	logger.Info("New member joined", "username", event.User.Username)
 	channel, err := session.UserChannelCreate(event.User.ID)
 	if err != nil {
-		fmt.Println(util.Fail("Failed to create DM channel: %v", err))
+		logger.Error("Failed to create DM channel", "err", err)
 		return
 	}
 
-	greet(channel.ID, session)
+	greet(channel.ID, event.GuildID, session)
 }
 
-func greet(ChannelID string, session *discordgo.Session) {
+func greet(ChannelID string, guildID string, session *discordgo.Session) {
+	cfg := guildconfig.Get(guildID)
+
+	title := "Welcome!"
+	if guild, err := session.State.Guild(guildID); err == nil && guild.Name != "" {
+		title = fmt.Sprintf("Welcome to %s!", guild.Name)
+	}
+
 	embed := &discordgo.MessageEmbed{
-		Title:       "Welcome to the San Diego FTC Discord Server!",
-		Description: "Get started with the information below",
+		Title:       title,
+		Description: cfg.WelcomeText,
 		Color:       0x72cfdd,
 		Fields: []*discordgo.MessageEmbedField{
 			&discordgo.MessageEmbedField{
 				Name:  "1️⃣ Get your team's role!",
-				Value: "Use `>>roleme [team_id]` to get your team's role. If you are in a SD team, we have your team's role.\n",
+				Value: fmt.Sprintf("Use `%sroleme [team_id]` to get your team's role. If you are on a team in %s, we have your team's role.\n", cfg.CommandPrefix, search.GetRegionName(cfg.HomeRegion)),
 			},
 			&discordgo.MessageEmbedField{
 				Name:  "2️⃣ Remember to practice Gracious Professionalism!",
@@ -34,7 +43,7 @@ func greet(ChannelID string, session *discordgo.Session) {
 			},
 			&discordgo.MessageEmbedField{
 				Name:  "3️⃣ Have fun!",
-				Value: "Reach out to the mods for any help, use `>>help` to see what I can help you with.\n",
+				Value: fmt.Sprintf("Reach out to the mods for any help, use `%shelp` to see what I can help you with.\n", cfg.CommandPrefix),
 			},
 		},
 	}
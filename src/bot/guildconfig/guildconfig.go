@@ -0,0 +1,195 @@
+// Package guildconfig stores per-server settings so more than one FTC community can run Bjorn.
+// Anything a server hasn't set falls back to Defaults, which match how the San Diego server has always worked.
+package guildconfig
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/render"
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+var logger = logging.For("guildconfig")
+
+type Config struct {
+	// region code from regions.csv, e.g. "USCASD"
+	HomeRegion string `json:"homeRegion,omitempty"`
+
+	// prefix for text commands, e.g. ">>"
+	CommandPrefix string `json:"commandPrefix,omitempty"`
+
+	// shown under the title of the welcome DM new members get
+	WelcomeText string `json:"welcomeText,omitempty"`
+
+	// if set, match tracker updates are posted here instead of where the tracker was started
+	AnnouncementChannelId string `json:"announcementChannelId,omitempty"`
+
+	// members with this role and a team role are pinged when their team is on deck, compared case-insensitively with dashes as spaces
+	MatchPingRole string `json:"matchPingRole,omitempty"`
+
+	// season used when a command's year is left empty, e.g. "2025"
+	DefaultSeason string `json:"defaultSeason,omitempty"`
+
+	// data provider spec for provider.Parse, empty means the global default
+	DataProvider string `json:"dataProvider,omitempty"`
+
+	// name of the render theme brackets and charts are drawn with, e.g. "dark"
+	Theme string `json:"theme,omitempty"`
+
+	// how many matches ahead the tracker warns teams they're on deck, 0 turns alerts off.
+	// it's a pointer so an explicit 0 isn't mistaken for unset
+	OnDeckMatches *int `json:"onDeckMatches,omitempty"`
+
+	// if set, on deck alerts go here (can be a thread) instead of the tracker's update channel
+	OnDeckChannelId string `json:"onDeckChannelId,omitempty"`
+}
+
+var Defaults = Config{
+	HomeRegion:    "USCASD",
+	CommandPrefix: ">>",
+	WelcomeText:   "Get started with the information below",
+	MatchPingRole: "match pings",
+	DefaultSeason: "2025",
+	Theme:         render.DefaultTheme.Name,
+	OnDeckMatches: &defaultOnDeckMatches,
+}
+
+var defaultOnDeckMatches = 3
+
+var (
+	configStore = store.New[map[string]Config]("guild_configs")
+
+	configs map[string]Config
+	mu      sync.RWMutex
+
+	// set when the stored configs couldn't be read, saving then would replace every guild's settings with just the change
+	loadErr error
+)
+
+// load needs mu held for writing
+func load() {
+	if configs != nil {
+		return
+	}
+
+	loaded, err := configStore.Load()
+	loadErr = err
+	if err != nil {
+		logger.Error("Failed to load guild configs", "err", err)
+	}
+	if loaded == nil {
+		loaded = make(map[string]Config)
+	}
+	configs = loaded
+}
+
+// Raw returns only what the guild has explicitly set, without defaults filled in
+func Raw(guildID string) Config {
+	mu.RLock()
+	loaded := configs != nil
+	cfg := configs[guildID]
+	mu.RUnlock()
+
+	if !loaded {
+		mu.Lock()
+		load()
+		cfg = configs[guildID]
+		mu.Unlock()
+	}
+
+	// copy so the caller can't change the stored config through the pointer
+	if cfg.OnDeckMatches != nil {
+		onDeck := *cfg.OnDeckMatches
+		cfg.OnDeckMatches = &onDeck
+	}
+	return cfg
+}
+
+// Get returns the guild's config with every unset field filled from Defaults.
+// guildID can be empty (e.g. in DMs), which just returns Defaults.
+func Get(guildID string) Config {
+	cfg := Raw(guildID)
+	if cfg.HomeRegion == "" {
+		cfg.HomeRegion = Defaults.HomeRegion
+	}
+	if cfg.CommandPrefix == "" {
+		cfg.CommandPrefix = Defaults.CommandPrefix
+	}
+	if cfg.WelcomeText == "" {
+		cfg.WelcomeText = Defaults.WelcomeText
+	}
+	if cfg.AnnouncementChannelId == "" {
+		cfg.AnnouncementChannelId = Defaults.AnnouncementChannelId
+	}
+	if cfg.MatchPingRole == "" {
+		cfg.MatchPingRole = Defaults.MatchPingRole
+	}
+	if cfg.DefaultSeason == "" {
+		cfg.DefaultSeason = Defaults.DefaultSeason
+	}
+	if cfg.DataProvider == "" {
+		cfg.DataProvider = Defaults.DataProvider
+	}
+	if cfg.Theme == "" {
+		cfg.Theme = Defaults.Theme
+	}
+	if cfg.OnDeckMatches == nil && Defaults.OnDeckMatches != nil {
+		onDeck := *Defaults.OnDeckMatches
+		cfg.OnDeckMatches = &onDeck
+	}
+	return cfg
+}
+
+// Update applies fn to the guild's stored config and saves it
+func Update(guildID string, fn func(cfg *Config)) error {
+	if guildID == "" {
+		return fmt.Errorf("settings can only be changed in a server")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if loadErr != nil {
+		// try again in case whatever broke the file has been fixed
+		configs = nil
+	}
+	load()
+	if loadErr != nil {
+		return fmt.Errorf("guild configs couldn't be loaded so nothing was saved: %w", loadErr)
+	}
+
+	cfg := configs[guildID]
+	fn(&cfg)
+	configs[guildID] = cfg
+	return configStore.Save(configs)
+}
+
+// SeasonOrDefault returns season unless it's empty, in which case the guild's default season is used
+func SeasonOrDefault(guildID, season string) string {
+	if season != "" {
+		return season
+	}
+	return Get(guildID).DefaultSeason
+}
+
+// Provider returns the data provider the guild picked, or the global default
+func Provider(guildID string) provider.Provider {
+	spec := Get(guildID).DataProvider
+	if spec == "" {
+		return provider.Default()
+	}
+
+	p, err := provider.Parse(spec)
+	if err != nil {
+		logger.Warn("Guild has an invalid data provider", "guild", guildID, "provider", spec, "err", err)
+		return provider.Default()
+	}
+	return p
+}
+
+// Theme returns the theme the guild draws images with
+func Theme(guildID string) render.Theme {
+	return render.GetTheme(Get(guildID).Theme)
+}
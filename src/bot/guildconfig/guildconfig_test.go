@@ -0,0 +1,69 @@
+package guildconfig
+
+import (
+	"os"
+	"testing"
+
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+// useStore points the package at a fresh data dir holding contents (if any) as the stored configs
+func useStore(t *testing.T, contents string) {
+	t.Helper()
+	dir := store.Dir
+	store.Dir = t.TempDir()
+	configs, loadErr = nil, nil
+	t.Cleanup(func() {
+		store.Dir = dir
+		configs, loadErr = nil, nil
+	})
+
+	if contents != "" {
+		if err := os.WriteFile(configStore.Path(), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUpdateRefusesToSaveAfterFailedLoad(t *testing.T) {
+	const broken = `{"guild-a": {"homeRegion": "USTX"`
+	useStore(t, broken)
+
+	if got := Get("guild-a").HomeRegion; got != Defaults.HomeRegion {
+		t.Errorf("home region = %q, want the default while the file is broken", got)
+	}
+	if err := Update("guild-b", func(cfg *Config) { cfg.HomeRegion = "USNY" }); err == nil {
+		t.Fatal("Update saved even though the configs failed to load")
+	}
+	if data, _ := os.ReadFile(configStore.Path()); string(data) != broken {
+		t.Fatalf("file was overwritten with %s", data)
+	}
+
+	// once the file is readable again updates go through and keep the other guilds
+	if err := os.WriteFile(configStore.Path(), []byte(`{"guild-a": {"homeRegion": "USTX"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Update("guild-b", func(cfg *Config) { cfg.HomeRegion = "USNY" }); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if a, b := Get("guild-a").HomeRegion, Get("guild-b").HomeRegion; a != "USTX" || b != "USNY" {
+		t.Errorf("home regions = %q, %q, want USTX, USNY", a, b)
+	}
+}
+
+func TestGetReturnsCopies(t *testing.T) {
+	useStore(t, "")
+	if err := Update("guild", func(cfg *Config) { n := 5; cfg.OnDeckMatches = &n }); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	*Get("guild").OnDeckMatches = 9
+	*Get("other").OnDeckMatches = 9
+
+	if got := *Get("guild").OnDeckMatches; got != 5 {
+		t.Errorf("guild's on deck matches = %d, want 5", got)
+	}
+	if got := *Get("other").OnDeckMatches; got != 3 {
+		t.Errorf("default on deck matches = %d, want 3", got)
+	}
+}
@@ -1,8 +1,12 @@
 package bot
 
 import (
+	"fmt"
+
 	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/search"
 )
 
 func init() {
@@ -11,15 +15,18 @@ func init() {
 			Name:        "help",
 			Description: "Displays help information about the bot commands.",
 		},
-		func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
 			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
 			helpcmd(s, nil, i)
 		},
 	)
 }
 
-func helpcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate) {
+func helpcmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate) {
 	channelID := interactions.GetChannelId(message, i)
+	guildID, _ := interactions.GetGuildId(message, i)
+	cfg := guildconfig.Get(guildID)
+	prefix := cfg.CommandPrefix
 
 	embed := &discordgo.MessageEmbed{
 		Title:       "Help",
@@ -27,33 +34,65 @@ func helpcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *di
 		Color:       0x72cfdd,
 		Fields: []*discordgo.MessageEmbedField{
 			&discordgo.MessageEmbedField{
-				Name:  "`>>help`",
+				Name:  fmt.Sprintf("`%shelp`", prefix),
 				Value: "Display this message\n",
 			},
 			&discordgo.MessageEmbedField{
-				Name:  "`>>lead [year] [event_code]`",
-				Value: "Display the leaderboard for a certain event\n",
+				Name:  fmt.Sprintf("`%slead [year] [event_code] [optional: rank, opr, rp]`", prefix),
+				Value: "Display the leaderboard for a certain event, with record, RP, TBP and OPR\n",
+			},
+			&discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("`%sevent opr [year] [event_code] [optional: quals, all]`", prefix),
+				Value: "Show OPR, DPR and CCWM for every team at an event\n",
+			},
+			&discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("`%sevent schedule [year] [event_code] [optional: team_id]`", prefix),
+				Value: "Show the qualification schedule in your local time\n",
 			},
 			&discordgo.MessageEmbedField{
-				Name:  "`>>match info [year] [event_code] [match_number]`",
+				Name:  fmt.Sprintf("`%sevent export [year] [event_code] [rankings, matches, awards, opr] [optional: csv, json]`", prefix),
+				Value: "Download event data as a CSV or JSON file\n",
+			},
+			&discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("`%smatch info [year] [event_code] [match_number]`", prefix),
 				Value: "Lookup information about a certain match\n",
 			},
 			&discordgo.MessageEmbedField{
-				Name:  "`>>match eventstart [year] [event_code]`",
+				Name:  fmt.Sprintf("`%smatch eventstart [year] [event_code]`", prefix),
 				Value: "Start an active match tracker for a current even\n",
 			},
 			&discordgo.MessageEmbedField{
-				Name:  "`>>ping`",
+				Name:  fmt.Sprintf("`%swatch [add, remove, list] [team_id]`", prefix),
+				Value: "Get a DM whenever a team plays a match or wins an award\n",
+			},
+			&discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("`%sscout view [team_id] [optional: event_code]`", prefix),
+				Value: "Read this server's scouting notes on a team (write them with `/scout note`)\n",
+			},
+			&discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("`%sscout form [optional: year]`", prefix),
+				Value: "Show the match scouting form (fill it in with `/scout match`, admins change it with `/scout form`)\n",
+			},
+			&discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("`%sscout export [year] [event_code]`", prefix),
+				Value: "Download each team's match scouting averages and maxima as a CSV\n",
+			},
+			&discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("`%sping`", prefix),
 				Value: "Get bot response latency",
 			},
 			&discordgo.MessageEmbedField{
-				Name:  "`>>roleme [team_id]`",
-				Value: "Assign yourself a role based on your team\nnumber (San Diego FTC teams only)\n",
+				Name:  fmt.Sprintf("`%sroleme [team_id]`", prefix),
+				Value: fmt.Sprintf("Assign yourself a role based on your team\nnumber (%s teams are suggested first)\n", search.GetRegionName(cfg.HomeRegion)),
 			},
 			&discordgo.MessageEmbedField{
-				Name:  "`>>team [team_id] [optional: stats, awards]`",
+				Name:  fmt.Sprintf("`%steam [team_id] [optional: stats, awards, history [year]]`", prefix),
 				Value: "Return information about a team\n",
 			},
+			&discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("`%steam compare [team_id] [team_id] [optional: up to 2 more]`", prefix),
+				Value: "Compare stats and awards for two to four teams side by side\n",
+			},
 		},
 	}
 	interactions.SendEmbed(session, i, channelID, embed)
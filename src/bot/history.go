@@ -0,0 +1,221 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/pagination"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/search"
+	"github.com/shuban-789/bjorn/src/bot/util"
+)
+
+var (
+	historyPaginator *pagination.Paginator[TeamEventHistory]
+
+	// keyed by "provider teamNumber season"
+	historyCache = util.NewCache(
+		100,
+		time.Minute*30,
+		getTeamHistory,
+	)
+)
+
+// TeamEventHistory is how a team did at one event
+type TeamEventHistory struct {
+	EventCode string
+	EventName string
+	Start     string
+	End       string
+	// nil if the team is registered but hasn't played yet
+	Stats  *ftcscout.EventTeamStats
+	Awards []TeamAward
+}
+
+func init() {
+	historyPaginator = pagination.New[TeamEventHistory]("team;history").
+		ItemsPerPage(4).
+		AddExtraKey("provider").
+		AddExtraKey("teamNumber").
+		AddExtraKey("season").
+		WithDataGetter(func(state pagination.PaginationState) ([]TeamEventHistory, error) {
+			return historyCache.GetOrFetch(fmt.Sprintf("%s %s %s", state.ExtraData["provider"], state.ExtraData["teamNumber"], state.ExtraData["season"]))
+		}).
+		OnCreate(generateHistoryEmbed).
+		OnUpdate(updateHistoryEmbed).
+		Register()
+}
+
+func teamHistory(channelID string, guildID string, teamNumber string, season string, session interactions.Session, i *discordgo.InteractionCreate) {
+	team, err := fetchTeamInfo(teamNumber)
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	dataProvider := guildconfig.Provider(guildID)
+	history, err := historyCache.GetOrFetch(fmt.Sprintf("%s %d %s", dataProvider.Name(), team.Number, season))
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Error: %v", err))
+		return
+	}
+	if len(history) == 0 {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Team %d didn't attend any events in the %s season.", team.Number, formatSeason(season)))
+		return
+	}
+
+	extraData := map[string]string{"provider": dataProvider.Name(), "teamNumber": fmt.Sprintf("%d", team.Number), "season": season}
+	err = historyPaginator.Setup(session, i, channelID, extraData, team.Name)
+	if err != nil {
+		logging.WithInteraction(commandLog, i).Error("Failed to set up history paginator", "team", teamNumber, "err", err)
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to setup history for Team %s: %v", teamNumber, err))
+	}
+}
+
+func getTeamHistory(key string) ([]TeamEventHistory, error) {
+	splitted := strings.Fields(key)
+	if len(splitted) != 3 {
+		return nil, fmt.Errorf("bad history key %q", key)
+	}
+	dataProvider, err := provider.Parse(splitted[0])
+	if err != nil {
+		return nil, err
+	}
+	return fetchTeamHistory(dataProvider, splitted[1], splitted[2])
+}
+
+func fetchTeamHistory(dataProvider provider.Provider, teamNumber string, season string) ([]TeamEventHistory, error) {
+	teamEvents, err := dataProvider.TeamEvents(context.Background(), teamNumber, season)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events for Team %s: %w", teamNumber, err)
+	}
+
+	// the events are still worth showing when the awards can't be fetched
+	awards, err := awardsCache.GetOrFetch(teamNumber)
+	if err != nil {
+		commandLog.Warn("Failed to fetch awards for team history", "team", teamNumber, "err", err)
+		awards = nil
+	}
+
+	history := make([]TeamEventHistory, len(teamEvents))
+	var wg sync.WaitGroup
+	for idx, teamEvent := range teamEvents {
+		history[idx] = TeamEventHistory{
+			EventCode: teamEvent.EventCode,
+			EventName: teamEvent.EventCode,
+			Stats:     teamEvent.Stats,
+		}
+		for _, award := range awards {
+			if strconv.Itoa(award.Season) == season && award.EventCode == teamEvent.EventCode {
+				history[idx].Awards = append(history[idx].Awards, award)
+			}
+		}
+
+		wg.Add(1)
+		go func(entry *TeamEventHistory) {
+			defer wg.Done()
+			// the name and dates are nice to have, the code is enough to go on without them
+			event, err := search.FetchEventDataFrom(dataProvider, season, entry.EventCode)
+			if err != nil {
+				commandLog.Warn("Failed to fetch event for team history", "year", season, "event", entry.EventCode, "err", err)
+				return
+			}
+			entry.EventName = event.Name
+			entry.Start = event.Start
+			entry.End = event.End
+		}(&history[idx])
+	}
+	wg.Wait()
+
+	// events without dates couldn't be looked up, they go at the end instead of the start
+	sort.SliceStable(history, func(a, b int) bool {
+		if history[a].Start == "" || history[b].Start == "" {
+			return history[b].Start == "" && history[a].Start != ""
+		}
+		return history[a].Start < history[b].Start
+	})
+	return history, nil
+}
+
+func formatSeason(season string) string {
+	for _, choice := range interactions.FtcYearChoices {
+		if choice.Value == season {
+			return choice.Name
+		}
+	}
+	return season
+}
+
+func formatEventDates(entry TeamEventHistory) string {
+	start, err := time.Parse("2006-01-02", entry.Start)
+	if err != nil {
+		return "Unknown dates"
+	}
+	end, err := time.Parse("2006-01-02", entry.End)
+	if err != nil || end.Equal(start) {
+		return start.Format("Jan 2, 2006")
+	}
+	return fmt.Sprintf("%s – %s", start.Format("Jan 2"), end.Format("Jan 2, 2006"))
+}
+
+func generateHistoryEmbed(state pagination.PaginationState, data []TeamEventHistory, params ...any) (*discordgo.MessageEmbed, error) {
+	teamNumber, err := strconv.Atoi(state.ExtraData["teamNumber"])
+	if err != nil {
+		return &discordgo.MessageEmbed{}, err
+	}
+
+	teamName := params[0].(string)
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s Season for Team %d (%s)", formatSeason(state.ExtraData["season"]), teamNumber, teamName),
+		Description: "Events attended this season:",
+		Color:       0x72cfdd,
+	}
+	return updateHistoryEmbed(state, data, embed)
+}
+
+// implements PageRenderer
+func updateHistoryEmbed(state pagination.PaginationState, data []TeamEventHistory, embed *discordgo.MessageEmbed) (*discordgo.MessageEmbed, error) {
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("Page %d of %d", state.CurrentPage+1, state.TotalPages),
+	}
+
+	embed.Fields = []*discordgo.MessageEmbedField{}
+	for _, entry := range data {
+		lines := []string{fmt.Sprintf("**Dates:** %s", formatEventDates(entry))}
+		if entry.Stats == nil {
+			lines = append(lines, "*No matches played yet*")
+		} else {
+			lines = append(lines,
+				fmt.Sprintf("**Record:** %d-%d-%d", entry.Stats.Wins, entry.Stats.Losses, entry.Stats.Ties),
+				fmt.Sprintf("**Rank:** %d", entry.Stats.Rank),
+			)
+			if entry.Stats.Opr != nil {
+				lines = append(lines, fmt.Sprintf("**OPR:** %.2f", entry.Stats.Opr.TotalPoints))
+			}
+		}
+
+		if len(entry.Awards) > 0 {
+			awards := []string{}
+			for _, award := range entry.Awards {
+				awards = append(awards, fmt.Sprintf("%s (#%d)", award.Type, award.Placement))
+			}
+			lines = append(lines, fmt.Sprintf("**Awards:** %s", strings.Join(awards, ", ")))
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s (%s)", entry.EventName, entry.EventCode),
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+	return embed, nil
+}
@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+)
+
+func TestFetchTeamHistory(t *testing.T) {
+	fakeEvent(t, "USHISTQ1", -10, -10, nil)
+	fakeEvent(t, "USHISTQ3", -30, -30, nil)
+	// USHISTQ2 isn't in fakeData so its lookup fails and it has no dates
+	fakeData.teamEvents["2025 16271"] = []ftcscout.EventTeam{
+		{EventCode: "USHISTQ1", TeamNumber: 16271, Stats: &ftcscout.EventTeamStats{Rank: 2}},
+		{EventCode: "USHISTQ2", TeamNumber: 16271},
+		{EventCode: "USHISTQ3", TeamNumber: 16271, Stats: &ftcscout.EventTeamStats{Rank: 5}},
+	}
+	fakeData.awardErrors["16271"] = errors.New("awards are down")
+	t.Cleanup(func() { delete(fakeData.awardErrors, "16271") })
+
+	history, err := fetchTeamHistory(fakeData, "16271", "2025")
+	if err != nil {
+		t.Fatalf("fetchTeamHistory: %v", err)
+	}
+
+	var codes []string
+	for _, entry := range history {
+		codes = append(codes, entry.EventCode)
+		if len(entry.Awards) != 0 {
+			t.Errorf("%s has awards %+v", entry.EventCode, entry.Awards)
+		}
+	}
+	if len(codes) != 3 || codes[0] != "USHISTQ3" || codes[1] != "USHISTQ1" || codes[2] != "USHISTQ2" {
+		t.Errorf("history is in the order %v, want oldest first and the undated event last", codes)
+	}
+	if history[0].EventName != "USHISTQ3 Qualifier" || history[2].EventName != "USHISTQ2" {
+		t.Errorf("names = %q, %q", history[0].EventName, history[2].EventName)
+	}
+}
@@ -0,0 +1,130 @@
+// Package httpretry is the GET loop shared by the FTCScout and FTC Events clients: a default timeout,
+// retries with exponential backoff on 5xx, 429 and network errors, Retry-After, and a hook for metrics.
+package httpretry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Retrier struct {
+	HTTPClient *http.Client
+
+	// applied to each request when the context passed in has no deadline of its own
+	Timeout time.Duration
+
+	// how many times to retry a request that failed with a 5xx, 429 or network error
+	MaxRetries int
+
+	// delay before the first retry, doubled after every attempt
+	Backoff time.Duration
+
+	// if set, called after every request attempt with the path, the status (0 when there was no response) and how long it took
+	Observe func(path string, status int, elapsed time.Duration)
+}
+
+// Request describes one GET, the clients fill in what's specific to their API
+type Request struct {
+	BaseURL string
+	Path    string
+
+	// if set, called on every attempt's request, e.g. to add auth
+	Prepare func(req *http.Request)
+
+	// builds the error returned for a non-200 status, so each client keeps its own error type
+	StatusError func(status int, body []byte) error
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// GetJSON fetches BaseURL+Path and decodes the JSON body into out, retrying with backoff when it makes sense
+func (r *Retrier) GetJSON(ctx context.Context, request Request, out any) error {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline && r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	path := request.Path
+	backoff := r.Backoff
+	var lastErr error
+	for attempt := 0; attempt <= r.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%v (last error: %v)", ctx.Err(), lastErr)
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		started := time.Now()
+		body, status, retryAfter, err := r.doOnce(ctx, request)
+		if r.Observe != nil {
+			r.Observe(path, status, time.Since(started))
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			lastErr = err
+			continue
+		}
+
+		if status != http.StatusOK {
+			lastErr = request.StatusError(status, body)
+			if !retryable(status) {
+				return lastErr
+			}
+			if retryAfter > backoff {
+				backoff = retryAfter
+			}
+			continue
+		}
+
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("failed to parse response from %s: %v", path, err)
+		}
+		return nil
+	}
+	return lastErr
+}
+
+func (r *Retrier) doOnce(ctx context.Context, request Request) (body []byte, status int, retryAfter time.Duration, err error) {
+	path := request.Path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, request.BaseURL+path, nil)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to build request for %s: %v", path, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if request.Prepare != nil {
+		request.Prepare(req)
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to fetch %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to read response from %s: %v", path, err)
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	return body, resp.StatusCode, retryAfter, nil
+}
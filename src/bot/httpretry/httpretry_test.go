@@ -0,0 +1,89 @@
+package httpretry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type statusErr int
+
+func (e statusErr) Error() string { return fmt.Sprintf("status %d", int(e)) }
+
+func newRequest(baseURL string) Request {
+	return Request{
+		BaseURL: baseURL,
+		Path:    "/things/1",
+		StatusError: func(status int, body []byte) error {
+			return statusErr(status)
+		},
+	}
+}
+
+func TestPrepareAndObserveEveryAttempt(t *testing.T) {
+	var calls atomic.Int32
+	var authed atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); ok {
+			authed.Add(1)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"name": "thing"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var statuses []int
+	retrier := Retrier{
+		MaxRetries: 3,
+		Backoff:    time.Millisecond,
+		Observe: func(path string, status int, elapsed time.Duration) {
+			if path != "/things/1" {
+				t.Errorf("observed path %q", path)
+			}
+			statuses = append(statuses, status)
+		},
+	}
+	request := newRequest(server.URL)
+	request.Prepare = func(req *http.Request) { req.SetBasicAuth("user", "token") }
+
+	var out struct{ Name string }
+	if err := retrier.GetJSON(context.Background(), request, &out); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if out.Name != "thing" {
+		t.Errorf("decoded %+v", out)
+	}
+	if authed.Load() != 3 {
+		t.Errorf("%d of 3 attempts had auth", authed.Load())
+	}
+	if fmt.Sprint(statuses) != "[502 502 200]" {
+		t.Errorf("observed %v, want [502 502 200]", statuses)
+	}
+}
+
+func TestStatusErrorIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	retrier := Retrier{MaxRetries: 3, Backoff: time.Millisecond}
+	err := retrier.GetJSON(context.Background(), newRequest(server.URL), &struct{}{})
+	var got statusErr
+	if !errors.As(err, &got) || got != http.StatusForbidden {
+		t.Fatalf("err = %v, want the 403 from StatusError", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d calls, want 1", calls.Load())
+	}
+}
@@ -0,0 +1,51 @@
+package interactions
+
+import (
+	"bytes"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Attachment is a file for EditOrSend, kept as bytes so it can be sent more than once
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+func (a Attachment) file() *discordgo.File {
+	return &discordgo.File{Name: a.Name, ContentType: a.ContentType, Reader: bytes.NewReader(a.Data)}
+}
+
+func attachmentFiles(attachments []Attachment) []*discordgo.File {
+	files := make([]*discordgo.File, 0, len(attachments))
+	for _, attachment := range attachments {
+		files = append(files, attachment.file())
+	}
+	return files
+}
+
+// EditOrSend edits the deferred response with the content, embeds and attachments, or posts them in channelID
+// when there's no interaction or the edit fails (e.g. the token expired while rendering). Every attempt gets new
+// readers, a failed edit has already read its files to the end.
+func EditOrSend(session Session, i *discordgo.InteractionCreate, channelID, content string, embeds []*discordgo.MessageEmbed, attachments ...Attachment) error {
+	if i != nil {
+		edit := &discordgo.WebhookEdit{Files: attachmentFiles(attachments)}
+		if content != "" {
+			edit.Content = &content
+		}
+		if embeds != nil {
+			edit.Embeds = &embeds
+		}
+		if _, err := session.InteractionResponseEdit(i.Interaction, edit); err == nil {
+			return nil
+		}
+	}
+
+	_, err := session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: content,
+		Embeds:  embeds,
+		Files:   attachmentFiles(attachments),
+	})
+	return err
+}
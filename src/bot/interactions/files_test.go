@@ -0,0 +1,76 @@
+package interactions_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+)
+
+var report = interactions.Attachment{Name: "report.csv", ContentType: "text/csv", Data: []byte("team,rank\n16271,1\n")}
+
+func interaction() *discordgo.InteractionCreate {
+	return sessiontest.NewInteraction("guild", "member", discordgo.ApplicationCommandInteractionData{Name: "export"})
+}
+
+func sentFile(t *testing.T, sent sessiontest.Sent) string {
+	t.Helper()
+	if len(sent.Files) != 1 {
+		t.Fatalf("sent %d files, want 1", len(sent.Files))
+	}
+	if sent.Files[0].Name != report.Name || sent.Files[0].ContentType != report.ContentType {
+		t.Errorf("file is %q (%s)", sent.Files[0].Name, sent.Files[0].ContentType)
+	}
+	data, _ := io.ReadAll(sent.Files[0].Reader)
+	return string(data)
+}
+
+func TestEditOrSendEditsResponse(t *testing.T) {
+	session := sessiontest.New()
+	embeds := []*discordgo.MessageEmbed{{Title: "Report"}}
+
+	if err := interactions.EditOrSend(session, interaction(), "channel", "here you go", embeds, report); err != nil {
+		t.Fatalf("EditOrSend: %v", err)
+	}
+
+	sent, _ := session.Last()
+	if sent.Kind != "edit" || sent.Content != "here you go" || len(sent.Embeds) != 1 {
+		t.Fatalf("sent %+v, want the response edited", sent)
+	}
+	if got := sentFile(t, sent); got != string(report.Data) {
+		t.Errorf("file = %q", got)
+	}
+}
+
+func TestEditOrSendFallsBackToChannel(t *testing.T) {
+	session := sessiontest.New()
+	session.Errors["InteractionResponseEdit"] = errors.New("unknown interaction")
+
+	if err := interactions.EditOrSend(session, interaction(), "channel", "", nil, report); err != nil {
+		t.Fatalf("EditOrSend: %v", err)
+	}
+
+	sent, _ := session.Last()
+	if sent.Kind != "channel" || sent.ChannelID != "channel" {
+		t.Fatalf("sent %+v, want it posted in the channel", sent)
+	}
+	// the failed edit read the first copy, the fallback needs the whole file again
+	if got := sentFile(t, sent); got != string(report.Data) {
+		t.Errorf("fallback file = %q, want %q", got, report.Data)
+	}
+}
+
+func TestEditOrSendWithoutInteraction(t *testing.T) {
+	session := sessiontest.New()
+	session.Errors["ChannelMessageSend"] = errors.New("missing access")
+
+	if err := interactions.EditOrSend(session, nil, "channel", "text command", nil, report); err == nil {
+		t.Error("the send failed but EditOrSend didn't say so")
+	}
+	if len(session.Sent()) != 0 {
+		t.Errorf("sent %+v", session.Sent())
+	}
+}
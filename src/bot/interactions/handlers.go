@@ -1,14 +1,10 @@
 package interactions
 
 import (
-	"fmt"
-
 	"github.com/bwmarrin/discordgo"
-	"github.com/shuban-789/bjorn/src/bot/util"
+	"github.com/shuban-789/bjorn/src/bot/logging"
 )
 
-var CmdPrefix = ">>"
-
 // all the registered slash commands (populated by each command file's init())
 var Commands []*discordgo.ApplicationCommand
 
@@ -23,13 +19,13 @@ var FtcYearChoices []*discordgo.ApplicationCommandOptionChoice = []*discordgo.Ap
 }
 
 // commandHandlers maps top-level command names to interaction handlers.
-type CommandHandler func(*discordgo.Session, *discordgo.InteractionCreate)
+type CommandHandler func(Session, *discordgo.InteractionCreate)
 
 var CommandHandlers map[string]CommandHandler
 
 // map of top level cmds for custom autocomplete handlers so you can write more custom code if needed
 // basically this is just the old system but I didn't want to delete it just in case
-var AutocompleteHandlers map[string]func(*discordgo.Session, *discordgo.InteractionCreate)
+var AutocompleteHandlers map[string]func(Session, *discordgo.InteractionCreate)
 
 // func that returns a list of autocomplete choices given the current options
 // opts is the current options filled out (like the region in the match track command is used to get the list of events)
@@ -39,13 +35,19 @@ type AutocompleteProvider func(opts map[string]string, query string) []*discordg
 // maps str of "command/subcommand/option" or "command/option" to the right function
 var AutocompleteProviders map[string]AutocompleteProvider
 
+// same as AutocompleteProvider but also gets the guild the command is being typed in (empty in DMs),
+// for choices that depend on the server's settings like its home region
+type GuildAutocompleteProvider func(guildID string, opts map[string]string, query string) []*discordgo.ApplicationCommandOptionChoice
+
+var GuildAutocompleteProviders map[string]GuildAutocompleteProvider
+
 // maps custom ID of component to handler func
-type ComponentHandler func(s *discordgo.Session, i *discordgo.InteractionCreate, data []string)
+type ComponentHandler func(s Session, i *discordgo.InteractionCreate, data []string)
 
 var ComponentHandlers map[string]ComponentHandler
 
 // maps custom ID of modal to handler func
-type ModalHandler func(s *discordgo.Session, i *discordgo.InteractionCreate, id_data []string, modal_data discordgo.ModalSubmitInteractionData)
+type ModalHandler func(s Session, i *discordgo.InteractionCreate, id_data []string, modal_data discordgo.ModalSubmitInteractionData)
 
 var ModalHandlers map[string]ModalHandler
 
@@ -59,13 +61,20 @@ func RegisterCommand(cmd *discordgo.ApplicationCommand, handler CommandHandler)
 
 // this is if you want to register a custom autocomplete handler for a command name, but it's better to use registerautocomplete now
 // I just kept this just in case
-func RegisterAutocompleteHandlerCustom(cmdName string, handler func(*discordgo.Session, *discordgo.InteractionCreate)) {
+func RegisterAutocompleteHandlerCustom(cmdName string, handler func(Session, *discordgo.InteractionCreate)) {
 	if AutocompleteHandlers == nil {
-		AutocompleteHandlers = make(map[string]func(*discordgo.Session, *discordgo.InteractionCreate))
+		AutocompleteHandlers = make(map[string]func(Session, *discordgo.InteractionCreate))
 	}
 	AutocompleteHandlers[cmdName] = handler
 }
 
+func RegisterGuildAutocomplete(path string, provider GuildAutocompleteProvider) {
+	if GuildAutocompleteProviders == nil {
+		GuildAutocompleteProviders = make(map[string]GuildAutocompleteProvider)
+	}
+	GuildAutocompleteProviders[path] = provider
+}
+
 // simpler autocomplete mapping system for a specific command/subcommand/option path.
 // path format: "command/subcommand/option" or "command/option" (for commands without subcommands)
 // provider func returns list of choices
@@ -76,7 +85,7 @@ func RegisterAutocomplete(path string, provider AutocompleteProvider) {
 	AutocompleteProviders[path] = provider
 }
 
-func HandleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func HandleAutocomplete(s Session, i *discordgo.InteractionCreate) {
 	data := i.ApplicationCommandData()
 	cmdName := data.Name
 
@@ -124,11 +133,6 @@ func HandleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		path = cmdName + "/" + focusedOpt.Name
 	}
 
-	provider, ok := AutocompleteProviders[path]
-	if !ok {
-		return
-	}
-
 	query := ""
 	if focusedOpt.Value != nil {
 		if v, ok := focusedOpt.Value.(string); ok {
@@ -136,7 +140,14 @@ func HandleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		}
 	}
 
-	choices := provider(opts, query)
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	if guildProvider, ok := GuildAutocompleteProviders[path]; ok {
+		choices = guildProvider(i.GuildID, opts, query)
+	} else if provider, ok := AutocompleteProviders[path]; ok {
+		choices = provider(opts, query)
+	} else {
+		return
+	}
 
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
@@ -145,7 +156,7 @@ func HandleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		},
 	})
 	if err != nil {
-		fmt.Println(util.Fail("Error responding to autocomplete interaction: %v", err))
+		logging.WithInteraction(logging.For("interactions"), i).Error("Error responding to autocomplete interaction", "err", err)
 	}
 }
 
@@ -214,3 +225,38 @@ func getBoolOptionHelper(opts []*discordgo.ApplicationCommandInteractionDataOpti
 	}
 	return false, false
 }
+
+// utility to get an integer option from interaction data options, ok is false if it wasn't given
+func GetIntOption(opts []*discordgo.ApplicationCommandInteractionDataOption, name string) (value int64, ok bool) {
+	for _, o := range opts {
+		if o.Name == name && o.Value != nil {
+			// discord sends numbers as json, so they come out as float64
+			if v, ok := o.Value.(float64); ok {
+				return int64(v), true
+			}
+		}
+		if len(o.Options) > 0 {
+			if v, ok := GetIntOption(o.Options, name); ok {
+				return v, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// utility to get a number option from interaction data options, ok is false if it wasn't given
+func GetFloatOption(opts []*discordgo.ApplicationCommandInteractionDataOption, name string) (value float64, ok bool) {
+	for _, o := range opts {
+		if o.Name == name && o.Value != nil {
+			if v, ok := o.Value.(float64); ok {
+				return v, true
+			}
+		}
+		if len(o.Options) > 0 {
+			if v, ok := GetFloatOption(o.Options, name); ok {
+				return v, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -64,7 +64,7 @@ func GetChannelId(message *discordgo.MessageCreate, i *discordgo.InteractionCrea
 	panic("Both message and interaction are nil in getChannelId")
 }
 
-func SendEmbed(session *discordgo.Session, i *discordgo.InteractionCreate, channelID string, embed *discordgo.MessageEmbed) {
+func SendEmbed(session Session, i *discordgo.InteractionCreate, channelID string, embed *discordgo.MessageEmbed) {
 	applyEvilToEmbed(embed)
 
 	if i != nil {
@@ -81,7 +81,7 @@ func SendEmbed(session *discordgo.Session, i *discordgo.InteractionCreate, chann
 	}
 }
 
-func SendMessage(session *discordgo.Session, i *discordgo.InteractionCreate, channelID string, message string) *discordgo.Message {
+func SendMessage(session Session, i *discordgo.InteractionCreate, channelID string, message string) *discordgo.Message {
 	message = applyEvilToMessage(message)
 
 	if i != nil {
@@ -109,7 +109,7 @@ func SendMessage(session *discordgo.Session, i *discordgo.InteractionCreate, cha
 
 // Returns whether or not the message was sent successfully
 // note that if sendNewMessage is true, messageObj will be nil bc idk how to get it
-func SendMessageComplex(session *discordgo.Session, i *discordgo.InteractionCreate, channelID string, message string, components *[]discordgo.MessageComponent, embeds *[]*discordgo.MessageEmbed, sendNewMessage bool) (messageObj *discordgo.Message, ok bool) {
+func SendMessageComplex(session Session, i *discordgo.InteractionCreate, channelID string, message string, components *[]discordgo.MessageComponent, embeds *[]*discordgo.MessageEmbed, sendNewMessage bool) (messageObj *discordgo.Message, ok bool) {
 	message = applyEvilToMessage(message)
 	applyEvilToEmbeds(embeds)
 
@@ -149,7 +149,7 @@ func SendMessageComplex(session *discordgo.Session, i *discordgo.InteractionCrea
 	return messageObj, true
 }
 
-func SendEphemeralMessage(session *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+func SendEphemeralMessage(session Session, i *discordgo.InteractionCreate, message string) error {
 	message = applyEvilToMessage(message)
 
 	return session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
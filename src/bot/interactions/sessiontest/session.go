@@ -0,0 +1,464 @@
+// Package sessiontest has a fake Discord session for running command handlers without a bot.
+// It records every message, embed and file a handler sends, and serves guilds, members and roles
+// from fixtures set on the struct, kind of like net/http/httptest but for interactions.Session.
+package sessiontest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+)
+
+// Sent is one message that a handler sent, edited into an interaction response, or responded with
+type Sent struct {
+	// "respond", "edit" or "channel"
+	Kind       string
+	ChannelID  string
+	Content    string
+	Embeds     []*discordgo.MessageEmbed
+	Files      []*discordgo.File
+	Components []discordgo.MessageComponent
+	Ephemeral  bool
+}
+
+// Session implements interactions.Session. Fixtures can be set directly before running a handler,
+// and Errors can make a method fail, e.g. Errors["GuildRoles"] = errors.New("no perms")
+type Session struct {
+	Guilds  map[string]*discordgo.Guild
+	Members map[string][]*discordgo.Member
+	Roles   map[string][]*discordgo.Role
+	Errors  map[string]error
+
+	mu              sync.Mutex
+	sent            []Sent
+	deferred        int
+	roleAdds        map[string][]string
+	threads         []*discordgo.ThreadStart
+	scheduledEvents []*discordgo.GuildScheduledEventParams
+	handlers        []interface{}
+	nextID          int
+}
+
+var _ interactions.Session = (*Session)(nil)
+
+func New() *Session {
+	return &Session{
+		Guilds:   make(map[string]*discordgo.Guild),
+		Members:  make(map[string][]*discordgo.Member),
+		Roles:    make(map[string][]*discordgo.Role),
+		Errors:   make(map[string]error),
+		roleAdds: make(map[string][]string),
+	}
+}
+
+// NewInteraction builds a slash command interaction from guildID by userID, for passing into handlers.
+// Use an empty guildID for a DM.
+func NewInteraction(guildID, userID string, data discordgo.ApplicationCommandInteractionData) *discordgo.InteractionCreate {
+	interaction := &discordgo.Interaction{
+		ID:        "interaction-" + userID,
+		Type:      discordgo.InteractionApplicationCommand,
+		GuildID:   guildID,
+		ChannelID: "channel",
+		Data:      data,
+	}
+	user := &discordgo.User{ID: userID, Username: "user" + userID}
+	if guildID != "" {
+		interaction.Member = &discordgo.Member{GuildID: guildID, User: user}
+	} else {
+		interaction.User = user
+	}
+	return &discordgo.InteractionCreate{Interaction: interaction}
+}
+
+// NewMessage builds a text command message, for passing into handlers that also take messages
+func NewMessage(guildID, channelID, userID, content string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID:        "message-" + userID,
+		GuildID:   guildID,
+		ChannelID: channelID,
+		Content:   content,
+		Author:    &discordgo.User{ID: userID, Username: "user" + userID},
+	}}
+}
+
+// Sent returns everything sent so far, in order
+func (s *Session) Sent() []Sent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Sent(nil), s.sent...)
+}
+
+// Last returns the last thing sent, and false if nothing was
+func (s *Session) Last() (Sent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sent) == 0 {
+		return Sent{}, false
+	}
+	return s.sent[len(s.sent)-1], true
+}
+
+// Embeds returns every embed sent so far, in order
+func (s *Session) Embeds() []*discordgo.MessageEmbed {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	embeds := []*discordgo.MessageEmbed{}
+	for _, sent := range s.sent {
+		embeds = append(embeds, sent.Embeds...)
+	}
+	return embeds
+}
+
+// Files returns every attachment sent so far, in order
+func (s *Session) Files() []*discordgo.File {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := []*discordgo.File{}
+	for _, sent := range s.sent {
+		files = append(files, sent.Files...)
+	}
+	return files
+}
+
+// Deferred is how many times a handler deferred its response
+func (s *Session) Deferred() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deferred
+}
+
+// RoleAdds returns the IDs of the roles given to userID in guildID
+func (s *Session) RoleAdds(guildID, userID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.roleAdds[guildID+"/"+userID]...)
+}
+
+func (s *Session) Threads() []*discordgo.ThreadStart {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.ThreadStart(nil), s.threads...)
+}
+
+func (s *Session) ScheduledEvents() []*discordgo.GuildScheduledEventParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.GuildScheduledEventParams(nil), s.scheduledEvents...)
+}
+
+// Dispatch runs the handlers registered with AddHandlerOnce that take this event's type,
+// e.g. a *discordgo.MessageCreate for roleme's role color question. They get a nil *discordgo.Session.
+func (s *Session) Dispatch(event interface{}) {
+	s.mu.Lock()
+	handlers := s.handlers
+	remaining := []interface{}{}
+	called := []func(){}
+	for _, handler := range handlers {
+		switch h := handler.(type) {
+		case func(*discordgo.Session, *discordgo.MessageCreate):
+			if m, ok := event.(*discordgo.MessageCreate); ok {
+				called = append(called, func() { h(nil, m) })
+				continue
+			}
+		case func(*discordgo.Session, *discordgo.InteractionCreate):
+			if i, ok := event.(*discordgo.InteractionCreate); ok {
+				called = append(called, func() { h(nil, i) })
+				continue
+			}
+		}
+		remaining = append(remaining, handler)
+	}
+	s.handlers = remaining
+	s.mu.Unlock()
+
+	for _, call := range called {
+		call()
+	}
+}
+
+// readFiles reads every attachment the way the real session does when it builds the request,
+// which happens before the request can fail. The recorded copies can be read again.
+func readFiles(files []*discordgo.File) []*discordgo.File {
+	read := make([]*discordgo.File, 0, len(files))
+	for _, file := range files {
+		data, _ := io.ReadAll(file.Reader)
+		copied := *file
+		copied.Reader = bytes.NewReader(data)
+		read = append(read, &copied)
+	}
+	return read
+}
+
+// call-site helpers, these expect s.mu to be held
+
+func (s *Session) failure(method string) error {
+	return s.Errors[method]
+}
+
+func (s *Session) record(sent Sent) *discordgo.Message {
+	s.sent = append(s.sent, sent)
+	s.nextID++
+	return &discordgo.Message{
+		ID:        strconv.Itoa(s.nextID),
+		ChannelID: sent.ChannelID,
+		Content:   sent.Content,
+		Embeds:    sent.Embeds,
+	}
+}
+
+func (s *Session) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var files []*discordgo.File
+	if resp.Data != nil {
+		files = readFiles(resp.Data.Files)
+	}
+	if err := s.failure("InteractionRespond"); err != nil {
+		return err
+	}
+
+	if resp.Data == nil {
+		s.deferred++
+		return nil
+	}
+
+	s.record(Sent{
+		Kind:       "respond",
+		ChannelID:  interaction.ChannelID,
+		Content:    resp.Data.Content,
+		Embeds:     resp.Data.Embeds,
+		Files:      files,
+		Components: resp.Data.Components,
+		Ephemeral:  resp.Data.Flags&discordgo.MessageFlagsEphemeral != 0,
+	})
+	return nil
+}
+
+func (s *Session) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := readFiles(newresp.Files)
+	if err := s.failure("InteractionResponseEdit"); err != nil {
+		return nil, err
+	}
+
+	sent := Sent{Kind: "edit", ChannelID: interaction.ChannelID, Files: files}
+	if newresp.Content != nil {
+		sent.Content = *newresp.Content
+	}
+	if newresp.Embeds != nil {
+		sent.Embeds = *newresp.Embeds
+	}
+	if newresp.Components != nil {
+		sent.Components = *newresp.Components
+	}
+	return s.record(sent), nil
+}
+
+func (s *Session) ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Content: content})
+}
+
+func (s *Session) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}})
+}
+
+func (s *Session) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := readFiles(data.Files)
+	if err := s.failure("ChannelMessageSend"); err != nil {
+		return nil, err
+	}
+
+	embeds := data.Embeds
+	if data.Embed != nil {
+		embeds = append([]*discordgo.MessageEmbed{data.Embed}, embeds...)
+	}
+	return s.record(Sent{
+		Kind:       "channel",
+		ChannelID:  channelID,
+		Content:    data.Content,
+		Embeds:     embeds,
+		Files:      files,
+		Components: data.Components,
+	}), nil
+}
+
+func (s *Session) MessageThreadStartComplex(channelID, messageID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("MessageThreadStartComplex"); err != nil {
+		return nil, err
+	}
+
+	s.threads = append(s.threads, data)
+	s.nextID++
+	return &discordgo.Channel{ID: strconv.Itoa(s.nextID), Name: data.Name, ParentID: channelID}, nil
+}
+
+// UserChannelCreate returns "dm-<user ID>" as the DM channel, so DMs show up in Sent with that channel ID
+func (s *Session) UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("UserChannelCreate"); err != nil {
+		return nil, err
+	}
+
+	return &discordgo.Channel{
+		ID:         "dm-" + recipientID,
+		Type:       discordgo.ChannelTypeDM,
+		Recipients: []*discordgo.User{{ID: recipientID}},
+	}, nil
+}
+
+func (s *Session) Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("Guild"); err != nil {
+		return nil, err
+	}
+
+	guild, ok := s.Guilds[guildID]
+	if !ok {
+		return &discordgo.Guild{ID: guildID, Roles: s.Roles[guildID]}, nil
+	}
+	if guild.Roles == nil {
+		guild.Roles = s.Roles[guildID]
+	}
+	return guild, nil
+}
+
+func (s *Session) GuildMember(guildID, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("GuildMember"); err != nil {
+		return nil, err
+	}
+
+	for _, member := range s.Members[guildID] {
+		if member.User != nil && member.User.ID == userID {
+			return member, nil
+		}
+	}
+	return nil, fmt.Errorf("member %s not found in guild %s", userID, guildID)
+}
+
+func (s *Session) GuildMembers(guildID string, after string, limit int, options ...discordgo.RequestOption) ([]*discordgo.Member, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("GuildMembers"); err != nil {
+		return nil, err
+	}
+
+	members := s.Members[guildID]
+	if limit > 0 && len(members) > limit {
+		members = members[:limit]
+	}
+	return members, nil
+}
+
+func (s *Session) GuildMemberRoleAdd(guildID, userID, roleID string, options ...discordgo.RequestOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("GuildMemberRoleAdd"); err != nil {
+		return err
+	}
+
+	key := guildID + "/" + userID
+	s.roleAdds[key] = append(s.roleAdds[key], roleID)
+	for _, member := range s.Members[guildID] {
+		if member.User != nil && member.User.ID == userID {
+			member.Roles = append(member.Roles, roleID)
+		}
+	}
+	return nil
+}
+
+func (s *Session) GuildRoles(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Role, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("GuildRoles"); err != nil {
+		return nil, err
+	}
+	return s.Roles[guildID], nil
+}
+
+func (s *Session) GuildRoleCreate(guildID string, data *discordgo.RoleParams, options ...discordgo.RequestOption) (*discordgo.Role, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("GuildRoleCreate"); err != nil {
+		return nil, err
+	}
+
+	s.nextID++
+	role := &discordgo.Role{ID: strconv.Itoa(s.nextID), Name: data.Name}
+	applyRoleParams(role, data)
+	s.Roles[guildID] = append(s.Roles[guildID], role)
+	return role, nil
+}
+
+func (s *Session) GuildRoleEdit(guildID, roleID string, data *discordgo.RoleParams, options ...discordgo.RequestOption) (*discordgo.Role, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("GuildRoleEdit"); err != nil {
+		return nil, err
+	}
+
+	for _, role := range s.Roles[guildID] {
+		if role.ID == roleID {
+			if data.Name != "" {
+				role.Name = data.Name
+			}
+			applyRoleParams(role, data)
+			return role, nil
+		}
+	}
+	return nil, fmt.Errorf("role %s not found in guild %s", roleID, guildID)
+}
+
+func applyRoleParams(role *discordgo.Role, data *discordgo.RoleParams) {
+	if data.Color != nil {
+		role.Color = *data.Color
+	}
+	if data.Hoist != nil {
+		role.Hoist = *data.Hoist
+	}
+	if data.Mentionable != nil {
+		role.Mentionable = *data.Mentionable
+	}
+	if data.Permissions != nil {
+		role.Permissions = *data.Permissions
+	}
+}
+
+func (s *Session) GuildScheduledEventCreate(guildID string, event *discordgo.GuildScheduledEventParams, options ...discordgo.RequestOption) (*discordgo.GuildScheduledEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.failure("GuildScheduledEventCreate"); err != nil {
+		return nil, err
+	}
+
+	s.scheduledEvents = append(s.scheduledEvents, event)
+	s.nextID++
+	return &discordgo.GuildScheduledEvent{ID: strconv.Itoa(s.nextID), GuildID: guildID, Name: event.Name}, nil
+}
+
+func (s *Session) AddHandlerOnce(handler interface{}) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+	index := len(s.handlers) - 1
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if index < len(s.handlers) {
+			s.handlers[index] = nil
+		}
+	}
+}
@@ -1,24 +1,26 @@
 package bot
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
 	"github.com/shuban-789/bjorn/src/bot/pagination"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/search"
 	"github.com/shuban-789/bjorn/src/bot/util"
 )
 
 var (
 	leadPaginator *pagination.Paginator[TeamRank]
 	
+	// keyed by "provider year eventCode"
 	leadCache = util.NewCache(
 		100,
 		time.Hour*5,
@@ -32,13 +34,6 @@ func init() {
 			Name:        "lead",
 			Description: "Display the leaderboard for a certain event.",
 			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "year",
-					Description: "Year of the event (e.g., 2025).",
-					Required:    true,
-					Choices: interactions.FtcYearChoices,
-				},
 				// {
 				// 	Type:        discordgo.ApplicationCommandOptionString,
 				// 	Name:        "region",
@@ -53,30 +48,54 @@ func init() {
 					Required:    true,
 					// Autocomplete: true,
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "year",
+					Description: "Year of the event (defaults to this server's season).",
+					Required:    false,
+					Choices:     interactions.FtcYearChoices,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "sort",
+					Description: "What to sort the leaderboard by (defaults to rank).",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Rank", Value: "rank"},
+						{Name: "OPR", Value: "opr"},
+						{Name: "Ranking Points", Value: "rp"},
+					},
+				},
 			},
 		},
-		func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
 			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
 			data := i.ApplicationCommandData()
-			year := interactions.GetStringOption(data.Options, "year")
+			year := guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(data.Options, "year"))
 			event := interactions.GetStringOption(data.Options, "event")
 			if year == "" || event == "" {
-				interactions.SendMessage(s, i, "", "Usage: /lead <year> <region> <event>")
+				interactions.SendMessage(s, i, "", "Usage: /lead <event> [year]")
 				return
 			}
-			leadcmd(s, nil, i, []string{year, event})
+			leadcmd(s, nil, i, []string{year, event, interactions.GetStringOption(data.Options, "sort")})
 		},
 	)
 
 	leadPaginator = pagination.New[TeamRank]("lead").
 					ItemsPerPage(10).
+					AddExtraKey("provider").
 					AddExtraKey("year").
 					AddExtraKey("eventCode").
+					AddExtraKey("sort").
 					OnUpdate(updateLeaderboard).
 					WithDataGetter(func(state pagination.PaginationState) ([]TeamRank, error) {
 						year := state.ExtraData["year"]
 						eventCode := state.ExtraData["eventCode"]
-						return leadCache.GetOrFetch(fmt.Sprintf("%s %s", year, eventCode))
+						ranks, err := leadCache.GetOrFetch(fmt.Sprintf("%s %s %s", state.ExtraData["provider"], year, eventCode))
+						if err != nil {
+							return nil, err
+						}
+						return sortLeaderboard(ranks, state.ExtraData["sort"]), nil
 					}).
 					Register();
 	
@@ -85,8 +104,24 @@ func init() {
 }
 
 type TeamRank struct {
-	Rank       int `json:"rank"`
-	TeamNumber int `json:"teamNumber"`
+	Rank          int     `json:"rank"`
+	TeamNumber    int     `json:"teamNumber"`
+	TeamName      string  `json:"teamName"`
+	RP            float64 `json:"rp"`
+	TBP           float64 `json:"tbp"`
+	Wins          int     `json:"wins"`
+	Losses        int     `json:"losses"`
+	Ties          int     `json:"ties"`
+	MatchesPlayed int     `json:"matchesPlayed"`
+	// nil when the backend doesn't compute OPR
+	OPR *float64 `json:"opr"`
+}
+
+// the sort options for the leaderboard, rank is the default
+var leaderboardSorts = map[string]string{
+	"rank": "Rank",
+	"opr":  "OPR",
+	"rp":   "Ranking Points",
 }
 
 type TeamRankSlice []TeamRank
@@ -103,16 +138,28 @@ func (s TeamRankSlice) Less(i, j int) bool {
 	return s[i].Rank < s[j].Rank
 }
 
-func leadcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
+func leadcmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
 	channelId := interactions.GetChannelId(message, i)
+	guildId, _ := interactions.GetGuildId(message, i)
 	if len(args) < 2 {
-		interactions.SendMessage(session, i, channelId, "Usage: >>lead <year> <eventCode>")
+		interactions.SendMessage(session, i, channelId, fmt.Sprintf("Usage: `%slead <year> <eventCode> [rank|opr|rp]`", guildconfig.Get(guildId).CommandPrefix))
+		return
+	}
+
+	sortBy := "rank"
+	if len(args) > 2 && args[2] != "" {
+		sortBy = strings.ToLower(args[2])
+	}
+	if _, ok := leaderboardSorts[sortBy]; !ok {
+		interactions.SendMessage(session, i, channelId, "Unknown sort, use 'rank', 'opr' or 'rp'.")
 		return
 	}
 
 	err := leadPaginator.Setup(session, i, channelId, map[string]string{
+		"provider":  guildconfig.Provider(guildId).Name(),
 		"year":      args[0],
 		"eventCode": args[1],
+		"sort":      sortBy,
 	})
 	if err != nil {
 		interactions.SendMessage(session, i, channelId, fmt.Sprintf("Error sending leaderboard: %v", err))
@@ -121,66 +168,77 @@ func leadcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *di
 
 func getLeaderboardInfo(key string) ([]TeamRank, error) {
 	splitted := strings.Fields(key)
-	return fetchLeaderboard(splitted[0], splitted[1])
-}
-
-func fetchLeaderboard(year string, eventCode string) ([]TeamRank, error) {
-	url := fmt.Sprintf("https://api.ftcscout.org/rest/v1/events/%s/%s/teams", year, eventCode)
-
-	resp, err := http.Get(url)
+	dataProvider, err := provider.Parse(splitted[0])
 	if err != nil {
-		return nil, errors.New(util.Fail("failed to fetch leaderboard: %v", err))
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New(util.Fail("API returned status code: %d", resp.StatusCode))
+		return nil, err
 	}
+	return fetchLeaderboard(dataProvider, splitted[1], splitted[2])
+}
 
-	body, err := io.ReadAll(resp.Body)
+func fetchLeaderboard(dataProvider provider.Provider, year string, eventCode string) ([]TeamRank, error) {
+	eventTeams, err := dataProvider.EventRankings(context.Background(), year, eventCode)
 	if err != nil {
-		return nil, errors.New(util.Fail("failed to read response: %v", err))
-	}
-
-	var leaderboard []map[string]interface{}
-	if err := json.Unmarshal(body, &leaderboard); err != nil {
-		return nil, errors.New(util.Fail("failed to parse JSON response: %v, body: %s", err, string(body)))
+		return nil, fmt.Errorf("failed to fetch leaderboard: %w", err)
 	}
 
 	var ranks []TeamRank
-	for _, team := range leaderboard {
-		teamNumber, ok := team["teamNumber"].(float64)
-		if !ok {
+	for _, team := range eventTeams {
+		// teams that haven't played yet have no stats
+		if team.Stats == nil {
 			continue
 		}
 
-		stats, ok := team["stats"].(map[string]interface{})
-		if !ok {
-			continue
+		rank := TeamRank{
+			Rank:          team.Stats.Rank,
+			TeamNumber:    team.TeamNumber,
+			TeamName:      team.TeamName,
+			RP:            team.Stats.RP,
+			TBP:           team.Stats.TBP,
+			Wins:          team.Stats.Wins,
+			Losses:        team.Stats.Losses,
+			Ties:          team.Stats.Ties,
+			MatchesPlayed: team.Stats.MatchesPlayed,
 		}
-
-		rank, ok := stats["rank"].(float64)
-		if !ok {
-			continue
+		if team.Stats.Opr != nil {
+			opr := team.Stats.Opr.TotalPoints
+			rank.OPR = &opr
 		}
-
-		ranks = append(ranks, TeamRank{
-			Rank:       int(rank),
-			TeamNumber: int(teamNumber),
-		})
+		if rank.TeamName == "" {
+			rank.TeamName, _ = search.GetTeamNameFromAnyRegion(strconv.Itoa(team.TeamNumber))
+		}
+		ranks = append(ranks, rank)
 	}
 
 	sort.Sort(TeamRankSlice(ranks))
 	return ranks, nil
 }
 
+// sortLeaderboard returns a copy of the rank ordered leaderboard sorted by sortBy, ties keep rank order
+func sortLeaderboard(ranks []TeamRank, sortBy string) []TeamRank {
+	sorted := append([]TeamRank(nil), ranks...)
+	switch sortBy {
+	case "opr":
+		sort.SliceStable(sorted, func(a, b int) bool {
+			if sorted[a].OPR == nil || sorted[b].OPR == nil {
+				return sorted[b].OPR == nil && sorted[a].OPR != nil
+			}
+			return *sorted[a].OPR > *sorted[b].OPR
+		})
+	case "rp":
+		sort.SliceStable(sorted, func(a, b int) bool {
+			return sorted[a].RP > sorted[b].RP
+		})
+	}
+	return sorted
+}
+
 func updateLeaderboard(state pagination.PaginationState, data []TeamRank, previousEmbed *discordgo.MessageEmbed) (*discordgo.MessageEmbed, error) {
 	year := state.ExtraData["year"]
 	eventCode := state.ExtraData["eventCode"]
-	return createLeaderboardEmbed(year, eventCode, data, state.CurrentPage+1, state.TotalPages), nil
+	return createLeaderboardEmbed(year, eventCode, state.ExtraData["sort"], data, state.CurrentPage+1, state.TotalPages), nil
 }
 
-func createLeaderboardEmbed(year string, eventCode string, teams []TeamRank, part int, totalParts int) *discordgo.MessageEmbed {
+func createLeaderboardEmbed(year string, eventCode string, sortBy string, teams []TeamRank, part int, totalParts int) *discordgo.MessageEmbed {
 	title := fmt.Sprintf("%s %s Leaderboard", year, eventCode)
 	if totalParts > 1 {
 		title = fmt.Sprintf("%s (Part %d/%d)", title, part, totalParts)
@@ -191,11 +249,25 @@ func createLeaderboardEmbed(year string, eventCode string, teams []TeamRank, par
 		Color:  0x72cfdd,
 		Fields: []*discordgo.MessageEmbedField{},
 	}
+	if name, ok := leaderboardSorts[sortBy]; ok && sortBy != "rank" {
+		embed.Description = fmt.Sprintf("Sorted by %s", name)
+	}
 
 	for _, team := range teams {
+		name := fmt.Sprintf("#%d · %d", team.Rank, team.TeamNumber)
+		if team.TeamName != "" {
+			name += " " + team.TeamName
+		}
+
+		opr := "—"
+		if team.OPR != nil {
+			opr = fmt.Sprintf("%.1f", *team.OPR)
+		}
+
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name:   fmt.Sprintf("Rank %d", team.Rank),
-			Value:  fmt.Sprintf("Team Number: %d", team.TeamNumber),
+			Name: name,
+			Value: fmt.Sprintf("RP %.2f · TBP %.1f · %d-%d-%d in %d · OPR %s",
+				team.RP, team.TBP, team.Wins, team.Losses, team.Ties, team.MatchesPlayed, opr),
 			Inline: false,
 		})
 	}
@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+)
+
+// fakeRankings is a leaderboard of count teams, ranked in team number order with RP going up
+// so sorting by RP flips it
+func fakeRankings(count int) []ftcscout.EventTeam {
+	rankings := make([]ftcscout.EventTeam, 0, count)
+	for rank := 1; rank <= count; rank++ {
+		rankings = append(rankings, ftcscout.EventTeam{
+			TeamNumber: 1000 + rank,
+			TeamName:   fmt.Sprintf("Team %d", rank),
+			Stats: &ftcscout.EventTeamStats{
+				Rank:          rank,
+				RP:            float64(rank),
+				Wins:          count - rank,
+				MatchesPlayed: count,
+			},
+		})
+	}
+	return rankings
+}
+
+func leadInteraction(options ...string) *discordgo.InteractionCreate {
+	return sessiontest.NewInteraction("lead-guild", "member", discordgo.ApplicationCommandInteractionData{
+		Name:    "lead",
+		Options: stringOptions(options...),
+	})
+}
+
+func TestLeadCommand(t *testing.T) {
+	fakeData.rankings["2025 USLEADQ1"] = fakeRankings(12)
+	session := sessiontest.New()
+
+	interactions.CommandHandlers["lead"](session, leadInteraction("event", "USLEADQ1", "year", "2025"))
+
+	embeds := session.Embeds()
+	if len(embeds) != 1 {
+		t.Fatalf("sent %d embeds, want 1", len(embeds))
+	}
+	embed := embeds[0]
+	if embed.Title != "2025 USLEADQ1 Leaderboard (Part 1/2)" {
+		t.Errorf("title = %q", embed.Title)
+	}
+	if len(embed.Fields) != 10 || embed.Fields[0].Name != "#1 · 1001 Team 1" {
+		t.Errorf("got %d fields starting with %q, want 10 starting with #1 · 1001 Team 1", len(embed.Fields), embed.Fields[0].Name)
+	}
+
+	sent, _ := session.Last()
+	if len(sent.Components) != 1 {
+		t.Errorf("sent %d component rows, want the page buttons", len(sent.Components))
+	}
+}
+
+func TestLeadNextPage(t *testing.T) {
+	fakeData.rankings["2025 USLEADQ2"] = fakeRankings(12)
+	session := sessiontest.New()
+	interactions.CommandHandlers["lead"](session, leadInteraction("event", "USLEADQ2", "year", "2025"))
+	first, _ := session.Last()
+
+	// click the next button on the message that was just sent
+	buttons := first.Components[0].(discordgo.ActionsRow).Components
+	next := buttons[len(buttons)-1].(*discordgo.Button)
+	click := sessiontest.NewInteraction("lead-guild", "member", discordgo.ApplicationCommandInteractionData{})
+	click.Type = discordgo.InteractionMessageComponent
+	click.Data = discordgo.MessageComponentInteractionData{CustomID: next.CustomID, ComponentType: discordgo.ButtonComponent}
+	click.Message = &discordgo.Message{Embeds: first.Embeds}
+	interactionCreateHandler(session, click)
+
+	page, _ := session.Last()
+	if page.Kind != "respond" || len(page.Embeds) != 1 {
+		t.Fatalf("page turn sent %+v", page)
+	}
+	if page.Embeds[0].Title != "2025 USLEADQ2 Leaderboard (Part 2/2)" || len(page.Embeds[0].Fields) != 2 {
+		t.Errorf("page 2 is %q with %d fields", page.Embeds[0].Title, len(page.Embeds[0].Fields))
+	}
+}
+
+func TestLeadSortedByRP(t *testing.T) {
+	fakeData.rankings["2025 USLEADQ3"] = fakeRankings(3)
+	session := sessiontest.New()
+
+	interactions.CommandHandlers["lead"](session, leadInteraction("event", "USLEADQ3", "year", "2025", "sort", "rp"))
+
+	embeds := session.Embeds()
+	if len(embeds) != 1 {
+		t.Fatalf("sent %d embeds, want 1", len(embeds))
+	}
+	if embeds[0].Description != "Sorted by Ranking Points" {
+		t.Errorf("description = %q", embeds[0].Description)
+	}
+	if embeds[0].Fields[0].Name != "#3 · 1003 Team 3" {
+		t.Errorf("first team is %q, want the one with the most RP", embeds[0].Fields[0].Name)
+	}
+}
+
+func TestLeadUsesGuildProvider(t *testing.T) {
+	other := &fakeProvider{
+		name:     "fake-other",
+		rankings: map[string][]ftcscout.EventTeam{"2025 USLEADQ4": fakeRankings(3)},
+	}
+	provider.Register(other)
+	if err := guildconfig.Update("other-lead-guild", func(cfg *guildconfig.Config) { cfg.DataProvider = other.Name() }); err != nil {
+		t.Fatalf("guildconfig.Update: %v", err)
+	}
+
+	// only the guild's provider has this event
+	session := sessiontest.New()
+	interactions.CommandHandlers["lead"](session, sessiontest.NewInteraction("other-lead-guild", "member", discordgo.ApplicationCommandInteractionData{
+		Name:    "lead",
+		Options: stringOptions("event", "USLEADQ4", "year", "2025"),
+	}))
+	if embeds := session.Embeds(); len(embeds) != 1 || len(embeds[0].Fields) != 3 {
+		t.Fatalf("sent %+v, want the leaderboard from the guild's provider", session.Sent())
+	}
+
+	// and the default one's cached leaderboard isn't mixed up with it
+	session = sessiontest.New()
+	interactions.CommandHandlers["lead"](session, leadInteraction("event", "USLEADQ4", "year", "2025"))
+	if len(session.Embeds()) != 0 {
+		t.Errorf("a guild on the default provider got %+v", session.Embeds())
+	}
+}
+
+func TestLeadTextCommandErrors(t *testing.T) {
+	session := sessiontest.New()
+	message := sessiontest.NewMessage("lead-guild", "channel", "member", ">>lead 2025 USLEADQ1 wins")
+
+	leadcmd(session, message, nil, []string{"2025", "USLEADQ1", "wins"})
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Unknown sort") {
+		t.Errorf("unknown sort got %q", got)
+	}
+
+	leadcmd(session, message, nil, []string{"2025", "NOSUCHEVENT"})
+	sent, _ := session.Last()
+	if sent.Kind != "channel" || !strings.HasPrefix(sent.Content, "Error sending leaderboard") {
+		t.Errorf("missing event got %+v", sent)
+	}
+}
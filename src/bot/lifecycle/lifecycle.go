@@ -0,0 +1,193 @@
+// Package lifecycle owns everything that runs in the background while the bot is connected, so it can all be
+// stopped together on shutdown or /mech restart instead of piling up another copy of every goroutine.
+//
+// A Manager hands out a root context. Workers started with Go or Every run until it's cancelled, interactions
+// mark themselves in flight with Track so Stop can let them finish, and OnStop hooks save state once it's all quiet.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/logging"
+)
+
+var logger = logging.For("lifecycle")
+
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	stopping bool
+	// worker name -> how many are running, so Stop can say which ones are stuck
+	running  map[string]int
+	onStop   []func()
+	workers  counter
+	inFlight counter
+}
+
+// counter is a WaitGroup that Stop can wait on with a timeout. done is closed once the manager is stopping
+// and nothing is left, so giving up on the wait doesn't leave a goroutine stuck behind it. Guarded by Manager.mu.
+type counter struct {
+	count int
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newCounter() counter {
+	return counter{done: make(chan struct{})}
+}
+
+func (c *counter) closeIfIdle(stopping bool) {
+	if stopping && c.count <= 0 {
+		c.once.Do(func() { close(c.done) })
+	}
+}
+
+func New(parent context.Context) *Manager {
+	ctx, cancel := context.WithCancel(parent)
+	return &Manager{ctx: ctx, cancel: cancel, running: make(map[string]int), workers: newCounter(), inFlight: newCounter()}
+}
+
+// Context is cancelled when the manager starts stopping its workers
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Go runs a worker in its own goroutine, it has to return once ctx is done
+func (m *Manager) Go(name string, worker func(ctx context.Context)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopping {
+		logger.Warn("Not starting worker, already stopping", "worker", name)
+		return
+	}
+
+	m.running[name]++
+	m.workers.count++
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.running[name]--
+			m.workers.count--
+			m.workers.closeIfIdle(m.stopping)
+		}()
+
+		logger.Debug("Worker started", "worker", name)
+		worker(m.ctx)
+		logger.Debug("Worker stopped", "worker", name)
+	}()
+}
+
+// Every runs fn once per interval until the manager stops. Runs never overlap, a slow one just delays the next.
+func (m *Manager) Every(name string, interval time.Duration, fn func(ctx context.Context)) {
+	m.Go(name, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				fn(ctx)
+			}
+		}
+	})
+}
+
+// Track marks an interaction as in flight so Stop waits for it. It returns false once the manager is stopping,
+// in which case the interaction should be dropped. Otherwise done has to be called when it's finished.
+func (m *Manager) Track() (done func(), ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopping {
+		return nil, false
+	}
+
+	m.inFlight.count++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.inFlight.count--
+			m.inFlight.closeIfIdle(m.stopping)
+		})
+	}, true
+}
+
+// OnStop adds a hook that runs after the workers have stopped, hooks run in reverse order like defers
+func (m *Manager) OnStop(hook func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onStop = append(m.onStop, hook)
+}
+
+// Stop turns away new interactions, waits for the ones in flight, cancels the workers and waits for them,
+// then runs the OnStop hooks. Each wait gives up after timeout so a stuck API call can't hold up a restart forever,
+// the hooks still run and the error says what didn't finish. Whatever is still running then is abandoned,
+// Stop doesn't wait for it again and it keeps going in the background until it returns on its own.
+func (m *Manager) Stop(timeout time.Duration) error {
+	m.mu.Lock()
+	if m.stopping {
+		m.mu.Unlock()
+		return fmt.Errorf("already stopped")
+	}
+	m.stopping = true
+	m.inFlight.closeIfIdle(true)
+	m.workers.closeIfIdle(true)
+	hooks := m.onStop
+	m.mu.Unlock()
+
+	var errs []string
+	if !waitTimeout(m.inFlight.done, timeout) {
+		errs = append(errs, "interactions still in flight")
+	}
+
+	m.cancel()
+	if !waitTimeout(m.workers.done, timeout) {
+		errs = append(errs, fmt.Sprintf("workers still running: %s", strings.Join(m.stillRunning(), ", ")))
+	}
+
+	for idx := len(hooks) - 1; idx >= 0; idx-- {
+		hooks[idx]()
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("stopped after %v with %s", timeout, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (m *Manager) stillRunning() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := []string{}
+	for name, count := range m.running {
+		if count > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// waitTimeout returns false if done still isn't closed after timeout
+func waitTimeout(done <-chan struct{}, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
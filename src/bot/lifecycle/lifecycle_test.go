@@ -0,0 +1,130 @@
+package lifecycle
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStopRunsHooksInReverseAfterWorkers(t *testing.T) {
+	manager := New(context.Background())
+
+	var mu sync.Mutex
+	var order []string
+	record := func(step string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, step)
+	}
+
+	manager.Go("poller", func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		record("worker stopped")
+	})
+	manager.OnStop(func() { record("first hook") })
+	manager.OnStop(func() { record("second hook") })
+
+	if err := manager.Stop(time.Second); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if got := strings.Join(order, ", "); got != "worker stopped, second hook, first hook" {
+		t.Errorf("order = %s", got)
+	}
+
+	if err := manager.Stop(time.Second); err == nil {
+		t.Error("stopping twice should fail")
+	}
+}
+
+func TestEveryStopsOnCancel(t *testing.T) {
+	manager := New(context.Background())
+
+	var runs atomic.Int32
+	manager.Every("tick", time.Millisecond, func(ctx context.Context) {
+		runs.Add(1)
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if runs.Load() < 3 {
+		t.Fatalf("only ran %d times", runs.Load())
+	}
+
+	if err := manager.Stop(time.Second); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	stoppedAt := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	if runs.Load() != stoppedAt {
+		t.Errorf("ran %d more times after Stop returned", runs.Load()-stoppedAt)
+	}
+}
+
+func TestStopGivesUpOnStuckWorker(t *testing.T) {
+	manager := New(context.Background())
+
+	release := make(chan struct{})
+	defer close(release)
+	manager.Go("stuck", func(ctx context.Context) {
+		// ignores ctx, like a request without a timeout
+		<-release
+	})
+	manager.Go("polite", func(ctx context.Context) {
+		<-ctx.Done()
+	})
+
+	hookRan := false
+	manager.OnStop(func() { hookRan = true })
+
+	started := time.Now()
+	err := manager.Stop(50 * time.Millisecond)
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("Stop took %v with a 50ms timeout", elapsed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "workers still running: stuck") || strings.Contains(err.Error(), "polite") {
+		t.Errorf("err = %v, want it to name only the stuck worker", err)
+	}
+	if !hookRan {
+		t.Error("hooks should still run after the timeout")
+	}
+}
+
+func TestStopWaitsForInFlight(t *testing.T) {
+	manager := New(context.Background())
+
+	done, ok := manager.Track()
+	if !ok {
+		t.Fatal("Track refused before Stop")
+	}
+	finished := atomic.Bool{}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		finished.Store(true)
+		done()
+		// calling it again is harmless
+		done()
+	}()
+
+	if err := manager.Stop(time.Second); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if !finished.Load() {
+		t.Error("Stop returned before the interaction finished")
+	}
+
+	if _, ok := manager.Track(); ok {
+		t.Error("Track accepted an interaction after Stop")
+	}
+	ran := false
+	manager.Go("late", func(ctx context.Context) { ran = true })
+	time.Sleep(10 * time.Millisecond)
+	if ran {
+		t.Error("Go started a worker after Stop")
+	}
+}
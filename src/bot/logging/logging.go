@@ -0,0 +1,129 @@
+// Package logging sets up the bot's logs on top of log/slog. Every part of the bot gets its own logger from For
+// so its lines can be filtered by component, and interaction handlers add the guild, channel and user they're for.
+//
+// BJORN_LOG_LEVEL picks the starting level (debug, info, warn or error, info by default) and BJORN_LOG_FORMAT=json
+// switches to one JSON object per line for containers. The level can be changed later with SetLevel, which admins
+// reach through /mech loglevel.
+package logging
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	level = new(slog.LevelVar)
+	root  *slog.Logger
+)
+
+func init() {
+	if err := SetLevel(os.Getenv("BJORN_LOG_LEVEL")); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid BJORN_LOG_LEVEL, using info: %v\n", err)
+	}
+	root = slog.New(newHandler(os.Stderr, os.Getenv("BJORN_LOG_FORMAT")))
+	// anything still using the log package ends up in the same place
+	slog.SetDefault(root)
+}
+
+func newHandler(w io.Writer, format string) slog.Handler {
+	options := &slog.HandlerOptions{Level: level}
+	if strings.EqualFold(format, "json") {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// For returns the logger for a part of the bot, e.g. "tracker" or "search"
+func For(component string) *slog.Logger {
+	return root.With("component", component)
+}
+
+// SetLevel changes the level of every logger right away, "" resets it to info
+func SetLevel(name string) error {
+	if name == "" {
+		level.Set(slog.LevelInfo)
+		return nil
+	}
+
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("unknown log level %q, use debug, info, warn or error", name)
+	}
+	level.Set(parsed)
+	return nil
+}
+
+// Level is the level logs are written at right now
+func Level() slog.Level {
+	return level.Level()
+}
+
+// WithInteraction adds the guild, channel, user and interaction ID to a logger so every line about it can be found
+func WithInteraction(logger *slog.Logger, i *discordgo.InteractionCreate) *slog.Logger {
+	if i == nil || i.Interaction == nil {
+		return logger
+	}
+
+	var userID string
+	if i.Member != nil && i.Member.User != nil {
+		userID = i.Member.User.ID
+	} else if i.User != nil {
+		userID = i.User.ID
+	}
+	return logger.With("guild", i.GuildID, "channel", i.ChannelID, "user", userID, "interaction", i.ID)
+}
+
+// WithMessage is WithInteraction for text commands
+func WithMessage(logger *slog.Logger, message *discordgo.MessageCreate) *slog.Logger {
+	if message == nil || message.Message == nil {
+		return logger
+	}
+
+	var userID string
+	if message.Author != nil {
+		userID = message.Author.ID
+	}
+	return logger.With("guild", message.GuildID, "channel", message.ChannelID, "user", userID, "message", message.ID)
+}
+
+// LevelHandler shows the log level on GET and changes it on PUT or POST with the level as the body.
+// Changing it needs "Authorization: Bearer <token>", and with no token set the level can only be read.
+func LevelHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			if token == "" {
+				http.Error(w, "changing the log level over http is turned off, use /mech loglevel", http.StatusForbidden)
+				return
+			}
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := SetLevel(strings.TrimSpace(string(body))); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			For("logging").Info("Log level changed", "level", Level().String())
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		fmt.Fprintln(w, strings.ToLower(Level().String()))
+	})
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func changeLevel(t *testing.T, handler http.Handler, level, auth string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(level))
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLevelHandler(t *testing.T) {
+	t.Cleanup(func() { SetLevel("") })
+	SetLevel("info")
+	handler := LevelHandler("secret")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/loglevel", nil))
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "info" {
+		t.Errorf("GET = %d %q, want 200 info", rec.Code, rec.Body.String())
+	}
+
+	for _, auth := range []string{"", "Bearer wrong", "secret"} {
+		if rec := changeLevel(t, handler, "debug", auth); rec.Code != http.StatusUnauthorized {
+			t.Errorf("PUT with %q = %d, want 401", auth, rec.Code)
+		}
+	}
+	if Level() != slog.LevelInfo {
+		t.Fatalf("level changed to %v without the token", Level())
+	}
+
+	if rec := changeLevel(t, handler, "debug", "Bearer secret"); rec.Code != http.StatusOK || Level() != slog.LevelDebug {
+		t.Errorf("PUT with the token = %d, level %v", rec.Code, Level())
+	}
+	if rec := changeLevel(t, handler, "loud", "Bearer secret"); rec.Code != http.StatusBadRequest {
+		t.Errorf("PUT with a bad level = %d, want 400", rec.Code)
+	}
+}
+
+func TestLevelHandlerReadOnlyWithoutToken(t *testing.T) {
+	t.Cleanup(func() { SetLevel("") })
+	SetLevel("warn")
+
+	if rec := changeLevel(t, LevelHandler(""), "debug", "Bearer "); rec.Code != http.StatusForbidden {
+		t.Errorf("PUT = %d, want 403", rec.Code)
+	}
+	if Level() != slog.LevelWarn {
+		t.Errorf("level changed to %v", Level())
+	}
+}
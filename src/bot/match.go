@@ -2,19 +2,23 @@ package bot
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
+	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/lifecycle"
+	"github.com/shuban-789/bjorn/src/bot/logging"
 	"github.com/shuban-789/bjorn/src/bot/presets"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/render"
 	"github.com/shuban-789/bjorn/src/bot/search"
-	"github.com/shuban-789/bjorn/src/bot/util"
 )
 
 func init() {
@@ -28,12 +32,6 @@ func init() {
 					Name:        "info",
 					Description: "Lookup information about a certain match.",
 					Options: []*discordgo.ApplicationCommandOption{
-						{
-							Type:        discordgo.ApplicationCommandOptionString,
-							Name:        "year",
-							Description: "Year of the event (e.g., 2025).",
-							Required:    true,
-						},
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
 							Name:        "event_code",
@@ -46,6 +44,12 @@ func init() {
 							Description: "The match ID/number to look up.",
 							Required:    true,
 						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
+						},
 					},
 				},
 				{
@@ -55,16 +59,16 @@ func init() {
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
-							Name:        "year",
-							Description: "Year of the event (e.g., 2025).",
+							Name:        "event_code",
+							Description: "The event code to track.",
 							Required:    true,
-							Choices:     interactions.FtcYearChoices,
 						},
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
-							Name:        "event_code",
-							Description: "The event code to track.",
-							Required:    true,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
+							Choices:     interactions.FtcYearChoices,
 						},
 					},
 				},
@@ -73,13 +77,6 @@ func init() {
 					Name:        "track",
 					Description: "Start an active match tracker for a current event.",
 					Options: []*discordgo.ApplicationCommandOption{
-						{
-							Type:        discordgo.ApplicationCommandOptionString,
-							Name:        "year",
-							Description: "Year of the event (e.g., 2025).",
-							Required:    true,
-							Choices:     interactions.FtcYearChoices,
-						},
 						{
 							Type:         discordgo.ApplicationCommandOptionString,
 							Name:         "region",
@@ -100,6 +97,13 @@ func init() {
 							Description: "Whether to show matches already completed, or only show new ones.",
 							Required:    false,
 						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
+							Choices:     interactions.FtcYearChoices,
+						},
 					},
 				},
 				{
@@ -109,22 +113,39 @@ func init() {
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
-							Name:        "year",
-							Description: "Year of the event (e.g., 2025).",
+							Name:        "event_code",
+							Description: "The event code to view bracket for.",
 							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
 							Choices:     interactions.FtcYearChoices,
 						},
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
-							Name:        "event_code",
-							Description: "The event code to view bracket for.",
-							Required:    true,
+							Name:        "theme",
+							Description: "Color theme for the bracket (defaults to this server's theme).",
+							Required:    false,
+							Choices:     themeChoices(),
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "format",
+							Description: "Image format (defaults to png).",
+							Required:    false,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "png", Value: string(render.PNG)},
+								{Name: "svg", Value: string(render.SVG)},
+							},
 						},
 					},
 				},
 			},
 		},
-		func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
 			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
 			data := i.ApplicationCommandData()
 			if len(data.Options) == 0 {
@@ -135,39 +156,39 @@ func init() {
 			subName := sub.Name
 			switch subName {
 			case "info":
-				year := interactions.GetStringOption(sub.Options, "year")
+				year := guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(sub.Options, "year"))
 				eventCode := interactions.GetStringOption(sub.Options, "event_code")
 				matchNumber := interactions.GetStringOption(sub.Options, "match_number")
 				if year == "" || eventCode == "" || matchNumber == "" {
-					interactions.SendMessage(s, i, "", "Usage: /match info <year> <event_code> <match_number>")
+					interactions.SendMessage(s, i, "", "Usage: /match info <event_code> <match_number> [year]")
 					return
 				}
 				matchcmd(s, nil, i, []string{"info", year, eventCode, matchNumber})
 			case "eventstart":
-				year := interactions.GetStringOption(sub.Options, "year")
+				year := guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(sub.Options, "year"))
 				eventCode := interactions.GetStringOption(sub.Options, "event_code")
 				if year == "" || eventCode == "" {
-					interactions.SendMessage(s, i, "", "Usage: /match eventstart <year> <event_code>")
+					interactions.SendMessage(s, i, "", "Usage: /match eventstart <event_code> [year]")
 					return
 				}
 				matchcmd(s, nil, i, []string{"eventstart", year, eventCode})
 			case "track":
-				year := interactions.GetStringOption(sub.Options, "year")
+				year := guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(sub.Options, "year"))
 				eventCode := interactions.GetStringOption(sub.Options, "event")
 				if year == "" || eventCode == "" {
-					interactions.SendMessage(s, i, "", "Usage: /match track <year> <region> <event_name>")
+					interactions.SendMessage(s, i, "", "Usage: /match track <region> <event_name> [year]")
 					return
 				}
 				showCompleted := interactions.GetBoolOption(sub.Options, "show_completed", true)
 				matchcmd(s, nil, i, []string{"eventstart", year, eventCode, fmt.Sprintf("%t", showCompleted)})
 			case "bracket":
-				year := interactions.GetStringOption(sub.Options, "year")
+				year := guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(sub.Options, "year"))
 				eventCode := interactions.GetStringOption(sub.Options, "event_code")
 				if year == "" || eventCode == "" {
-					interactions.SendMessage(s, i, "", "Usage: /match bracket <year> <event_code>")
+					interactions.SendMessage(s, i, "", "Usage: /match bracket <event_code> [year]")
 					return
 				}
-				handleBracketCommand(s, i, year, eventCode)
+				handleBracketCommand(s, i, year, eventCode, interactions.GetStringOption(sub.Options, "theme"), interactions.GetStringOption(sub.Options, "format"))
 			default:
 				interactions.SendMessage(s, i, "", "Unknown subcommand for match.")
 			}
@@ -223,26 +244,32 @@ type MatchResult struct {
 type EventTracked struct {
 	Year                 string
 	EventCode            string
+	GuildId              string
 	UpdateChannelId      string
 	LastUpdateTime       time.Time
 	CachedMatches        []Match
 	LastProcessedMatchId int
+	LastPredictedMatchId int
 	Ongoing              bool
 	StartTime            time.Time
 	EndTime              time.Time
 	Tournament           DoubleElimTournament
+	// awards watchers were already DMed about, "type placement team"
+	NotifiedAwards map[string]bool
+	// the last match teams were told they're on deck for
+	LastOnDeckMatchId int
+	// whether the alliance selection results were posted
+	AlliancesAnnounced bool
+	// when the matches were last fetched without an error, for the metrics endpoint
+	LastSuccessfulPoll time.Time
 }
 
 var eventsBeingTracked []EventTracked
 
 // this is used in the api call to get a match, it's a small part of it but I use this in other funcs so I define it globally
-type TeamDTO struct {
-	AllianceColor string `json:"alliance"`
-	AllianceRole  string `json:"allianceRole"`
-	TeamNumber    int    `json:"teamNumber"`
-}
+type TeamDTO = ftcscout.MatchTeam
 
-func matchcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
+func matchcmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
 	authorId, authorRetrieved := interactions.GetAuthorId(message, i)
 	guildId, _ := interactions.GetGuildId(message, i)
 	channelId := interactions.GetChannelId(message, i)
@@ -262,7 +289,7 @@ func matchcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *d
 	switch subCommand {
 	case "info":
 		if len(args) < 4 {
-			interactions.SendMessage(session, i, channelId, "Usage: `>>match info <year> <eventCode> <matchNumber>`")
+			interactions.SendMessage(session, i, channelId, fmt.Sprintf("Usage: `%smatch info <year> <eventCode> <matchNumber>`", guildconfig.Get(guildId).CommandPrefix))
 			return
 		}
 
@@ -273,7 +300,7 @@ func matchcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *d
 		getMatch(channelId, year, eventCode, matchNumber, nil, session, i)
 	case "eventstart":
 		if len(args) < 3 {
-			interactions.SendMessage(session, i, channelId, "Usage: `>>match eventstart <year> <eventCode>`")
+			interactions.SendMessage(session, i, channelId, fmt.Sprintf("Usage: `%smatch eventstart <year> <eventCode>`", guildconfig.Get(guildId).CommandPrefix))
 			return
 		}
 
@@ -300,8 +327,9 @@ func matchcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *d
 	}
 }
 
-func eventStart(channelID, guildID, year, eventCode string, showCompleted bool, session *discordgo.Session, i *discordgo.InteractionCreate) {
-	eventDetails, err := search.FetchEventData(year, eventCode)
+func eventStart(channelID, guildID, year, eventCode string, showCompleted bool, session interactions.Session, i *discordgo.InteractionCreate) {
+	dataProvider := guildconfig.Provider(guildID)
+	eventDetails, err := search.FetchEventDataFrom(dataProvider, year, eventCode)
 	if err != nil {
 		interactions.SendMessage(session, i, channelID, err.Error())
 		return
@@ -332,28 +360,35 @@ func eventStart(channelID, guildID, year, eventCode string, showCompleted bool,
 	lastProcessedMatchId := -100 // Will process all matches
 	if !showCompleted {
 		// Fetch current matches to find the highest played match ID
-		url := fmt.Sprintf("https://api.ftcscout.org/rest/v1/events/%s/%s/matches", year, eventCode)
-		resp, err := http.Get(url)
+		matches, err := dataProvider.EventMatches(context.Background(), year, eventCode)
 		if err == nil {
-			defer resp.Body.Close()
-			body, err := io.ReadAll(resp.Body)
-			if err == nil {
-				var matches []Match
-				if json.Unmarshal(body, &matches) == nil {
-					for _, match := range matches {
-						if match.HasBeenPlayed && match.ID > lastProcessedMatchId {
-							lastProcessedMatchId = match.ID
-						}
-					}
+			for _, match := range matches {
+				if match.HasBeenPlayed && match.ID > lastProcessedMatchId {
+					lastProcessedMatchId = match.ID
 				}
 			}
 		}
 	}
 
+	// servers can send all tracker posts to one announcement channel no matter where it was started
+	updateChannelID := channelID
+	if announcementChannel := guildconfig.Get(guildID).AnnouncementChannelId; announcementChannel != "" {
+		updateChannelID = announcementChannel
+	}
+
+	trackedMu.Lock()
+	for _, tracked := range eventsBeingTracked {
+		if tracked.Year == year && tracked.EventCode == eventCode && tracked.UpdateChannelId == updateChannelID {
+			trackedMu.Unlock()
+			interactions.SendMessage(session, i, channelID, fmt.Sprintf("Already tracking event %s in %s in <#%s> (last posted match: %d).", eventCode, year, updateChannelID, tracked.LastProcessedMatchId))
+			return
+		}
+	}
 	eventsBeingTracked = append(eventsBeingTracked, EventTracked{
 		Year:                 year,
 		EventCode:            eventCode,
-		UpdateChannelId:      channelID,
+		GuildId:              guildID,
+		UpdateChannelId:      updateChannelID,
 		LastUpdateTime:       time.Date(1, time.January, 1, 1, 1, 1, 1, time.Now().Location()), // hopefully will force an immediate update
 		CachedMatches:        []Match{},
 		LastProcessedMatchId: lastProcessedMatchId,
@@ -366,11 +401,16 @@ func eventStart(channelID, guildID, year, eventCode string, showCompleted bool,
 			Eliminated:     make(map[TwoTeamAlliance]bool),
 		},
 	})
+	saveTrackedEvents()
+	trackedMu.Unlock()
 
 	statusMsg := fmt.Sprintf("Started tracking matches for event %s in %s...", eventCode, year)
 	if !showCompleted && lastProcessedMatchId > 0 {
 		statusMsg += fmt.Sprintf(" (skipping %d already completed matches)", lastProcessedMatchId)
 	}
+	if updateChannelID != channelID {
+		statusMsg += fmt.Sprintf(" Updates will be posted in <#%s>.", updateChannelID)
+	}
 	interactions.SendMessage(session, i, channelID, statusMsg)
 
 	if startTime.Before(today) {
@@ -394,92 +434,150 @@ func eventStart(channelID, guildID, year, eventCode string, showCompleted bool,
 		},
 	})
 	if err != nil {
-		log.Fatalf("Error creating event: %v", err)
+		logging.WithInteraction(commandLog, i).Error("Error creating scheduled event", "event", eventCode, "err", err)
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to create the event: %v", err))
+		return
 	}
 	interactions.SendMessage(session, i, channelID, fmt.Sprintf("Created event: %s", event.ID))
 }
 
-func getMatch(ChannelID string, year string, eventCode string, matchNumber string, event *EventTracked, session *discordgo.Session, i *discordgo.InteractionCreate) {
-	url := fmt.Sprintf("https://api.ftcscout.org/rest/v1/events/%s/%s/matches", year, eventCode)
-	resp, err := http.Get(url)
+func getMatch(ChannelID string, year string, eventCode string, matchNumber string, event *EventTracked, session interactions.Session, i *discordgo.InteractionCreate) {
+	var guildID string
+	if i != nil {
+		guildID = i.GuildID
+	} else if event != nil {
+		guildID = event.GuildId
+	}
+
+	dataProvider := guildconfig.Provider(guildID)
+	matches, err := dataProvider.EventMatches(context.Background(), year, eventCode)
 	if err != nil {
 		interactions.SendMessage(session, i, ChannelID, fmt.Sprintf("Failed to fetch match data: %v", err))
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		interactions.SendMessage(session, i, ChannelID, fmt.Sprintf("Failed to read response: %v", err))
+	var found ftcscout.Match
+	matchFound := false
+	for _, match := range matches {
+		if fmt.Sprintf("%d", match.ID) == matchNumber {
+			found = match
+			matchFound = true
+			break
+		}
+	}
+
+	if !matchFound {
+		interactions.SendMessage(session, i, ChannelID, fmt.Sprintf("Couldn't find match %s at %s %s.", matchNumber, year, eventCode))
 		return
 	}
 
-	type TeamScoreDetail struct {
-		Total  int `json:"totalPoints"`
-		Auto   int `json:"autoPoints"`
-		TeleOp int `json:"dcPoints"` // "driver controlled"
-		Fouls  int `json:"penaltyPointsByOpp"`
+	matchName := getMatchName(found)
+
+	// show a forecast instead of an empty scoreboard for matches that haven't happened yet
+	if !found.HasBeenPlayed {
+		embed, err := createPredictionEmbed(eventCode, matchName, matches, found)
+		if err != nil {
+			interactions.SendMessage(session, i, ChannelID, fmt.Sprintf("%s hasn't been played yet, and %v.", matchName, err))
+			return
+		}
+		interactions.SendEmbed(session, i, ChannelID, embed)
+		return
 	}
 
-	var matches []struct {
-		ID     int `json:"id"`
-		Scores struct {
-			Red  TeamScoreDetail `json:"red"`
-			Blue TeamScoreDetail `json:"blue"`
-		} `json:"scores"`
-		Teams           []TeamDTO `json:"teams"`
-		TournamentLevel string    `json:"tournamentLevel"`
-		Series          int       `json:"series"`
+	embed := createMatchResultEmbed(eventCode, found)
+
+	discordMsg := &discordgo.MessageSend{
+		Embed: embed,
 	}
-	err = json.Unmarshal(body, &matches)
-	if err != nil {
-		interactions.SendMessage(session, i, ChannelID, fmt.Sprintf("Failed to parse match data: %v", err))
-		return
+
+	// For DoubleElim (playoff) matches, generate and attach bracket image
+	var bracketBuf *bytes.Buffer
+	if found.TournamentLevel == "DoubleElim" {
+		// replay every playoff match we already have so the bracket is complete even if we missed some
+		tracker := rebuildBracketTrackerFromMatches(dataProvider, year, eventCode, matches)
+
+		// Generate bracket image
+		var err error
+		bracketBuf, err = tracker.RenderBracket(render.PNG, guildconfig.Theme(guildID))
+		if err != nil {
+			trackerLog.Error("Failed to generate bracket image", "year", year, "event", eventCode, "err", err)
+		} else {
+			discordMsg.Files = []*discordgo.File{
+				{
+					Name:   "bracket.png",
+					Reader: bracketBuf,
+				},
+			}
+			// Update embed to show bracket image
+			embed.Image = &discordgo.MessageEmbedImage{
+				URL: "attachment://bracket.png",
+			}
+		}
 	}
 
-	var selectedMatch struct {
-		Scores struct {
-			Red  TeamScoreDetail
-			Blue TeamScoreDetail
+	var msg *discordgo.Message
+	if i != nil {
+		// Edit the deferred interaction response to dismiss "thinking..." indicator
+		webhookEdit := &discordgo.WebhookEdit{
+			Embeds: &[]*discordgo.MessageEmbed{embed},
 		}
-		RedAlliance     TwoTeamAlliance
-		RedTeams        []TeamDTO
-		BlueAlliance    TwoTeamAlliance
-		BlueTeams       []TeamDTO
-		TournamentLevel string
-		ID              int
-		Series          int
-	}
-	redTeams := []TeamDTO{}
-	blueTeams := []TeamDTO{}
-	for _, match := range matches {
-		if fmt.Sprintf("%d", match.ID) == matchNumber {
-			selectedMatch.Scores.Red = match.Scores.Red
-			selectedMatch.Scores.Blue = match.Scores.Blue
-			for _, team := range match.Teams {
-				if team.AllianceColor == "Red" {
-					redTeams = append(redTeams, team)
-				} else if team.AllianceColor == "Blue" {
-					blueTeams = append(blueTeams, team)
-				} else {
-					// TODO: I'm going to crash out if this happens
-				}
-				selectedMatch.TournamentLevel = match.TournamentLevel
-				selectedMatch.ID = match.ID
-				selectedMatch.Series = match.Series
+		if bracketBuf != nil {
+			webhookEdit.Files = []*discordgo.File{
+				{
+					Name:   "bracket.png",
+					Reader: bracketBuf,
+				},
 			}
-			break
+		}
+		msg, err = session.InteractionResponseEdit(i.Interaction, webhookEdit)
+		if err != nil {
+			HandleErr(err)
+			return
+		}
+	} else {
+		msg, err = session.ChannelMessageSendComplex(ChannelID, discordMsg)
+		if err != nil {
+			HandleErr(err)
+			return
 		}
 	}
-	selectedMatch.RedTeams = redTeams
-	selectedMatch.BlueTeams = blueTeams
-	selectedMatch.RedAlliance = getAllianceFromTeams(redTeams)
-	selectedMatch.BlueAlliance = getAllianceFromTeams(blueTeams)
+
+	trackerLog.Debug("Sent match result", "year", year, "event", eventCode, "match", matchNumber, "message", msg.ID)
+	_, err = session.MessageThreadStartComplex(msg.ChannelID, msg.ID, &discordgo.ThreadStart{
+		Name:                matchName,
+		AutoArchiveDuration: interactions.AUTO_ARCHIVE_1_DAY,
+		Type:                discordgo.ChannelTypeGuildPublicThread,
+	})
+	HandleErr(err)
+}
+
+// splitAlliances returns the red and blue teams in a match
+func splitAlliances(match ftcscout.Match) (redTeams, blueTeams []TeamDTO) {
+	redTeams = []TeamDTO{}
+	blueTeams = []TeamDTO{}
+	for _, team := range match.Teams {
+		if team.AllianceColor == "Red" {
+			redTeams = append(redTeams, team)
+		} else if team.AllianceColor == "Blue" {
+			blueTeams = append(blueTeams, team)
+		} else {
+			// TODO: I'm going to crash out if this happens
+		}
+	}
+	return redTeams, blueTeams
+}
+
+// createMatchResultEmbed is the scoreboard for a played match, it's posted by the tracker and /match info
+// and DMed to anyone watching a team in it
+func createMatchResultEmbed(eventCode string, match ftcscout.Match) *discordgo.MessageEmbed {
+	redTeams, blueTeams := splitAlliances(match)
+	useQualsTeamNaming := match.TournamentLevel == "Quals"
+	red, blue := match.Scores.Red, match.Scores.Blue
 
 	winnerSkib := Red
-	if selectedMatch.Scores.Blue.Total > selectedMatch.Scores.Red.Total {
+	if blue.Total > red.Total {
 		winnerSkib = Blue
-	} else if selectedMatch.Scores.Blue.Total == selectedMatch.Scores.Red.Total {
+	} else if blue.Total == red.Total {
 		winnerSkib = Neither
 	}
 
@@ -490,95 +588,44 @@ func getMatch(ChannelID string, year string, eventCode string, matchNumber strin
 		color = 0xE8E4EC
 	}
 
-	var matchName string
-	if selectedMatch.TournamentLevel == "Quals" {
-		matchName = fmt.Sprintf("Qualification %d", selectedMatch.ID)
-	} else if selectedMatch.TournamentLevel == "DoubleElim" {
-		matchName = fmt.Sprintf("Playoffs Match %d", selectedMatch.Series)
-	} else {
-		matchName = fmt.Sprintf("Match %d?", selectedMatch.ID)
-	}
-	useQualsTeamNaming := selectedMatch.TournamentLevel == "Quals"
 	var redAlliance strings.Builder
 	var blueAlliance strings.Builder
-	// supports if there are any number of teams (including more than 2 for some reason)
-	nTeamsRed := len(selectedMatch.RedTeams)
-	nTeamsBlue := len(selectedMatch.BlueTeams)
-	if useQualsTeamNaming {
-		for i, team := range selectedMatch.RedTeams {
-			if i > 0 {
-				if nTeamsRed > 2 {
-					redAlliance.WriteString(",")
-				}
-				if i == (nTeamsRed - 1) {
-					redAlliance.WriteString(" and ")
-				} else {
-					redAlliance.WriteString(" ")
-				}
-			}
-			redAlliance.WriteString(fmt.Sprintf("%d", team.TeamNumber))
-		}
-		for i, team := range selectedMatch.BlueTeams {
-			if i > 0 {
-				if nTeamsBlue > 2 {
-					blueAlliance.WriteString(",")
-				}
-				if i == (nTeamsBlue - 1) {
-					blueAlliance.WriteString(" and ")
-				} else {
-					blueAlliance.WriteString(" ")
-				}
-			}
-			blueAlliance.WriteString(fmt.Sprintf("%d", team.TeamNumber))
-		}
-	} else {
-		for i, team := range selectedMatch.RedTeams {
-			if i > 0 {
-				redAlliance.WriteRune('\n')
-			}
-			redAlliance.WriteString(fmt.Sprintf("%s: %d", team.AllianceRole, team.TeamNumber))
-		}
-		for i, team := range selectedMatch.BlueTeams {
-			if i > 0 {
-				blueAlliance.WriteRune('\n')
-			}
-			blueAlliance.WriteString(fmt.Sprintf("%s: %d", team.AllianceRole, team.TeamNumber))
-		}
-	}
+	redAlliance.WriteString(formatAllianceTeams(redTeams, useQualsTeamNaming))
+	blueAlliance.WriteString(formatAllianceTeams(blueTeams, useQualsTeamNaming))
 
 	redAlliance.WriteString("\n\n")
 	blueAlliance.WriteString("\n\n")
 
 	redAlliance.WriteString(fmt.Sprintf(
 		"**%d points",
-		selectedMatch.Scores.Red.Total,
+		red.Total,
 	))
 	if winnerSkib == Red {
 		redAlliance.WriteString(" 🏆")
 	}
 	redAlliance.WriteString(fmt.Sprintf(
 		"**\n • Auto: **%d**\n • TeleOp: **%d**\n • Fouls: **%d**\n\u200B",
-		selectedMatch.Scores.Red.Auto,
-		selectedMatch.Scores.Red.TeleOp,
-		selectedMatch.Scores.Red.Fouls,
+		red.Auto,
+		red.TeleOp,
+		red.Fouls,
 	))
 
 	blueAlliance.WriteString(fmt.Sprintf(
 		"**%d points",
-		selectedMatch.Scores.Blue.Total,
+		blue.Total,
 	))
 	if winnerSkib == Blue {
 		blueAlliance.WriteString(" 🏆")
 	}
 	blueAlliance.WriteString(fmt.Sprintf(
 		"**\n • Auto: **%d**\n • TeleOp: **%d**\n • Fouls: **%d**",
-		selectedMatch.Scores.Blue.Auto,
-		selectedMatch.Scores.Blue.TeleOp,
-		selectedMatch.Scores.Blue.Fouls,
+		blue.Auto,
+		blue.TeleOp,
+		blue.Fouls,
 	))
 
 	embed := &discordgo.MessageEmbed{
-		Title: fmt.Sprintf("%s %s: Results", eventCode, matchName),
+		Title: fmt.Sprintf("%s %s: Results", eventCode, getMatchName(match)),
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:   "Red Alliance  🔴\u200B",
@@ -593,262 +640,222 @@ func getMatch(ChannelID string, year string, eventCode string, matchNumber strin
 		},
 		Color: color,
 	}
+	return embed
+}
 
-	discordMsg := &discordgo.MessageSend{
-		Embed: embed,
+func getMatchName(match ftcscout.Match) string {
+	if match.TournamentLevel == "Quals" {
+		return fmt.Sprintf("Qualification %d", match.ID)
+	} else if match.TournamentLevel == "DoubleElim" {
+		return fmt.Sprintf("Playoffs Match %d", match.Series)
 	}
+	return fmt.Sprintf("Match %d?", match.ID)
+}
 
-	// For DoubleElim (playoff) matches, generate and attach bracket image
-	var bracketBuf *bytes.Buffer
-	if selectedMatch.TournamentLevel == "DoubleElim" {
-		// Get or create bracket tracker for this event
-		tracker := GetOrCreateBracketTracker(year, eventCode)
-
-		// Update bracket with this match result
-		tracker.UpdateBracketWithMatch(
-			selectedMatch.ID,
-			selectedMatch.Series,
-			selectedMatch.RedTeams,
-			selectedMatch.BlueTeams,
-			selectedMatch.Scores.Red.Total,
-			selectedMatch.Scores.Blue.Total,
-		)
-
-		// Generate bracket image
-		var err error
-		bracketBuf, err = tracker.GenerateBracketImage()
-		if err != nil {
-			fmt.Printf("Failed to generate bracket image: %v\n", err)
-		} else {
-			discordMsg.Files = []*discordgo.File{
-				{
-					Name:   "bracket.png",
-					Reader: bracketBuf,
-				},
-			}
-			// Update embed to show bracket image
-			embed.Image = &discordgo.MessageEmbedImage{
-				URL: "attachment://bracket.png",
+// formatAllianceTeams lists the teams on an alliance, quals read like "123 and 456",
+// playoffs list each team's role on its own line
+func formatAllianceTeams(teams []TeamDTO, useQualsTeamNaming bool) string {
+	var alliance strings.Builder
+	// supports if there are any number of teams (including more than 2 for some reason)
+	nTeams := len(teams)
+	for i, team := range teams {
+		if useQualsTeamNaming {
+			if i > 0 {
+				if nTeams > 2 {
+					alliance.WriteString(",")
+				}
+				if i == (nTeams - 1) {
+					alliance.WriteString(" and ")
+				} else {
+					alliance.WriteString(" ")
+				}
 			}
-		}
-	}
-
-	var msg *discordgo.Message
-	if i != nil {
-		// Edit the deferred interaction response to dismiss "thinking..." indicator
-		webhookEdit := &discordgo.WebhookEdit{
-			Embeds: &[]*discordgo.MessageEmbed{embed},
-		}
-		if bracketBuf != nil {
-			webhookEdit.Files = []*discordgo.File{
-				{
-					Name:   "bracket.png",
-					Reader: bracketBuf,
-				},
+			alliance.WriteString(fmt.Sprintf("%d", team.TeamNumber))
+		} else {
+			if i > 0 {
+				alliance.WriteRune('\n')
 			}
-		}
-		msg, err = session.InteractionResponseEdit(i.Interaction, webhookEdit)
-		if err != nil {
-			HandleErr(err)
-			return
-		}
-	} else {
-		msg, err = session.ChannelMessageSendComplex(ChannelID, discordMsg)
-		if err != nil {
-			HandleErr(err)
-			return
+			alliance.WriteString(fmt.Sprintf("%s: %d", team.AllianceRole, team.TeamNumber))
 		}
 	}
-
-	fmt.Print(msg.ID)
-	_, err = session.MessageThreadStartComplex(msg.ChannelID, msg.ID, &discordgo.ThreadStart{
-		Name:                matchName,
-		AutoArchiveDuration: interactions.AUTO_ARCHIVE_1_DAY,
-		Type:                discordgo.ChannelTypeGuildPublicThread,
-	})
-	HandleErr(err)
-
-	// // Get channel to retrieve guild ID
-	// // for some reason message.GuildId is empty when called from interaction
-	// channel, err := session.Channel(ChannelID)
-	// if HandleErr(err) {
-	//     return
-	// }
-	// guildID := channel.GuildID
-
-	// users, err := getUsersToPing(session, guildID, selectedMatch.RedTeams, selectedMatch.BlueTeams)
-	// if HandleErr(err) {
-	// 	session.ChannelMessageSend(thread.ID, fmt.Sprintf("Error getting users to ping: %v", err))
-	// }
-
-	// if len(users) > 0 {
-	// 	var mentions strings.Builder
-	// 	mentions.WriteString("Match update! ")
-	// 	for userID := range users {
-	// 		mentions.WriteString(fmt.Sprintf("<@%s> ", userID))
-	// 	}
-	// 	session.ChannelMessageSend(thread.ID, mentions.String())
-	// }
+	return alliance.String()
 }
 
-func getUsersToPing(session *discordgo.Session, guildId string, redTeams, blueTeams []TeamDTO) (map[string]bool, error) {
+// getUsersToPing finds who to mention about a match. Members opt in with the match ping role, and only the
+// ones who also have the role of a team in the match are returned. teamsWithRoles is every team in the match
+// that has a role in the server, whether or not anyone on it opted in.
+func getUsersToPing(session interactions.Session, guildId string, teams []int) (users map[string]bool, teamsWithRoles []int, err error) {
 	roles, err := session.GuildRoles(guildId)
 	if HandleErr(err) {
-		return nil, err
+		return nil, nil, err
 	}
 
-	teamNumbers := make([]string, 0)
-	for _, team := range redTeams {
-		teamNumbers = append(teamNumbers, fmt.Sprintf("%d", team.TeamNumber))
+	teamNumbers := make(map[int]bool)
+	for _, team := range teams {
+		teamNumbers[team] = true
 	}
-	for _, team := range blueTeams {
-		teamNumbers = append(teamNumbers, fmt.Sprintf("%d", team.TeamNumber))
+
+	normalizeRoleName := func(name string) string {
+		return strings.TrimSpace(strings.ReplaceAll(strings.ToLower(name), "-", " "))
 	}
+	matchPingRoleName := normalizeRoleName(guildconfig.Get(guildId).MatchPingRole)
 
-	teamRoleIDs := make([]string, 0)
+	teamRoleIDs := make(map[string]bool)
 	var matchPingRoleID string
 	for _, role := range roles {
-		for _, teamNum := range teamNumbers {
-			if strings.HasPrefix(strings.TrimSpace(role.Name), teamNum) {
-				teamRoleIDs = append(teamRoleIDs, role.ID)
-			}
+		if roleTeamNumber, ok := teamNumberFromRoleName(role.Name); ok && teamNumbers[roleTeamNumber] {
+			teamRoleIDs[role.ID] = true
+			teamsWithRoles = append(teamsWithRoles, roleTeamNumber)
 		}
 
 		// "match pings", "Match-Pings", etc should all work
-		roleNameLower := strings.ReplaceAll(strings.ToLower(role.Name), "-", " ")
-		if roleNameLower == "match pings" {
+		if normalizeRoleName(role.Name) == matchPingRoleName {
 			matchPingRoleID = role.ID
 		}
 	}
+	sort.Ints(teamsWithRoles)
 
 	usersToPing := make(map[string]bool)
+	if len(teamRoleIDs) == 0 || matchPingRoleID == "" {
+		return usersToPing, teamsWithRoles, nil
+	}
+
 	// todo: guildmembers only returns 1000 members at a time, sdftc doesn't have that many but it still bothers me, we'd need to implement pagination to fully fix
 	members, err := session.GuildMembers(guildId, "", 1000)
 	if HandleErr(err) {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, member := range members {
+		onTeam, optedIn := false, false
 		for _, memberRoleID := range member.Roles {
-			// Check team roles
-			for _, teamRoleID := range teamRoleIDs {
-				if memberRoleID == teamRoleID {
-					usersToPing[member.User.ID] = true
-				}
+			if teamRoleIDs[memberRoleID] {
+				onTeam = true
 			}
-			// Check match ping role
-			if matchPingRoleID != "" && memberRoleID == matchPingRoleID {
-				usersToPing[member.User.ID] = true
+			if memberRoleID == matchPingRoleID {
+				optedIn = true
 			}
 		}
+		if onTeam && optedIn {
+			usersToPing[member.User.ID] = true
+		}
 	}
 
-	return usersToPing, nil
+	return usersToPing, teamsWithRoles, nil
 }
 
-func eventUpdate(apiPollTime time.Duration, session *discordgo.Session) {
-	for i := 0; i < len(eventsBeingTracked); i++ {
-		event := &eventsBeingTracked[i]
-
-		fmt.Print(util.Info("Checking event: Year=%s, EventCode=%s, LastUpdateTime=%v\n",
-			event.Year, event.EventCode, event.LastUpdateTime))
+// eventLog is the tracker's logger with the event on every line
+func eventLog(event *EventTracked) *slog.Logger {
+	return trackerLog.With("guild", event.GuildId, "year", event.Year, "event", event.EventCode)
+}
 
+// eventUpdate polls every tracked event that's due. trackedMu is only held to copy the events out and write them
+// back, the API calls and posts happen on the copies so /match eventstart doesn't wait behind a whole poll.
+func eventUpdate(ctx context.Context, apiPollTime time.Duration, session interactions.Session) {
+	trackedMu.Lock()
+	var due []EventTracked
+	for idx := range eventsBeingTracked {
+		event := &eventsBeingTracked[idx]
 		if time.Since(event.LastUpdateTime) < apiPollTime {
-			fmt.Print(util.Info("Skipping update for event %s/%s (last updated %v)\n",
-				event.Year, event.EventCode, event.LastUpdateTime))
+			eventLog(event).Debug("Skipping update", "lastUpdate", event.LastUpdateTime)
 			continue
 		}
-
 		event.LastUpdateTime = time.Now()
+		due = append(due, event.clone())
+	}
+	trackedMu.Unlock()
 
-		eventDetails, err := search.FetchEventData(event.Year, event.EventCode)
-		if err != nil {
-			session.ChannelMessageSend(event.UpdateChannelId, err.Error())
+	for idx := range due {
+		// the rest can wait for the next start, shutdown is waiting on this
+		if ctx.Err() != nil {
 			return
 		}
-		if eventDetails.Ongoing && !event.Ongoing {
-			event.Ongoing = true
-			session.ChannelMessageSend(event.UpdateChannelId, fmt.Sprintf("The %s has started!", eventDetails.Name))
-		} else if !eventDetails.Ongoing && event.Ongoing {
-			// remove the event once it's done
-			eventsBeingTracked = append(eventsBeingTracked[:i], eventsBeingTracked[i+1:]...)
-			session.ChannelMessageSend(event.UpdateChannelId, fmt.Sprintf("The %s has ended!", eventDetails.Name))
+		if !pollTrackedEvent(ctx, session, &due[idx]) {
 			return
 		}
+	}
+}
 
-		url := fmt.Sprintf("https://api.ftcscout.org/rest/v1/events/%s/%s/matches", event.Year, event.EventCode)
-		fmt.Print(util.Info("Fetching matches from: %s\n", url))
-
-		resp, err := http.Get(url)
-		if err != nil {
-			fmt.Print(util.Fail("Failed to fetch match data for event %s/%s: %v\n",
-				event.Year, event.EventCode, err))
-			session.ChannelMessageSend(event.UpdateChannelId, fmt.Sprintf("Failed to fetch match data: %v", err))
-			continue
-		}
-		defer resp.Body.Close()
+// pollTrackedEvent fetches one event's matches and posts whatever is new. It returns false when the rest of
+// this round should be skipped.
+func pollTrackedEvent(ctx context.Context, session interactions.Session, event *EventTracked) bool {
+	logger := eventLog(event)
 
-		if resp.StatusCode == http.StatusNotFound {
-			fmt.Print(util.Info("Event %s/%s not found!\n", event.Year, event.EventCode))
-			session.ChannelMessageSend(event.UpdateChannelId, "That event does not exist!")
-			continue
-		}
+	dataProvider := guildconfig.Provider(event.GuildId)
+	eventDetails, err := search.FetchEventDataFrom(dataProvider, event.Year, event.EventCode)
+	if err != nil {
+		logger.Error("Failed to fetch event details", "err", err)
+		session.ChannelMessageSend(event.UpdateChannelId, err.Error())
+		return false
+	}
+	if eventDetails.Ongoing && !event.Ongoing {
+		event.Ongoing = true
+		storeTrackedEvent(*event)
+		session.ChannelMessageSend(event.UpdateChannelId, fmt.Sprintf("The %s has started!", eventDetails.Name))
+	} else if !eventDetails.Ongoing && event.Ongoing {
+		// awards usually get posted right at the end, so check one last time
+		notifyAwardWatchers(session, event, dataProvider)
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			fmt.Print(util.Fail("Failed to read response for event %s/%s: %v\n",
-				event.Year, event.EventCode, err))
-			session.ChannelMessageSend(event.UpdateChannelId, fmt.Sprintf("Failed to read response: %v", err))
-			continue
-		}
+		// remove the event once it's done
+		removeTrackedEvent(*event)
+		session.ChannelMessageSend(event.UpdateChannelId, fmt.Sprintf("The %s has ended!", eventDetails.Name))
+		return false
+	}
 
-		var matches []Match
-		err = json.Unmarshal(body, &matches)
-		if err != nil {
-			fmt.Printf("\033[31m[ERROR]\033[0m Failed to parse match data for event %s/%s: %v\n",
-				event.Year, event.EventCode, err)
-			session.ChannelMessageSend(event.UpdateChannelId, fmt.Sprintf("Failed to parse match data: %v", err))
-			continue
-		}
+	matches, err := dataProvider.EventMatches(ctx, event.Year, event.EventCode)
+	if provider.IsNotFound(err) {
+		logger.Warn("Event not found")
+		session.ChannelMessageSend(event.UpdateChannelId, "That event does not exist!")
+		return true
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		logger.Error("Failed to fetch match data", "err", err)
+		session.ChannelMessageSend(event.UpdateChannelId, fmt.Sprintf("Failed to fetch match data: %v", err))
+		return true
+	}
 
-		fmt.Print(util.Info("Matches fetched for event %s/%s: %d matches found\n",
-			event.Year, event.EventCode, len(matches)))
+	logger.Debug("Matches fetched", "matches", len(matches))
+	event.LastSuccessfulPoll = time.Now()
 
-		var newMatches []Match
-		for _, match := range matches {
-			fmt.Print(util.Info("Checking match: ID=%d, hasBeenPlayed=%v\n",
-				match.ID, match.GetHasBeenPlayed()))
+	// before the new matches so the first playoff bracket already has the right seeds
+	postAllianceSelection(session, event, eventDetails.Name, dataProvider, matches)
 
-			if match.ID > event.LastProcessedMatchId && match.GetHasBeenPlayed() {
-				newMatches = append(newMatches, match)
-				fmt.Print(util.Info("New match to process: ID=%d\n", match.ID))
-			}
+	var newMatches []ftcscout.Match
+	for _, match := range matches {
+		if match.ID > event.LastProcessedMatchId && match.HasBeenPlayed {
+			newMatches = append(newMatches, match)
 		}
+	}
 
-		if len(newMatches) > 0 {
-			for _, match := range newMatches {
-				fmt.Print(util.Info("Processing match: ID=%d\n", match.ID))
-				getMatch(event.UpdateChannelId, event.Year, event.EventCode, fmt.Sprintf("%d", match.ID), event, session, nil)
-			}
-			event.LastProcessedMatchId = newMatches[len(newMatches)-1].ID
-			fmt.Print(util.Info("Updated LastProcessedMatchId for event %s/%s: %d\n",
-				event.Year, event.EventCode, event.LastProcessedMatchId))
-		} else {
-			fmt.Println(util.Info("No new played matches found in this interval."))
+	if len(newMatches) > 0 {
+		for _, match := range newMatches {
+			logger.Info("Processing match", "match", match.ID)
+			getMatch(event.UpdateChannelId, event.Year, event.EventCode, fmt.Sprintf("%d", match.ID), event, session, nil)
+			notifyMatchWatchers(session, event, match)
+
+			// save after every post so a crash mid-batch doesn't repost what already went out
+			event.LastProcessedMatchId = match.ID
+			storeTrackedEvent(*event)
 		}
+		logger.Debug("Updated last processed match", "lastMatch", event.LastProcessedMatchId)
+	} else {
+		logger.Debug("No new played matches found in this interval")
 	}
+
+	postUpNext(session, event, matches)
+	postOnDeck(session, event, eventDetails.Timezone, matches)
+	notifyAwardWatchers(session, event, dataProvider)
+	storeTrackedEvent(*event)
+	return true
 }
 
-func startMatchEventUpdater(session *discordgo.Session, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				eventUpdate(interval, session)
-			}
-		}
-	}()
+func startMatchEventUpdater(lc *lifecycle.Manager, session interactions.Session, interval time.Duration) {
+	lc.Every("match tracker", interval, func(ctx context.Context) {
+		eventUpdate(ctx, interval, session)
+	})
 }
 
 func getAllianceFromTeams(teams []TeamDTO) TwoTeamAlliance {
@@ -0,0 +1,221 @@
+package bot
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+)
+
+// fakeEvent adds an event to fakeData that runs from start to end days from today
+func fakeEvent(t *testing.T, eventCode string, start, end int, matches []ftcscout.Match) {
+	t.Helper()
+	today := time.Now().UTC()
+	fakeData.events["2025 "+eventCode] = &ftcscout.Event{
+		Season:   2025,
+		Code:     eventCode,
+		Name:     eventCode + " Qualifier",
+		Venue:    "Del Norte High School",
+		Timezone: "UTC",
+		Start:    today.AddDate(0, 0, start).Format("2006-01-02"),
+		End:      today.AddDate(0, 0, end).Format("2006-01-02"),
+		Ongoing:  start <= 0 && end >= 0,
+	}
+	fakeData.matches["2025 "+eventCode] = matches
+}
+
+func fakeQual(id int, played bool) ftcscout.Match {
+	match := ftcscout.Match{
+		ID:              id,
+		MatchNumber:     id,
+		HasBeenPlayed:   played,
+		TournamentLevel: "Quals",
+		Teams: []ftcscout.MatchTeam{
+			{AllianceColor: "Red", TeamNumber: 16271},
+			{AllianceColor: "Red", TeamNumber: 11111},
+			{AllianceColor: "Blue", TeamNumber: 22222},
+			{AllianceColor: "Blue", TeamNumber: 33333},
+		},
+	}
+	if played {
+		match.Scores = ftcscout.MatchScores{
+			Red:  ftcscout.AllianceScore{Total: 120, Auto: 30, TeleOp: 80, Fouls: 10},
+			Blue: ftcscout.AllianceScore{Total: 95, Auto: 25, TeleOp: 70},
+		}
+	}
+	return match
+}
+
+// trackedEvent returns the tracker's copy of the event, and stops tracking it when the test ends
+func trackedEvent(t *testing.T, eventCode string) (EventTracked, bool) {
+	t.Helper()
+	t.Cleanup(func() {
+		trackedMu.Lock()
+		defer trackedMu.Unlock()
+		eventsBeingTracked = slices.DeleteFunc(eventsBeingTracked, func(event EventTracked) bool {
+			return event.EventCode == eventCode
+		})
+	})
+
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+	for _, event := range eventsBeingTracked {
+		if event.EventCode == eventCode {
+			return event.clone(), true
+		}
+	}
+	return EventTracked{}, false
+}
+
+func sentContaining(session *sessiontest.Session, text string) bool {
+	for _, sent := range session.Sent() {
+		if strings.Contains(sent.Content, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchInteraction(guildID string) *discordgo.InteractionCreate {
+	return sessiontest.NewInteraction(guildID, "member", discordgo.ApplicationCommandInteractionData{Name: "match"})
+}
+
+func TestEventStartUpcomingEvent(t *testing.T) {
+	fakeEvent(t, "USSTARTQ1", 3, 3, []ftcscout.Match{fakeQual(1, false)})
+	session := sessiontest.New()
+
+	eventStart("channel", "start-guild", "2025", "USSTARTQ1", false, session, matchInteraction("start-guild"))
+
+	event, ok := trackedEvent(t, "USSTARTQ1")
+	if !ok {
+		t.Fatal("the event isn't being tracked")
+	}
+	if event.UpdateChannelId != "channel" || event.GuildId != "start-guild" || event.LastProcessedMatchId != -100 {
+		t.Errorf("tracked %+v", event)
+	}
+	if !sentContaining(session, "Started tracking matches for event USSTARTQ1") {
+		t.Errorf("no start message in %+v", session.Sent())
+	}
+
+	scheduled := session.ScheduledEvents()
+	if len(scheduled) != 1 || scheduled[0].Name != "USSTARTQ1 Qualifier" || scheduled[0].EntityMetadata.Location != "Del Norte High School" {
+		t.Fatalf("scheduled events = %+v", scheduled)
+	}
+	if scheduled[0].ScheduledStartTime.Hour() != 8 || scheduled[0].ScheduledEndTime.Hour() != 17 {
+		t.Errorf("scheduled %v to %v, want 8am to 5pm", scheduled[0].ScheduledStartTime, scheduled[0].ScheduledEndTime)
+	}
+
+	// starting it again in the same channel doesn't track it twice
+	eventStart("channel", "start-guild", "2025", "USSTARTQ1", false, session, matchInteraction("start-guild"))
+	if !sentContaining(session, "Already tracking event USSTARTQ1") {
+		t.Errorf("no already tracking message in %+v", session.Sent())
+	}
+}
+
+func TestEventStartSkipsPlayedMatches(t *testing.T) {
+	fakeEvent(t, "USSTARTQ2", -1, 1, []ftcscout.Match{fakeQual(1, true), fakeQual(2, true), fakeQual(3, false)})
+	if err := guildconfig.Update("announce-guild", func(cfg *guildconfig.Config) { cfg.AnnouncementChannelId = "announcements" }); err != nil {
+		t.Fatalf("guildconfig.Update: %v", err)
+	}
+	session := sessiontest.New()
+
+	eventStart("channel", "announce-guild", "2025", "USSTARTQ2", false, session, matchInteraction("announce-guild"))
+
+	event, ok := trackedEvent(t, "USSTARTQ2")
+	if !ok {
+		t.Fatal("the event isn't being tracked")
+	}
+	if event.LastProcessedMatchId != 2 || event.UpdateChannelId != "announcements" {
+		t.Errorf("tracking from match %d in %s, want 2 in announcements", event.LastProcessedMatchId, event.UpdateChannelId)
+	}
+	if !sentContaining(session, "skipping 2 already completed matches") || !sentContaining(session, "<#announcements>") {
+		t.Errorf("start message doesn't mention the skipped matches and channel: %+v", session.Sent())
+	}
+	if len(session.ScheduledEvents()) != 0 {
+		t.Error("created a scheduled event for an event that already started")
+	}
+}
+
+func TestEventStartEndedOrMissing(t *testing.T) {
+	fakeEvent(t, "USSTARTQ3", -3, -2, nil)
+	session := sessiontest.New()
+
+	eventStart("channel", "start-guild", "2025", "USSTARTQ3", false, session, matchInteraction("start-guild"))
+	if !strings.HasPrefix(lastContent(t, session), "This event has already ended!") {
+		t.Errorf("ended event got %q", lastContent(t, session))
+	}
+
+	eventStart("channel", "start-guild", "2025", "NOSUCHEVENT", false, session, matchInteraction("start-guild"))
+	if !strings.HasPrefix(lastContent(t, session), "that event does not exist!") {
+		t.Errorf("missing event got %q", lastContent(t, session))
+	}
+
+	for _, code := range []string{"USSTARTQ3", "NOSUCHEVENT"} {
+		if _, ok := trackedEvent(t, code); ok {
+			t.Errorf("%s is being tracked", code)
+		}
+	}
+}
+
+func TestGetMatchResult(t *testing.T) {
+	fakeEvent(t, "USMATCHQ1", 0, 0, []ftcscout.Match{fakeQual(1, true), fakeQual(2, false)})
+	session := sessiontest.New()
+
+	getMatch("channel", "2025", "USMATCHQ1", "1", nil, session, matchInteraction("match-guild"))
+
+	sent, _ := session.Last()
+	if sent.Kind != "edit" || len(sent.Embeds) != 1 {
+		t.Fatalf("sent %+v, want the deferred response edited with the result", sent)
+	}
+	embed := sent.Embeds[0]
+	if embed.Title != "USMATCHQ1 Qualification 1: Results" || embed.Color != 0xE02C44 {
+		t.Errorf("embed is %q colored %x, want a red win", embed.Title, embed.Color)
+	}
+	if !strings.Contains(embed.Fields[0].Value, "**120 points 🏆**") || !strings.Contains(embed.Fields[1].Value, "Fouls: **0**") {
+		t.Errorf("scores = %q / %q", embed.Fields[0].Value, embed.Fields[1].Value)
+	}
+
+	threads := session.Threads()
+	if len(threads) != 1 || threads[0].Name != "Qualification 1" {
+		t.Errorf("threads = %+v", threads)
+	}
+}
+
+func TestGetMatchFromTracker(t *testing.T) {
+	fakeEvent(t, "USMATCHQ2", 0, 0, []ftcscout.Match{fakeQual(1, true)})
+	session := sessiontest.New()
+	tracked := &EventTracked{Year: "2025", EventCode: "USMATCHQ2", GuildId: "match-guild", UpdateChannelId: "updates"}
+
+	getMatch("updates", "2025", "USMATCHQ2", "1", tracked, session, nil)
+
+	sent, _ := session.Last()
+	if sent.Kind != "channel" || sent.ChannelID != "updates" || len(sent.Embeds) != 1 {
+		t.Fatalf("sent %+v, want the result posted in the updates channel", sent)
+	}
+	if len(session.Threads()) != 1 {
+		t.Errorf("started %d threads, want 1", len(session.Threads()))
+	}
+}
+
+func TestGetMatchMissing(t *testing.T) {
+	fakeEvent(t, "USMATCHQ3", 0, 0, []ftcscout.Match{fakeQual(1, true)})
+	session := sessiontest.New()
+
+	getMatch("channel", "2025", "USMATCHQ3", "99", nil, session, matchInteraction("match-guild"))
+	if !strings.HasPrefix(lastContent(t, session), "Couldn't find match 99 at 2025 USMATCHQ3.") {
+		t.Errorf("missing match got %q", lastContent(t, session))
+	}
+
+	getMatch("channel", "2025", "NOSUCHEVENT", "1", nil, session, matchInteraction("match-guild"))
+	if !strings.HasPrefix(lastContent(t, session), "Failed to fetch match data") {
+		t.Errorf("missing event got %q", lastContent(t, session))
+	}
+	if len(session.Threads()) != 0 {
+		t.Error("started a thread without posting a result")
+	}
+}
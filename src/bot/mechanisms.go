@@ -1,8 +1,13 @@
 package bot
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/logging"
 )
 
 func init() {
@@ -18,9 +23,28 @@ func init() {
 					Description: "Restart the bot.",
 					ChannelTypes: interactions.GUILDS_ONLY,
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "loglevel",
+					Description: "Show or change how much the bot logs.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "level",
+							Description: "The new log level, leave it out to see the current one.",
+							Required:    false,
+							Choices: []*discordgo.ApplicationCommandOptionChoice{
+								{Name: "Debug", Value: "debug"},
+								{Name: "Info", Value: "info"},
+								{Name: "Warn", Value: "warn"},
+								{Name: "Error", Value: "error"},
+							},
+						},
+					},
+				},
 			},
 		},
-		func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
 			data := i.ApplicationCommandData()
 			if len(data.Options) == 0 {
 				_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Content: "No subcommand provided.", Flags: 1 << 6}})
@@ -44,6 +68,8 @@ func init() {
 			switch subName {
 			case "restart":
 				restartBot(s, i.ChannelID, i)
+			case "loglevel":
+				setLogLevel(s, i, interactions.GetStringOption(sub.Options, "level"))
 			default:
 				interactions.SendMessage(s, i, "", "Unknown mech subcommand.")
 			}
@@ -51,7 +77,7 @@ func init() {
 	)
 }
 
-func mechcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
+func mechcmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
 	channelId := interactions.GetChannelId(message, i)
 	guildId, guildRetrieved := interactions.GetGuildId(message, i)
 	authorId, authorRetrieved := interactions.GetAuthorId(message, i)
@@ -71,7 +97,7 @@ func mechcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *di
 	switch subCommand {
 	case "restart":
 		if len(args) > 1 {
-			interactions.SendMessage(session, i, channelId, "Usage: `>>mech restart`")
+			interactions.SendMessage(session, i, channelId, fmt.Sprintf("Usage: `%smech restart`", guildconfig.Get(guildId).CommandPrefix))
 			return
 		}
 
@@ -92,8 +118,27 @@ func mechcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *di
 	}
 }
 
-func restartBot(session *discordgo.Session, channelID string, i *discordgo.InteractionCreate) {
+func restartBot(session interactions.Session, channelID string, i *discordgo.InteractionCreate) {
 	interactions.SendMessage(session, i, channelID, "Restarting bot...")
-	session.Close()
-	Deploy(inScopeToken)
+	requestRestart()
+}
+
+// setLogLevel changes the level for the whole bot, not just this server, so only the owner can do it. An empty level only shows it
+func setLogLevel(session interactions.Session, i *discordgo.InteractionCreate, level string) {
+	if level == "" {
+		interactions.SendMessage(session, i, "", fmt.Sprintf("Log level is `%s`.", strings.ToLower(logging.Level().String())))
+		return
+	}
+
+	if authorId, _ := interactions.GetAuthorId(nil, i); !isOwner(authorId) {
+		interactions.SendMessage(session, i, "", "Only the bot owner can change the log level.")
+		return
+	}
+
+	if err := logging.SetLevel(level); err != nil {
+		interactions.SendMessage(session, i, "", err.Error())
+		return
+	}
+	logging.WithInteraction(botLog, i).Info("Log level changed", "level", logging.Level().String())
+	interactions.SendMessage(session, i, "", fmt.Sprintf("Log level set to `%s`.", strings.ToLower(logging.Level().String())))
 }
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+	"github.com/shuban-789/bjorn/src/bot/logging"
+)
+
+func loglevelInteraction(userID string, level string) *discordgo.InteractionCreate {
+	sub := &discordgo.ApplicationCommandInteractionDataOption{Name: "loglevel", Type: discordgo.ApplicationCommandOptionSubCommand}
+	if level != "" {
+		sub.Options = stringOptions("level", level)
+	}
+	return sessiontest.NewInteraction("mech-guild", userID, discordgo.ApplicationCommandInteractionData{
+		Name:    "mech",
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{sub},
+	})
+}
+
+func TestMechLoglevel(t *testing.T) {
+	t.Cleanup(func() { logging.SetLevel("") })
+	owner := OwnerId
+	OwnerId = "owner"
+	t.Cleanup(func() { OwnerId = owner })
+
+	session := sessiontest.New()
+	session.Roles["mech-guild"] = []*discordgo.Role{{ID: "admins", Name: "Admins", Permissions: discordgo.PermissionAdministrator}}
+	session.Members["mech-guild"] = []*discordgo.Member{
+		{User: &discordgo.User{ID: "owner"}, Roles: []string{"admins"}},
+		{User: &discordgo.User{ID: "admin"}, Roles: []string{"admins"}},
+		{User: &discordgo.User{ID: "member"}},
+	}
+
+	interactions.CommandHandlers["mech"](session, loglevelInteraction("member", "debug"))
+	if logging.Level() != slog.LevelInfo {
+		t.Fatalf("a non-admin changed the level to %v", logging.Level())
+	}
+
+	interactions.CommandHandlers["mech"](session, loglevelInteraction("admin", "debug"))
+	if logging.Level() != slog.LevelInfo {
+		t.Fatalf("an admin who isn't the owner changed the level to %v", logging.Level())
+	}
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Only the bot owner can change the log level.") {
+		t.Errorf("admin got %q", got)
+	}
+
+	interactions.CommandHandlers["mech"](session, loglevelInteraction("owner", "debug"))
+	if logging.Level() != slog.LevelDebug {
+		t.Errorf("level = %v, want debug", logging.Level())
+	}
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Log level set to `debug`.") {
+		t.Errorf("set got %q", got)
+	}
+
+	interactions.CommandHandlers["mech"](session, loglevelInteraction("admin", ""))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Log level is `debug`.") {
+		t.Errorf("show got %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/metrics"
+	"github.com/shuban-789/bjorn/src/bot/util"
+)
+
+// MetricsAddr is where the metrics endpoint listens (e.g. ":9090"), set BJORN_METRICS_ADDR to turn it on.
+// The same listener serves /loglevel, which anyone who can reach it can read but only changes the level
+// with BJORN_LOG_TOKEN as a bearer token. Without the token the owner (BJORN_OWNER_ID) can use /mech loglevel instead.
+var (
+	MetricsAddr = os.Getenv("BJORN_METRICS_ADDR")
+	logToken    = os.Getenv("BJORN_LOG_TOKEN")
+)
+
+var (
+	commandInvocations  = metrics.NewCounter("bjorn_command_invocations_total", "Slash commands run, by command name.", "command")
+	commandDuration     = metrics.NewHistogram("bjorn_command_duration_seconds", "How long slash command handlers took, by command name.", metrics.DefaultBuckets, "command")
+	componentDispatches = metrics.NewCounter("bjorn_component_dispatch_total", "Button and select menu interactions, by handler (\"unknown\" if none matched).", "handler")
+	modalDispatches     = metrics.NewCounter("bjorn_modal_dispatch_total", "Modal submissions, by handler (\"unknown\" if none matched).", "handler")
+
+	ftcscoutRequests = metrics.NewCounter("bjorn_ftcscout_requests_total", "Requests made to FTCScout, by endpoint and status code (0 when there was no response).", "endpoint", "status")
+	ftcscoutDuration = metrics.NewHistogram("bjorn_ftcscout_request_duration_seconds", "How long FTCScout requests took, by endpoint.", metrics.DefaultBuckets, "endpoint")
+)
+
+func init() {
+	ftcscout.Default.Observe = func(path string, status int, elapsed time.Duration) {
+		endpoint := ftcscoutEndpoint(path)
+		ftcscoutRequests.Inc(endpoint, strconv.Itoa(status))
+		ftcscoutDuration.Observe(elapsed.Seconds(), endpoint)
+	}
+
+	caches := map[string]func() util.CacheStats{
+		"awardsCache":       awardsCache.Stats,
+		"leadCache":         leadCache.Stats,
+		"eventMatchesCache": eventMatchesCache.Stats,
+		"historyCache":      historyCache.Stats,
+		"eventDetailsCache": eventDetailsCache.Stats,
+	}
+	cacheSamples := func(value func(util.CacheStats) float64) func() []metrics.Sample {
+		return func() []metrics.Sample {
+			samples := []metrics.Sample{}
+			for name, stats := range caches {
+				samples = append(samples, metrics.Sample{LabelValues: []string{name}, Value: value(stats())})
+			}
+			return samples
+		}
+	}
+	metrics.NewCounterFunc("bjorn_cache_hits_total", "Cache lookups that were already cached, by cache.", []string{"cache"},
+		cacheSamples(func(stats util.CacheStats) float64 { return float64(stats.Hits) }))
+	metrics.NewCounterFunc("bjorn_cache_misses_total", "Cache lookups that had to be fetched, by cache.", []string{"cache"},
+		cacheSamples(func(stats util.CacheStats) float64 { return float64(stats.Misses) }))
+	metrics.NewGaugeFunc("bjorn_cache_size", "Values in each cache right now.", []string{"cache"},
+		cacheSamples(func(stats util.CacheStats) float64 { return float64(stats.Size) }))
+
+	metrics.NewGaugeFunc("bjorn_tracked_events", "Events the match tracker is following.", nil, func() []metrics.Sample {
+		trackedMu.Lock()
+		defer trackedMu.Unlock()
+		return []metrics.Sample{{Value: float64(len(eventsBeingTracked))}}
+	})
+	metrics.NewGaugeFunc("bjorn_tracked_event_seconds_since_last_poll", "Seconds since the tracker last fetched an event's matches successfully, -1 if it never has.",
+		[]string{"guild", "year", "event"}, func() []metrics.Sample {
+			trackedMu.Lock()
+			defer trackedMu.Unlock()
+
+			samples := []metrics.Sample{}
+			for _, event := range eventsBeingTracked {
+				since := -1.0
+				if !event.LastSuccessfulPoll.IsZero() {
+					since = time.Since(event.LastSuccessfulPoll).Seconds()
+				}
+				samples = append(samples, metrics.Sample{LabelValues: []string{event.GuildId, event.Year, event.EventCode}, Value: since})
+			}
+			return samples
+		})
+}
+
+// ftcscoutEndpoint keeps the endpoint label to a handful of values, "/teams/123/awards" -> "teams"
+func ftcscoutEndpoint(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	endpoint, _, _ := strings.Cut(path, "/")
+	endpoint, _, _ = strings.Cut(endpoint, "?")
+	return endpoint
+}
+
+// dispatchLabel keeps made up custom IDs out of the metrics
+func dispatchLabel(customID string, handled bool) string {
+	if !handled {
+		return "unknown"
+	}
+	return customID
+}
+
+// serveMetrics runs the metrics endpoint until ctx is cancelled or it fails, it's optional so failing only gets logged
+func serveMetrics(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/loglevel", logging.LevelHandler(logToken))
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	logger := logging.For("metrics")
+	logger.Info("Serving metrics", "addr", addr)
+	failed := make(chan error, 1)
+	go func() {
+		failed <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-failed:
+		logger.Error("Metrics endpoint stopped", "err", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Failed to stop metrics endpoint", "err", err)
+		}
+		if err := <-failed; err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Metrics endpoint stopped", "err", err)
+		}
+	}
+}
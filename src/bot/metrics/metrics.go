@@ -0,0 +1,265 @@
+// Package metrics keeps counters and histograms for the bot process and serves them in the Prometheus text format
+// (https://prometheus.io/docs/instrumenting/exposition_formats/), so event day can be watched on a dashboard.
+// It's deliberately small, just enough for what the bot records, instead of pulling in the whole client library.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are latency buckets in seconds, from fast cache hits up to the slowest API calls
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type metric interface {
+	name() string
+	write(w io.Writer)
+}
+
+var (
+	registered []metric
+	registryMu sync.Mutex
+)
+
+func register(m metric) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, existing := range registered {
+		if existing.name() == m.name() {
+			panic(fmt.Sprintf("metric %s is registered twice", m.name()))
+		}
+	}
+	registered = append(registered, m)
+}
+
+// series keys a set of label values, they're joined with a character that can't show up in a label value unescaped
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+func checkLabels(metricName string, labels, values []string) {
+	if len(labels) != len(values) {
+		panic(fmt.Sprintf("metric %s takes %d label values, got %d", metricName, len(labels), len(values)))
+	}
+}
+
+// Counter is a value that only goes up, one per set of label values
+type Counter struct {
+	metricName, help string
+	labels           []string
+
+	mu     sync.Mutex
+	values map[string]float64
+	series map[string][]string
+}
+
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{metricName: name, help: help, labels: labels, values: make(map[string]float64), series: make(map[string][]string)}
+	register(c)
+	return c
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *Counter) Add(value float64, labelValues ...string) {
+	checkLabels(c.metricName, c.labels, labelValues)
+	key := seriesKey(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.series[key]; !ok {
+		c.series[key] = append([]string(nil), labelValues...)
+	}
+	c.values[key] += value
+}
+
+func (c *Counter) name() string { return c.metricName }
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeHeader(w, c.metricName, c.help, "counter")
+	for _, key := range sortedKeys(c.series) {
+		writeSample(w, c.metricName, c.labels, c.series[key], c.values[key])
+	}
+}
+
+// Histogram counts observations into buckets, one per set of label values
+type Histogram struct {
+	metricName, help string
+	labels           []string
+	buckets          []float64
+
+	mu     sync.Mutex
+	data   map[string]*histogramData
+	series map[string][]string
+}
+
+type histogramData struct {
+	// counts[i] is how many observations were <= buckets[i], not cumulative until it's written
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &Histogram{metricName: name, help: help, labels: labels, buckets: buckets, data: make(map[string]*histogramData), series: make(map[string][]string)}
+	register(h)
+	return h
+}
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	checkLabels(h.metricName, h.labels, labelValues)
+	key := seriesKey(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	data, ok := h.data[key]
+	if !ok {
+		data = &histogramData{counts: make([]uint64, len(h.buckets))}
+		h.data[key] = data
+		h.series[key] = append([]string(nil), labelValues...)
+	}
+	if idx := sort.SearchFloat64s(h.buckets, value); idx < len(h.buckets) {
+		data.counts[idx]++
+	}
+	data.count++
+	data.sum += value
+}
+
+func (h *Histogram) name() string { return h.metricName }
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	writeHeader(w, h.metricName, h.help, "histogram")
+	bucketLabels := append(append([]string(nil), h.labels...), "le")
+	for _, key := range sortedKeys(h.series) {
+		data := h.data[key]
+		labelValues := h.series[key]
+
+		var cumulative uint64
+		for idx, bound := range h.buckets {
+			cumulative += data.counts[idx]
+			writeSample(w, h.metricName+"_bucket", bucketLabels, append(append([]string(nil), labelValues...), formatValue(bound)), float64(cumulative))
+		}
+		writeSample(w, h.metricName+"_bucket", bucketLabels, append(append([]string(nil), labelValues...), "+Inf"), float64(data.count))
+		writeSample(w, h.metricName+"_sum", h.labels, labelValues, data.sum)
+		writeSample(w, h.metricName+"_count", h.labels, labelValues, float64(data.count))
+	}
+}
+
+// Sample is one value reported by a Func, LabelValues line up with the Func's labels
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// Func reads its values when scraped, for numbers the bot already keeps somewhere else (like cache sizes)
+type Func struct {
+	metricName, help, kind string
+	labels                 []string
+	collect                func() []Sample
+}
+
+// NewGaugeFunc registers a value that can go up and down
+func NewGaugeFunc(name, help string, labels []string, collect func() []Sample) *Func {
+	f := &Func{metricName: name, help: help, kind: "gauge", labels: labels, collect: collect}
+	register(f)
+	return f
+}
+
+// NewCounterFunc registers a value that only goes up but is counted somewhere else
+func NewCounterFunc(name, help string, labels []string, collect func() []Sample) *Func {
+	f := &Func{metricName: name, help: help, kind: "counter", labels: labels, collect: collect}
+	register(f)
+	return f
+}
+
+func (f *Func) name() string { return f.metricName }
+
+func (f *Func) write(w io.Writer) {
+	samples := f.collect()
+	sort.Slice(samples, func(a, b int) bool {
+		return seriesKey(samples[a].LabelValues) < seriesKey(samples[b].LabelValues)
+	})
+
+	writeHeader(w, f.metricName, f.help, f.kind)
+	for _, sample := range samples {
+		checkLabels(f.metricName, f.labels, sample.LabelValues)
+		writeSample(w, f.metricName, f.labels, sample.LabelValues, sample.Value)
+	}
+}
+
+func sortedKeys(series map[string][]string) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func writeSample(w io.Writer, name string, labels, labelValues []string, value float64) {
+	if len(labels) == 0 {
+		fmt.Fprintf(w, "%s %s\n", name, formatValue(value))
+		return
+	}
+
+	pairs := make([]string, len(labels))
+	for idx, label := range labels {
+		pairs[idx] = fmt.Sprintf(`%s="%s"`, label, labelEscaper.Replace(labelValues[idx]))
+	}
+	fmt.Fprintf(w, "%s{%s} %s\n", name, strings.Join(pairs, ","), formatValue(value))
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// WriteTo writes every registered metric in the Prometheus text format, sorted by name
+func WriteTo(w io.Writer) {
+	registryMu.Lock()
+	metrics := append([]metric(nil), registered...)
+	registryMu.Unlock()
+
+	sort.Slice(metrics, func(a, b int) bool { return metrics[a].name() < metrics[b].name() })
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+// Handler serves the metrics to a Prometheus scrape
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteTo(w)
+	})
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func written(m metric) string {
+	var buf bytes.Buffer
+	m.write(&buf)
+	return buf.String()
+}
+
+func TestCounter(t *testing.T) {
+	counter := NewCounter("test_requests_total", "Requests made.\nBy endpoint \\ status.", "endpoint", "status")
+	counter.Inc("teams", "200")
+	counter.Inc("teams", "200")
+	counter.Add(3, "events", "500")
+	counter.Inc("we\"ird\\", "line\nbreak")
+
+	want := `# HELP test_requests_total Requests made.\nBy endpoint \\ status.
+# TYPE test_requests_total counter
+test_requests_total{endpoint="events",status="500"} 3
+test_requests_total{endpoint="teams",status="200"} 2
+test_requests_total{endpoint="we\"ird\\",status="line\nbreak"} 1
+`
+	if got := written(counter); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGaugeFunc(t *testing.T) {
+	unlabeled := NewGaugeFunc("test_tracked", "Tracked things.", nil, func() []Sample {
+		return []Sample{{Value: 2.5}}
+	})
+	labeled := NewCounterFunc("test_cache_hits_total", "Cache hits.", []string{"cache"}, func() []Sample {
+		return []Sample{{LabelValues: []string{"b"}, Value: 7}, {LabelValues: []string{"a"}, Value: 1e21}}
+	})
+
+	want := `# HELP test_tracked Tracked things.
+# TYPE test_tracked gauge
+test_tracked 2.5
+`
+	if got := written(unlabeled); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	want = `# HELP test_cache_hits_total Cache hits.
+# TYPE test_cache_hits_total counter
+test_cache_hits_total{cache="a"} 1e+21
+test_cache_hits_total{cache="b"} 7
+`
+	if got := written(labeled); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	// buckets are sorted however they're passed in
+	histogram := NewHistogram("test_duration_seconds", "How long it took.", []float64{0.1, 1, 0.5}, "command")
+	for _, value := range []float64{0.0625, 0.25, 0.5, 2} {
+		histogram.Observe(value, "team")
+	}
+	histogram.Observe(1, "lead")
+
+	// 0.5 lands in the 0.5 bucket since le is inclusive, 2 only shows up in +Inf
+	want := `# HELP test_duration_seconds How long it took.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{command="lead",le="0.1"} 0
+test_duration_seconds_bucket{command="lead",le="0.5"} 0
+test_duration_seconds_bucket{command="lead",le="1"} 1
+test_duration_seconds_bucket{command="lead",le="+Inf"} 1
+test_duration_seconds_sum{command="lead"} 1
+test_duration_seconds_count{command="lead"} 1
+test_duration_seconds_bucket{command="team",le="0.1"} 1
+test_duration_seconds_bucket{command="team",le="0.5"} 3
+test_duration_seconds_bucket{command="team",le="1"} 3
+test_duration_seconds_bucket{command="team",le="+Inf"} 4
+test_duration_seconds_sum{command="team"} 2.8125
+test_duration_seconds_count{command="team"} 4
+`
+	if got := written(histogram); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHandlerSortsByName(t *testing.T) {
+	NewCounter("test_zz_total", "Last.").Inc()
+	NewCounter("test_aa_total", "First.").Inc()
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if got := recorder.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", got)
+	}
+	body := recorder.Body.String()
+	first, last := strings.Index(body, "test_aa_total 1\n"), strings.Index(body, "test_zz_total 1\n")
+	if first < 0 || last < 0 || first > last {
+		t.Errorf("metrics missing or out of order:\n%s", body)
+	}
+}
+
+func TestRegisteringTwicePanics(t *testing.T) {
+	NewCounter("test_twice_total", "Once.")
+	defer func() {
+		if recover() == nil {
+			t.Error("registering the same name twice should panic")
+		}
+	}()
+	NewCounter("test_twice_total", "Twice.")
+}
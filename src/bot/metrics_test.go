@@ -0,0 +1,29 @@
+package bot
+
+import "testing"
+
+func TestFtcscoutEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/teams/16271":                 "teams",
+		"/teams/16271/awards":          "teams",
+		"/events/2025/USCASDQ/matches": "events",
+		"/teams/search?limit=10":       "teams",
+		"/quick-stats?region=USCA":     "quick-stats",
+		"events":                       "events",
+		"/":                            "",
+	}
+	for path, want := range tests {
+		if got := ftcscoutEndpoint(path); got != want {
+			t.Errorf("ftcscoutEndpoint(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestDispatchLabel(t *testing.T) {
+	if got := dispatchLabel("lead-next", true); got != "lead-next" {
+		t.Errorf("handled ID got %q", got)
+	}
+	if got := dispatchLabel("made-up-by-a-client", false); got != "unknown" {
+		t.Errorf("unhandled ID got %q, want unknown", got)
+	}
+}
@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+)
+
+// postOnDeck warns teams with a role in the server that their match is coming up, once per match.
+// Matches up to the guild's OnDeckMatches away get an alert, so a match that was missed (e.g. the bot was
+// down) still gets one with however many matches away it is now. event is the tracker's copy, it's saved by the caller.
+func postOnDeck(session interactions.Session, event *EventTracked, timezone string, matches []ftcscout.Match) {
+	cfg := guildconfig.Get(event.GuildId)
+	lead := *cfg.OnDeckMatches
+	if lead <= 0 {
+		return
+	}
+
+	channelID := event.UpdateChannelId
+	if cfg.OnDeckChannelId != "" {
+		channelID = cfg.OnDeckChannelId
+	}
+
+	upcoming := upcomingMatches(matches)
+	for matchesAway, match := range upcoming {
+		if matchesAway > lead {
+			break
+		}
+		if match.ID <= event.LastOnDeckMatchId {
+			continue
+		}
+
+		teams := []int{}
+		for _, team := range match.Teams {
+			teams = append(teams, team.TeamNumber)
+		}
+		users, teamsWithRoles, err := getUsersToPing(session, event.GuildId, teams)
+		if err != nil {
+			eventLog(event).Error("Failed to get on deck pings", "match", match.ID, "err", err)
+			return
+		}
+
+		// only teams that are part of this server get alerts, everyone else is just noise
+		if len(teamsWithRoles) > 0 {
+			sendOnDeckAlert(session, channelID, match, matchesAway, timezone, teamsWithRoles, users)
+		}
+
+		event.LastOnDeckMatchId = match.ID
+	}
+}
+
+// upcomingMatches returns the matches that have teams but haven't been played, in the order they'll be played
+func upcomingMatches(matches []ftcscout.Match) []ftcscout.Match {
+	upcoming := []ftcscout.Match{}
+	for _, match := range matches {
+		if !match.HasBeenPlayed && len(match.Teams) > 0 {
+			upcoming = append(upcoming, match)
+		}
+	}
+	sortMatchesByID(upcoming)
+	return upcoming
+}
+
+// sortMatchesByID puts matches in the order they're played, ftcscout numbers playoff
+// matches after every qual so this works across both
+func sortMatchesByID(matches []ftcscout.Match) {
+	sort.Slice(matches, func(a, b int) bool {
+		return matches[a].ID < matches[b].ID
+	})
+}
+
+// parseMatchTime reads a match's start time. ftcscout sends UTC timestamps, ftc events sends
+// the event's local time with no zone, so those are read in the event's timezone.
+func parseMatchTime(value string, timezone string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", strings.SplitN(value, ".", 2)[0], location)
+	return t, err == nil
+}
+
+func sendOnDeckAlert(session interactions.Session, channelID string, match ftcscout.Match, matchesAway int, timezone string, teams []int, users map[string]bool) {
+	var alert strings.Builder
+	verb := "is"
+	if len(teams) > 1 {
+		verb = "are"
+	}
+
+	when := "up next"
+	if matchesAway == 1 {
+		when = "1 match away"
+	} else if matchesAway > 1 {
+		when = fmt.Sprintf("%d matches away", matchesAway)
+	}
+
+	alert.WriteString(fmt.Sprintf("🔔 **%s** %s on deck for %s, %s", formatTeamNumbers(teams), verb, getMatchName(match), when))
+	if start, ok := parseMatchTime(match.ScheduledStartTime, timezone); ok {
+		alert.WriteString(fmt.Sprintf(" (scheduled <t:%d:t>, <t:%d:R>)", start.Unix(), start.Unix()))
+	}
+	alert.WriteString(".")
+
+	userIDs := []string{}
+	for userID := range users {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+	if len(userIDs) > 0 {
+		alert.WriteString("\n")
+		for _, userID := range userIDs {
+			alert.WriteString(fmt.Sprintf("<@%s> ", userID))
+		}
+	}
+
+	// only the people we picked get pinged, even if someone puts a role mention in a team name
+	_, err := session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         strings.TrimSpace(alert.String()),
+		AllowedMentions: &discordgo.MessageAllowedMentions{Users: userIDs},
+	})
+	HandleErr(err)
+}
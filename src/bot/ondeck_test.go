@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+)
+
+// onDeckSession is a server with a role for 16271, a match pings role and one member who has both
+func onDeckSession(guildID string) *sessiontest.Session {
+	session := sessiontest.New()
+	session.Roles[guildID] = []*discordgo.Role{
+		{ID: "roboknights", Name: "16271 Roboknights"},
+		{ID: "pings", Name: "Match Pings"},
+	}
+	session.Members[guildID] = []*discordgo.Member{
+		{User: &discordgo.User{ID: "driver"}, Roles: []string{"roboknights", "pings"}},
+		{User: &discordgo.User{ID: "lurker"}, Roles: []string{"roboknights"}},
+	}
+	return session
+}
+
+// onDeckMatches is a schedule where Q1 has been played and Q2 through Q6 haven't
+func onDeckMatches() []ftcscout.Match {
+	matches := []ftcscout.Match{fakeQual(1, true)}
+	for id := 2; id <= 6; id++ {
+		matches = append(matches, fakeQual(id, false))
+	}
+	return matches
+}
+
+func TestPostOnDeck(t *testing.T) {
+	session := onDeckSession("ondeck-guild")
+	event := &EventTracked{GuildId: "ondeck-guild", UpdateChannelId: "updates", Year: "2025", EventCode: "USONDECK1"}
+
+	postOnDeck(session, event, "UTC", onDeckMatches())
+	sent := session.Sent()
+	if len(sent) != 4 {
+		t.Fatalf("sent %d alerts, want one for each of Q2 through Q5: %+v", len(sent), sent)
+	}
+	wantWhen := []string{"up next", "1 match away", "2 matches away", "3 matches away"}
+	for n, alert := range sent {
+		if alert.ChannelID != "updates" {
+			t.Errorf("alert %d went to %q", n, alert.ChannelID)
+		}
+		want := "🔔 **Team 16271** is on deck for " + getMatchName(fakeQual(n+2, false)) + ", " + wantWhen[n] + "."
+		if !strings.HasPrefix(alert.Content, want) {
+			t.Errorf("alert %d = %q, want it to start with %q", n, alert.Content, want)
+		}
+		if !strings.Contains(alert.Content, "<@driver>") || strings.Contains(alert.Content, "<@lurker>") {
+			t.Errorf("alert %d should ping only the member with match pings: %q", n, alert.Content)
+		}
+	}
+	if event.LastOnDeckMatchId != 5 {
+		t.Errorf("LastOnDeckMatchId = %d, want 5", event.LastOnDeckMatchId)
+	}
+
+	// the next poll only alerts for matches it hasn't already
+	postOnDeck(session, event, "UTC", onDeckMatches())
+	if len(session.Sent()) != 4 {
+		t.Errorf("alerted again: %+v", session.Sent()[4:])
+	}
+}
+
+func TestPostOnDeckSettings(t *testing.T) {
+	err := guildconfig.Update("ondeck-channel-guild", func(cfg *guildconfig.Config) {
+		onDeck := 1
+		cfg.OnDeckMatches = &onDeck
+		cfg.OnDeckChannelId = "pit"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := onDeckSession("ondeck-channel-guild")
+	event := &EventTracked{GuildId: "ondeck-channel-guild", UpdateChannelId: "updates"}
+	postOnDeck(session, event, "UTC", onDeckMatches())
+	sent := session.Sent()
+	if len(sent) != 2 || sent[0].ChannelID != "pit" || sent[1].ChannelID != "pit" {
+		t.Errorf("sent %+v, want Q2 and Q3 in the on deck channel", sent)
+	}
+
+	err = guildconfig.Update("ondeck-off-guild", func(cfg *guildconfig.Config) {
+		off := 0
+		cfg.OnDeckMatches = &off
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session = onDeckSession("ondeck-off-guild")
+	event = &EventTracked{GuildId: "ondeck-off-guild", UpdateChannelId: "updates"}
+	postOnDeck(session, event, "UTC", onDeckMatches())
+	if len(session.Sent()) != 0 || event.LastOnDeckMatchId != 0 {
+		t.Errorf("alerts are off but sent %+v", session.Sent())
+	}
+}
+
+func TestPostOnDeckErrors(t *testing.T) {
+	// without roles we can't tell who to ping, so try again next poll instead of skipping the match
+	session := onDeckSession("ondeck-error-guild")
+	session.Errors["GuildRoles"] = errors.New("missing access")
+	event := &EventTracked{GuildId: "ondeck-error-guild", UpdateChannelId: "updates"}
+	postOnDeck(session, event, "UTC", onDeckMatches())
+	if len(session.Sent()) != 0 || event.LastOnDeckMatchId != 0 {
+		t.Errorf("sent %+v and moved to match %d without roles", session.Sent(), event.LastOnDeckMatchId)
+	}
+
+	// teams that aren't part of the server don't get alerts, but the matches still count as handled
+	session = sessiontest.New()
+	event = &EventTracked{GuildId: "ondeck-stranger-guild", UpdateChannelId: "updates"}
+	postOnDeck(session, event, "UTC", onDeckMatches())
+	if len(session.Sent()) != 0 || event.LastOnDeckMatchId != 5 {
+		t.Errorf("sent %+v and stopped at match %d for a server with no team roles", session.Sent(), event.LastOnDeckMatchId)
+	}
+}
@@ -38,42 +38,45 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/logging"
 	"github.com/shuban-789/bjorn/src/bot/util"
 )
 
+var logger = logging.For("pagination")
+
 func (p *Paginator[T]) Register() {
 	id_prev, id_jump_button, id_next, id_jump_modal := p.GetAllComponentIds()
 
-	interactions.RegisterComponentHandler(id_prev, func(s *discordgo.Session, ic *discordgo.InteractionCreate, data []string) {
+	interactions.RegisterComponentHandler(id_prev, func(s interactions.Session, ic *discordgo.InteractionCreate, data []string) {
 		err := p.pageLeftRight(s, ic, data, -1)
 		if err != nil {
-			fmt.Print(util.Fail(err.Error()))
+			logging.WithInteraction(logger, ic).Error("Failed to turn page", "paginator", p.CustomIDPrefix, "err", err)
 		}
 	})
 
-	interactions.RegisterComponentHandler(id_jump_button, func(s *discordgo.Session, ic *discordgo.InteractionCreate, data []string) {
+	interactions.RegisterComponentHandler(id_jump_button, func(s interactions.Session, ic *discordgo.InteractionCreate, data []string) {
 		err := p.launchJumpModal(s, ic, data)
 		if err != nil {
-			fmt.Print(util.Fail(err.Error()))
+			logging.WithInteraction(logger, ic).Error("Failed to open jump to page modal", "paginator", p.CustomIDPrefix, "err", err)
 		}
 	})
 
-	interactions.RegisterModalHandler(id_jump_modal, func(s *discordgo.Session, i *discordgo.InteractionCreate, id_data []string, modal_data discordgo.ModalSubmitInteractionData) {
+	interactions.RegisterModalHandler(id_jump_modal, func(s interactions.Session, i *discordgo.InteractionCreate, id_data []string, modal_data discordgo.ModalSubmitInteractionData) {
 		err := p.handleJumpModalSubmit(s, i, id_data, modal_data)
 		if err != nil {
-			fmt.Print(util.Fail(err.Error()))
+			logging.WithInteraction(logger, i).Error("Failed to jump to page", "paginator", p.CustomIDPrefix, "err", err)
 		}
 	})
 
-	interactions.RegisterComponentHandler(id_next, func(s *discordgo.Session, ic *discordgo.InteractionCreate, data []string) {
+	interactions.RegisterComponentHandler(id_next, func(s interactions.Session, ic *discordgo.InteractionCreate, data []string) {
 		err := p.pageLeftRight(s, ic, data, 1)
 		if err != nil {
-			fmt.Print(util.Fail(err.Error()))
+			logging.WithInteraction(logger, ic).Error("Failed to turn page", "paginator", p.CustomIDPrefix, "err", err)
 		}
 	})
 }
 
-func (p *Paginator[T]) pageLeftRight(s *discordgo.Session, ic *discordgo.InteractionCreate, data []string, delta int) error {
+func (p *Paginator[T]) pageLeftRight(s interactions.Session, ic *discordgo.InteractionCreate, data []string, delta int) error {
 	state, err := p.GetStateFromCustomId(data)
 	if err != nil {
 		return err
@@ -84,7 +87,7 @@ func (p *Paginator[T]) pageLeftRight(s *discordgo.Session, ic *discordgo.Interac
 }
 
 
-func (p *Paginator[T]) handleJumpModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, id_data []string, modal_data discordgo.ModalSubmitInteractionData) error {
+func (p *Paginator[T]) handleJumpModalSubmit(s interactions.Session, i *discordgo.InteractionCreate, id_data []string, modal_data discordgo.ModalSubmitInteractionData) error {
 	state, err := p.GetStateFromCustomId(id_data)
 	if err != nil {
 		return err
@@ -118,7 +121,7 @@ func (p *Paginator[T]) prepareMessageContent(state PaginationState, embed *disco
 	return
 }
 
-func (p *Paginator[T]) Setup(session *discordgo.Session, i *discordgo.InteractionCreate, channelID string, extraData map[string]string, createParams ...any) error {
+func (p *Paginator[T]) Setup(session interactions.Session, i *discordgo.InteractionCreate, channelID string, extraData map[string]string, createParams ...any) error {
 	initialState := PaginationState{
 		CurrentPage: 0,
 		ExtraData:   extraData,
@@ -135,14 +138,14 @@ func (p *Paginator[T]) Setup(session *discordgo.Session, i *discordgo.Interactio
 		return fmt.Errorf("error getting initial page data: %v", err)
 	}
 
-	// if create is nil, default to update (used in lead command)
+	// if create is nil, default to update on an empty embed (used in lead command)
 	var embed *discordgo.MessageEmbed
 	if p.Create == nil {
 		if len(createParams) != 0 {
 			return fmt.Errorf("Paginator.Create is nil, but createParams were provided")
 		}
 
-		embed, err = p.Update(initialState, pageData, nil)
+		embed, err = p.Update(initialState, pageData, &discordgo.MessageEmbed{})
 	} else {
 		embed, err = p.Create(initialState, pageData, createParams...)
 	}
@@ -159,7 +162,7 @@ func (p *Paginator[T]) Setup(session *discordgo.Session, i *discordgo.Interactio
 // edits the message to reflect the new page
 // editMessage assumes that there's only one embed in the message
 // TODO: avoid panic if there are no embeds
-func (p *Paginator[T]) editMessage(s *discordgo.Session, i *discordgo.InteractionCreate, state PaginationState) (err error) {
+func (p *Paginator[T]) editMessage(s interactions.Session, i *discordgo.InteractionCreate, state PaginationState) (err error) {
 	data, err := p.GetPageData(state)
 	if err != nil {
 		return fmt.Errorf("error getting page data: %v", err)
@@ -181,7 +184,7 @@ func (p *Paginator[T]) editMessage(s *discordgo.Session, i *discordgo.Interactio
 	return
 }
 
-func (p *Paginator[T]) launchJumpModal(s *discordgo.Session, i *discordgo.InteractionCreate, data []string) error {
+func (p *Paginator[T]) launchJumpModal(s interactions.Session, i *discordgo.InteractionCreate, data []string) error {
 	state, err := p.GetStateFromCustomId(data)
 	if err != nil {
 		return err
@@ -211,7 +214,6 @@ func (p *Paginator[T]) launchJumpModal(s *discordgo.Session, i *discordgo.Intera
 	})
 
 	if err != nil {
-		fmt.Println(util.Fail("Error launching jump to page modal: %v", err))
 		interactions.SendEphemeralMessage(s, i, "Error displaying jump to page modal.")
 		return fmt.Errorf("error launching jump to page modal: %v", err)
 	}
@@ -15,14 +15,14 @@ func init() {
 			Name:        "ping",
 			Description: "Checks the bot's responsiveness.",
 		},
-		func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
 			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
 			pingcmd(s, nil, i)
 		},
 	)
 }
 
-func pingcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate) {
+func pingcmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate) {
 	target := "google.com"
 	pinger, err := ping.NewPinger(target)
 	HandleErr(err)
@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/analytics"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+)
+
+// createPredictionEmbed forecasts an unplayed match from the OPRs of the qualification matches played so far
+func createPredictionEmbed(eventCode string, matchName string, matches []ftcscout.Match, match ftcscout.Match) (*discordgo.MessageEmbed, error) {
+	var redTeams, blueTeams []TeamDTO
+	for _, team := range match.Teams {
+		if team.AllianceColor == "Red" {
+			redTeams = append(redTeams, team)
+		} else if team.AllianceColor == "Blue" {
+			blueTeams = append(blueTeams, team)
+		}
+	}
+	if len(redTeams) == 0 || len(blueTeams) == 0 {
+		return nil, errors.New("the teams playing in it aren't known yet")
+	}
+
+	model, err := analytics.Fit(matches, analytics.QualsOnly)
+	if errors.Is(err, analytics.ErrNoMatches) {
+		return nil, errors.New("no qualification matches have been played to predict it from")
+	}
+	if err != nil {
+		return nil, err
+	}
+	prediction := model.Predict(match)
+
+	useQualsTeamNaming := match.TournamentLevel == "Quals"
+	redAlliance := formatAllianceTeams(redTeams, useQualsTeamNaming) + "\n\n" + formatAlliancePrediction(prediction.Red) + "\n\u200B"
+	blueAlliance := formatAllianceTeams(blueTeams, useQualsTeamNaming) + "\n\n" + formatAlliancePrediction(prediction.Blue)
+
+	// lean the color towards whoever is favored, a coin flip stays gray
+	color := 0xE8E4EC
+	if prediction.Red.WinProbability >= 0.55 {
+		color = 0xE02C44
+	} else if prediction.Blue.WinProbability >= 0.55 {
+		color = 0x58ACEC
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("%s %s: Prediction", eventCode, matchName),
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Red Alliance  🔴\u200B",
+				Value:  redAlliance,
+				Inline: true,
+			},
+			{
+				Name:   "Blue Alliance  🔵\u200B",
+				Value:  blueAlliance,
+				Inline: true,
+			},
+			{
+				Name: "Win Probability",
+				Value: fmt.Sprintf("🔴 **%.0f%%** - **%.0f%%** 🔵",
+					prediction.Red.WinProbability*100, prediction.Blue.WinProbability*100),
+			},
+		},
+		Color: color,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Based on OPR from %d qualification matches played so far. This match hasn't been played yet.", prediction.Samples/2),
+		},
+	}
+
+	if unknown := append(append([]int{}, prediction.Red.UnknownTeams...), prediction.Blue.UnknownTeams...); len(unknown) > 0 {
+		teams := make([]string, 0, len(unknown))
+		for _, team := range unknown {
+			teams = append(teams, fmt.Sprint(team))
+		}
+		embed.Description = fmt.Sprintf("No results yet for %s, so they're counted as an average team.", strings.Join(teams, ", "))
+	}
+
+	return embed, nil
+}
+
+func formatAlliancePrediction(alliance analytics.AlliancePrediction) string {
+	return fmt.Sprintf(
+		"**~%d points**\n • Auto: **~%d**\n • TeleOp: **~%d**\n • Fouls: **~%d**",
+		roundPoints(alliance.Get(analytics.Total)),
+		roundPoints(alliance.Get(analytics.Auto)),
+		roundPoints(alliance.Get(analytics.TeleOp)),
+		roundPoints(alliance.Get(analytics.Fouls)),
+	)
+}
+
+// OPRs can come out slightly negative for components nobody scores much in, which reads weird as a score
+func roundPoints(points float64) int {
+	return int(math.Round(math.Max(points, 0)))
+}
+
+// postUpNext posts a prediction for the next match that has teams but hasn't been played,
+// once per match. event is the tracker's copy, it's saved by the caller.
+func postUpNext(session interactions.Session, event *EventTracked, matches []ftcscout.Match) {
+	var next *ftcscout.Match
+	for index := range matches {
+		match := &matches[index]
+		if match.HasBeenPlayed || len(match.Teams) == 0 {
+			continue
+		}
+		if next == nil || match.ID < next.ID {
+			next = match
+		}
+	}
+	if next == nil || next.ID == event.LastPredictedMatchId {
+		return
+	}
+
+	embed, err := createPredictionEmbed(event.EventCode, getMatchName(*next), matches, *next)
+	if err != nil {
+		// nothing to base it on yet, e.g. before the first match
+		eventLog(event).Debug("Not predicting next match", "match", next.ID, "err", err)
+		return
+	}
+	embed.Title = fmt.Sprintf("Up next: %s", embed.Title)
+
+	if _, err := session.ChannelMessageSendEmbed(event.UpdateChannelId, embed); HandleErr(err) {
+		return
+	}
+	event.LastPredictedMatchId = next.ID
+}
@@ -5,8 +5,8 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/logging"
 	"github.com/shuban-789/bjorn/src/bot/search"
-	"github.com/shuban-789/bjorn/src/bot/util"
 )
 
 func RegionAutocomplete(opts map[string]string, query string) []*discordgo.ApplicationCommandOptionChoice {
@@ -43,7 +43,7 @@ func EventAutocomplete(includeFinishedEvents bool) interactions.AutocompleteProv
 func TeamsAutocomplete(opts map[string]string, query string) []*discordgo.ApplicationCommandOptionChoice {
 	results, err := search.SearchTeamNames(query, 25, "All")
 	if err != nil {
-		fmt.Println(util.Fail("Error searching team names: %v", err))
+		logging.For("presets").Error("Error searching team names", "query", query, "err", err)
 		return nil
 	}
 
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+)
+
+// Chain tries each provider in order and returns the first successful result
+type Chain []Provider
+
+func (c Chain) Name() string {
+	names := make([]string, 0, len(c))
+	for _, p := range c {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+// try runs fn against every provider until one succeeds. IsNotFound is only true for the
+// returned error when every backend said not found, otherwise one being down would look like a 404.
+func try[T any](c Chain, fn func(p Provider) (T, error)) (T, error) {
+	var errs []error
+	allNotFound := true
+	for _, p := range c {
+		result, err := fn(p)
+		if err == nil {
+			return result, nil
+		}
+		allNotFound = allNotFound && IsNotFound(err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+	}
+
+	var zero T
+	if len(errs) == 0 {
+		return zero, errors.New("no data providers configured")
+	}
+	if allNotFound {
+		return zero, errors.Join(errs...)
+	}
+	return zero, errors.New(errors.Join(errs...).Error())
+}
+
+func (c Chain) Team(ctx context.Context, teamNumber string) (*ftcscout.Team, error) {
+	return try(c, func(p Provider) (*ftcscout.Team, error) { return p.Team(ctx, teamNumber) })
+}
+
+func (c Chain) TeamAwards(ctx context.Context, teamNumber string) ([]ftcscout.Award, error) {
+	return try(c, func(p Provider) ([]ftcscout.Award, error) { return p.TeamAwards(ctx, teamNumber) })
+}
+
+func (c Chain) TeamEvents(ctx context.Context, teamNumber, season string) ([]ftcscout.EventTeam, error) {
+	return try(c, func(p Provider) ([]ftcscout.EventTeam, error) { return p.TeamEvents(ctx, teamNumber, season) })
+}
+
+func (c Chain) Event(ctx context.Context, season, eventCode string) (*ftcscout.Event, error) {
+	return try(c, func(p Provider) (*ftcscout.Event, error) { return p.Event(ctx, season, eventCode) })
+}
+
+func (c Chain) EventMatches(ctx context.Context, season, eventCode string) ([]ftcscout.Match, error) {
+	return try(c, func(p Provider) ([]ftcscout.Match, error) { return p.EventMatches(ctx, season, eventCode) })
+}
+
+func (c Chain) EventRankings(ctx context.Context, season, eventCode string) ([]ftcscout.EventTeam, error) {
+	return try(c, func(p Provider) ([]ftcscout.EventTeam, error) { return p.EventRankings(ctx, season, eventCode) })
+}
+
+func (c Chain) EventAwards(ctx context.Context, season, eventCode string) ([]ftcscout.Award, error) {
+	return try(c, func(p Provider) ([]ftcscout.Award, error) { return p.EventAwards(ctx, season, eventCode) })
+}
+
+func (c Chain) EventAlliances(ctx context.Context, season, eventCode string) ([]ftcscout.EventAlliance, error) {
+	return try(c, func(p Provider) ([]ftcscout.EventAlliance, error) { return p.EventAlliances(ctx, season, eventCode) })
+}
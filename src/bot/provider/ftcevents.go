@@ -0,0 +1,328 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcevents"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+)
+
+// FTCEvents converts the official FTC Events API into the ftcscout shapes
+type FTCEvents struct {
+	Client *ftcevents.Client
+}
+
+func (f *FTCEvents) Name() string {
+	return FTCEventsName
+}
+
+func (f *FTCEvents) Team(ctx context.Context, teamNumber string) (*ftcscout.Team, error) {
+	team, err := f.Client.Team(ctx, CurrentSeason, teamNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ftcscout.Team{
+		Number:     team.TeamNumber,
+		Name:       team.NameShort,
+		SchoolName: team.SchoolName,
+		Sponsors:   splitSponsors(team.NameFull),
+		Country:    team.Country,
+		State:      team.StateProv,
+		City:       team.City,
+		RookieYear: team.RookieYear,
+		Website:    team.Website,
+	}, nil
+}
+
+// nameFull is "Sponsor A/Sponsor B&School", which is as close as the API gets to a sponsor list
+func splitSponsors(nameFull string) []string {
+	var sponsors []string
+	for _, part := range strings.FieldsFunc(nameFull, func(r rune) bool { return r == '/' || r == '&' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			sponsors = append(sponsors, part)
+		}
+	}
+	return sponsors
+}
+
+func (f *FTCEvents) TeamAwards(ctx context.Context, teamNumber string) ([]ftcscout.Award, error) {
+	awards, err := f.Client.TeamAwards(ctx, CurrentSeason, teamNumber)
+	if err != nil {
+		return nil, err
+	}
+	return convertAwards(CurrentSeason, awards), nil
+}
+
+// TeamEvents asks for the team's row of the rankings at each event, since the event list doesn't have results
+func (f *FTCEvents) TeamEvents(ctx context.Context, teamNumber, season string) ([]ftcscout.EventTeam, error) {
+	events, err := f.Client.TeamEvents(ctx, season, teamNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ftcscout.EventTeam, 0, len(events))
+	for _, event := range events {
+		entry := ftcscout.EventTeam{
+			Season:     atoiOrZero(season),
+			EventCode:  event.Code,
+			TeamNumber: atoiOrZero(teamNumber),
+		}
+		rankings, err := f.Client.TeamRanking(ctx, season, event.Code, teamNumber)
+		if err != nil && !IsNotFound(err) {
+			return nil, err
+		}
+		if len(rankings) > 0 {
+			entry.TeamName = rankings[0].TeamName
+			entry.Stats = rankingStats(rankings[0])
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+func (f *FTCEvents) EventAwards(ctx context.Context, season, eventCode string) ([]ftcscout.Award, error) {
+	awards, err := f.Client.EventAwards(ctx, season, eventCode)
+	if err != nil {
+		return nil, err
+	}
+	return convertAwards(season, awards), nil
+}
+
+func convertAwards(season string, awards []ftcevents.Award) []ftcscout.Award {
+	result := make([]ftcscout.Award, 0, len(awards))
+	for _, award := range awards {
+		result = append(result, ftcscout.Award{
+			Season:     atoiOrZero(season),
+			EventCode:  award.EventCode,
+			TeamNumber: award.TeamNumber,
+			Type:       award.Name,
+			Placement:  award.Series,
+			PersonName: award.PersonName,
+		})
+	}
+	return result
+}
+
+func (f *FTCEvents) Event(ctx context.Context, season, eventCode string) (*ftcscout.Event, error) {
+	event, err := f.Client.Event(ctx, season, eventCode)
+	if err != nil {
+		return nil, err
+	}
+
+	start := dateOnly(event.DateStart)
+	end := dateOnly(event.DateEnd)
+	return &ftcscout.Event{
+		Season:        atoiOrZero(season),
+		Code:          event.Code,
+		DivisionCode:  event.DivisionCode,
+		Name:          event.Name,
+		Published:     event.Published,
+		Type:          event.TypeName,
+		RegionCode:    event.RegionCode,
+		LeagueCode:    event.LeagueCode,
+		DistrictCode:  event.DistrictCode,
+		Venue:         event.Venue,
+		Address:       event.Address,
+		Country:       event.Country,
+		State:         event.StateProv,
+		City:          event.City,
+		Website:       event.Website,
+		LiveStreamUrl: event.LiveStreamUrl,
+		Timezone:      event.Timezone,
+		Start:         start,
+		End:           end,
+		Ongoing:       isOngoing(start, end, event.Timezone),
+	}, nil
+}
+
+// the API gives "2025-01-11T00:00:00", everything else in the bot expects "2025-01-11"
+func dateOnly(date string) string {
+	if len(date) >= 10 {
+		return date[:10]
+	}
+	return date
+}
+
+// the API has no ongoing flag so it's worked out from the dates in the event's timezone
+func isOngoing(start, end, timezone string) bool {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	startDate, errStart := time.ParseInLocation("2006-01-02", start, location)
+	endDate, errEnd := time.ParseInLocation("2006-01-02", end, location)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	now := time.Now().In(location)
+	return !now.Before(startDate) && now.Before(endDate.AddDate(0, 0, 1))
+}
+
+func (f *FTCEvents) EventMatches(ctx context.Context, season, eventCode string) ([]ftcscout.Match, error) {
+	quals, err := f.Client.Matches(ctx, season, eventCode, "qual")
+	if err != nil {
+		return nil, err
+	}
+	playoffs, err := f.Client.Matches(ctx, season, eventCode, "playoff")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ftcscout.Match, 0, len(quals)+len(playoffs))
+	played := make(map[int]int)
+	for _, match := range append(quals, playoffs...) {
+		converted := convertMatch(match)
+		played[converted.ID] = len(result)
+		result = append(result, converted)
+	}
+
+	// results only have played matches, the schedule fills in start times and the matches still to come.
+	// it isn't published until the event starts, so a missing schedule just means nothing to add
+	for _, level := range []string{"qual", "playoff"} {
+		schedule, err := f.Client.Schedule(ctx, season, eventCode, level)
+		if err != nil {
+			continue
+		}
+		for _, match := range schedule {
+			converted := convertMatch(match)
+			if index, ok := played[converted.ID]; ok {
+				result[index].ScheduledStartTime = match.StartTime
+				result[index].Field = converted.Field
+				continue
+			}
+			converted.HasBeenPlayed = false
+			converted.ScheduledStartTime = match.StartTime
+			result = append(result, converted)
+		}
+	}
+	return result, nil
+}
+
+func convertMatch(match ftcevents.Match) ftcscout.Match {
+	converted := ftcscout.Match{
+		ID:              match.MatchNumber,
+		MatchNumber:     match.MatchNumber,
+		Field:           ftcscout.Field(match.Field),
+		HasBeenPlayed:   match.PostResultTime != "",
+		ActualStartTime: match.ActualStartTime,
+		TournamentLevel: "Quals",
+		Series:          match.Series,
+	}
+
+	// ftcscout numbers playoff matches as series*1000 + match so they never clash with quals
+	if match.TournamentLevel != "QUALIFICATION" {
+		converted.TournamentLevel = "DoubleElim"
+		converted.ID = match.Series*1000 + match.MatchNumber
+	}
+
+	if converted.HasBeenPlayed {
+		converted.Scores = ftcscout.MatchScores{
+			Red: ftcscout.AllianceScore{
+				Total:  match.ScoreRedFinal,
+				Auto:   match.ScoreRedAuto,
+				TeleOp: match.ScoreRedFinal - match.ScoreRedAuto - match.ScoreRedFoul,
+				Fouls:  match.ScoreRedFoul,
+			},
+			Blue: ftcscout.AllianceScore{
+				Total:  match.ScoreBlueFinal,
+				Auto:   match.ScoreBlueAuto,
+				TeleOp: match.ScoreBlueFinal - match.ScoreBlueAuto - match.ScoreBlueFoul,
+				Fouls:  match.ScoreBlueFoul,
+			},
+		}
+	}
+
+	for _, team := range match.Teams {
+		color, station := splitStation(team.Station)
+		converted.Teams = append(converted.Teams, ftcscout.MatchTeam{
+			AllianceColor: color,
+			AllianceRole:  stationRoles[station],
+			TeamNumber:    team.TeamNumber,
+		})
+	}
+	return converted
+}
+
+var stationRoles = map[string]string{
+	"1": "Captain",
+	"2": "FirstPick",
+	"3": "SecondPick",
+}
+
+// "Red1" -> "Red", "1"
+func splitStation(station string) (color string, number string) {
+	if rest, ok := strings.CutPrefix(station, "Red"); ok {
+		return "Red", rest
+	}
+	if rest, ok := strings.CutPrefix(station, "Blue"); ok {
+		return "Blue", rest
+	}
+	return station, ""
+}
+
+func (f *FTCEvents) EventRankings(ctx context.Context, season, eventCode string) ([]ftcscout.EventTeam, error) {
+	rankings, err := f.Client.Rankings(ctx, season, eventCode)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ftcscout.EventTeam, 0, len(rankings))
+	for _, ranking := range rankings {
+		result = append(result, ftcscout.EventTeam{
+			Season:     atoiOrZero(season),
+			EventCode:  eventCode,
+			TeamNumber: ranking.TeamNumber,
+			TeamName:   ranking.TeamName,
+			Stats:      rankingStats(ranking),
+		})
+	}
+	return result, nil
+}
+
+// the first two sort orders are ranking points and tiebreaker points
+func rankingStats(ranking ftcevents.Ranking) *ftcscout.EventTeamStats {
+	return &ftcscout.EventTeamStats{
+		Rank:          ranking.Rank,
+		RP:            ranking.SortOrder1,
+		TBP:           ranking.SortOrder2,
+		Wins:          ranking.Wins,
+		Losses:        ranking.Losses,
+		Ties:          ranking.Ties,
+		MatchesPlayed: ranking.MatchesPlayed,
+	}
+}
+
+func (f *FTCEvents) EventAlliances(ctx context.Context, season, eventCode string) ([]ftcscout.EventAlliance, error) {
+	alliances, err := f.Client.Alliances(ctx, season, eventCode)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ftcscout.EventAlliance, 0, len(alliances))
+	for _, alliance := range alliances {
+		// selection is still going while any alliance is missing its first pick
+		if alliance.Captain == 0 || alliance.Round1 == 0 {
+			return []ftcscout.EventAlliance{}, nil
+		}
+		result = append(result, ftcscout.EventAlliance{
+			Seed:       alliance.Number,
+			Captain:    alliance.Captain,
+			FirstPick:  alliance.Round1,
+			SecondPick: alliance.Round2,
+			Backup:     alliance.Backup,
+		})
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Seed < result[b].Seed })
+	return result, nil
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
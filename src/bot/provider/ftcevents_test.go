@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcevents"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+)
+
+// fakeFTCEvents serves recorded FTC Events responses from testdata/ftcevents, keyed by the request URI.
+// anything without a recording is a 404 like the real API gives for an unpublished schedule
+type fakeFTCEvents struct {
+	t      *testing.T
+	routes map[string]string
+}
+
+func newFakeFTCEvents(t *testing.T, routes map[string]string) *FTCEvents {
+	t.Helper()
+	fake := &fakeFTCEvents{t: t, routes: routes}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := ftcevents.NewClient(server.URL, "user", "token")
+	client.Backoff = time.Millisecond
+	client.Timeout = time.Second
+	return &FTCEvents{Client: client}
+}
+
+func (f *fakeFTCEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if username, token, ok := r.BasicAuth(); !ok || username != "user" || token != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	name, ok := f.routes[r.URL.RequestURI()]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := os.ReadFile(filepath.Join("testdata", "ftcevents", name))
+	if err != nil {
+		f.t.Errorf("reading recording %s: %v", name, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func TestFTCEventsTeam(t *testing.T) {
+	f := newFakeFTCEvents(t, map[string]string{
+		"/2025/teams?teamNumber=16271": "teams.json",
+	})
+
+	team, err := f.Team(context.Background(), "16271")
+	if err != nil {
+		t.Fatalf("Team: %v", err)
+	}
+	if team.Number != 16271 || team.Name != "Roboknights" || team.City != "San Diego" || team.RookieYear != 2019 {
+		t.Errorf("team = %+v", team)
+	}
+	want := []string{"Qualcomm", "Google", "Del Norte High School"}
+	if !slices.Equal(team.Sponsors, want) {
+		t.Errorf("sponsors = %q, want %q", team.Sponsors, want)
+	}
+}
+
+func TestFTCEventsTeamAwardsUsesQuery(t *testing.T) {
+	var gotPath, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.Query().Get("teamNumber")
+		body, _ := os.ReadFile(filepath.Join("testdata", "ftcevents", "awards_team.json"))
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	f := &FTCEvents{Client: ftcevents.NewClient(server.URL, "", "")}
+
+	awards, err := f.TeamAwards(context.Background(), "16271")
+	if err != nil {
+		t.Fatalf("TeamAwards: %v", err)
+	}
+	// the team number in the path would be read as an event code
+	if gotPath != "/2025/awards" || gotQuery != "16271" {
+		t.Errorf("requested %s?teamNumber=%s, want /2025/awards?teamNumber=16271", gotPath, gotQuery)
+	}
+	if len(awards) != 2 {
+		t.Fatalf("got %d awards, want 2", len(awards))
+	}
+	if awards[0].Type != "Inspire Award" || awards[0].EventCode != "USCASDQ1" || awards[0].Season != 2025 || awards[0].Placement != 1 {
+		t.Errorf("awards[0] = %+v", awards[0])
+	}
+}
+
+func TestFTCEventsTeamEvents(t *testing.T) {
+	f := newFakeFTCEvents(t, map[string]string{
+		"/2025/events?teamNumber=16271":            "events_team.json",
+		"/2025/rankings/USCASDQ1?teamNumber=16271": "rankings_team.json",
+		// the team hasn't played at USCASDQ2 yet, so there's no ranking for it
+	})
+
+	events, err := f.TeamEvents(context.Background(), "16271", "2025")
+	if err != nil {
+		t.Fatalf("TeamEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	played := events[0]
+	if played.EventCode != "USCASDQ1" || played.TeamNumber != 16271 || played.Season != 2025 {
+		t.Errorf("events[0] = %+v", played)
+	}
+	if played.Stats == nil || played.Stats.Rank != 1 || played.Stats.RP != 2 || played.Stats.Wins != 1 {
+		t.Errorf("events[0].Stats = %+v", played.Stats)
+	}
+	if events[1].EventCode != "USCASDQ2" || events[1].Stats != nil {
+		t.Errorf("events[1] = %+v, want USCASDQ2 without stats", events[1])
+	}
+}
+
+func TestFTCEventsEvent(t *testing.T) {
+	f := newFakeFTCEvents(t, map[string]string{
+		"/2025/events?eventCode=USCASDQ1": "events.json",
+	})
+
+	event, err := f.Event(context.Background(), "2025", "USCASDQ1")
+	if err != nil {
+		t.Fatalf("Event: %v", err)
+	}
+	if event.Start != "2025-01-11" || event.End != "2025-01-11" {
+		t.Errorf("dates = %q to %q, want 2025-01-11", event.Start, event.End)
+	}
+	if event.Name != "San Diego Qualifier 1" || event.Type != "Qualifier" || event.Timezone != "America/Los_Angeles" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Ongoing {
+		t.Error("an event from 2025-01-11 shouldn't be ongoing")
+	}
+}
+
+func TestFTCEventsEventNotFound(t *testing.T) {
+	f := newFakeFTCEvents(t, nil)
+
+	if _, err := f.Event(context.Background(), "2025", "NOPE"); !IsNotFound(err) {
+		t.Fatalf("err = %v, want not found", err)
+	}
+}
+
+func TestFTCEventsEventMatches(t *testing.T) {
+	f := newFakeFTCEvents(t, map[string]string{
+		"/2025/matches/USCASDQ1?tournamentLevel=qual":    "matches_qual.json",
+		"/2025/matches/USCASDQ1?tournamentLevel=playoff": "matches_playoff.json",
+		"/2025/schedule/USCASDQ1?tournamentLevel=qual":   "schedule_qual.json",
+		// the playoff schedule isn't recorded, it 404s and gets skipped
+	})
+
+	matches, err := f.EventMatches(context.Background(), "2025", "USCASDQ1")
+	if err != nil {
+		t.Fatalf("EventMatches: %v", err)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("got %d matches, want 3", len(matches))
+	}
+
+	qual := matches[0]
+	if qual.ID != 1 || qual.TournamentLevel != "Quals" || !qual.HasBeenPlayed {
+		t.Errorf("qual = %+v", qual)
+	}
+	if qual.ScheduledStartTime != "2025-01-11T10:00:00" || qual.Field != "1" {
+		t.Errorf("qual schedule = %q on field %q, want it filled in from the schedule", qual.ScheduledStartTime, qual.Field)
+	}
+	if qual.Scores.Red.Total != 120 || qual.Scores.Red.TeleOp != 80 || qual.Scores.Blue.Auto != 25 {
+		t.Errorf("qual scores = %+v", qual.Scores)
+	}
+	if len(qual.Teams) != 4 || qual.Teams[0] != (ftcscout.MatchTeam{AllianceColor: "Red", AllianceRole: "Captain", TeamNumber: 16271}) {
+		t.Errorf("qual teams = %+v", qual.Teams)
+	}
+
+	playoff := matches[1]
+	if playoff.ID != 1001 || playoff.TournamentLevel != "DoubleElim" || playoff.Series != 1 {
+		t.Errorf("playoff = %+v", playoff)
+	}
+	if playoff.Scores.Blue.Fouls != 15 || playoff.Scores.Blue.TeleOp != 90 {
+		t.Errorf("playoff scores = %+v", playoff.Scores)
+	}
+
+	upcoming := matches[2]
+	if upcoming.ID != 2 || upcoming.HasBeenPlayed || upcoming.ScheduledStartTime != "2025-01-11T10:07:00" {
+		t.Errorf("upcoming = %+v", upcoming)
+	}
+	if upcoming.Scores != (ftcscout.MatchScores{}) {
+		t.Errorf("an unplayed match has scores %+v", upcoming.Scores)
+	}
+}
+
+func TestFTCEventsEventRankings(t *testing.T) {
+	f := newFakeFTCEvents(t, map[string]string{
+		"/2025/rankings/USCASDQ1": "rankings.json",
+	})
+
+	rankings, err := f.EventRankings(context.Background(), "2025", "USCASDQ1")
+	if err != nil {
+		t.Fatalf("EventRankings: %v", err)
+	}
+	if len(rankings) != 2 {
+		t.Fatalf("got %d rankings, want 2", len(rankings))
+	}
+	first := rankings[0]
+	if first.TeamNumber != 16271 || first.EventCode != "USCASDQ1" || first.Season != 2025 {
+		t.Errorf("rankings[0] = %+v", first)
+	}
+	if first.Stats == nil || first.Stats.Rank != 1 || first.Stats.RP != 2 || first.Stats.TBP != 30 || first.Stats.Wins != 1 {
+		t.Errorf("rankings[0].Stats = %+v", first.Stats)
+	}
+}
+
+func TestFTCEventsEventAlliances(t *testing.T) {
+	f := newFakeFTCEvents(t, map[string]string{
+		"/2025/alliances/USCASDQ1": "alliances.json",
+		"/2025/alliances/USCASDQ2": "alliances_selecting.json",
+	})
+
+	alliances, err := f.EventAlliances(context.Background(), "2025", "USCASDQ1")
+	if err != nil {
+		t.Fatalf("EventAlliances: %v", err)
+	}
+	want := []ftcscout.EventAlliance{
+		{Seed: 1, Captain: 16271, FirstPick: 11111},
+		{Seed: 2, Captain: 22222, FirstPick: 44444},
+	}
+	if !slices.Equal(alliances, want) {
+		t.Errorf("alliances = %+v, want %+v", alliances, want)
+	}
+
+	// alliance 2 hasn't picked yet, so selection isn't over
+	alliances, err = f.EventAlliances(context.Background(), "2025", "USCASDQ2")
+	if err != nil {
+		t.Fatalf("EventAlliances during selection: %v", err)
+	}
+	if len(alliances) != 0 {
+		t.Errorf("alliances during selection = %+v, want none", alliances)
+	}
+}
+
+func TestFTCEventsSendsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	f := &FTCEvents{Client: ftcevents.NewClient(server.URL, "wrong", "creds")}
+
+	if _, err := f.EventRankings(context.Background(), "2025", "USCASDQ1"); err == nil {
+		t.Fatal("expected an error for rejected credentials")
+	}
+
+	ok := newFakeFTCEvents(t, map[string]string{"/2025/rankings/USCASDQ1": "rankings.json"})
+	if _, err := ok.EventRankings(context.Background(), "2025", "USCASDQ1"); err != nil {
+		t.Fatalf("EventRankings with the right credentials: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/shuban-789/bjorn/src/bot/bracket"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+)
+
+// FTCScout is a thin wrapper since its types are already the ones we use everywhere
+type FTCScout struct {
+	Client *ftcscout.Client
+}
+
+func (f *FTCScout) Name() string {
+	return FTCScoutName
+}
+
+func (f *FTCScout) Team(ctx context.Context, teamNumber string) (*ftcscout.Team, error) {
+	return f.Client.Team(ctx, teamNumber)
+}
+
+func (f *FTCScout) TeamAwards(ctx context.Context, teamNumber string) ([]ftcscout.Award, error) {
+	return f.Client.TeamAwards(ctx, teamNumber)
+}
+
+func (f *FTCScout) TeamEvents(ctx context.Context, teamNumber, season string) ([]ftcscout.EventTeam, error) {
+	return f.Client.TeamEvents(ctx, teamNumber, season)
+}
+
+func (f *FTCScout) Event(ctx context.Context, season, eventCode string) (*ftcscout.Event, error) {
+	return f.Client.Event(ctx, season, eventCode)
+}
+
+func (f *FTCScout) EventMatches(ctx context.Context, season, eventCode string) ([]ftcscout.Match, error) {
+	return f.Client.EventMatches(ctx, season, eventCode)
+}
+
+func (f *FTCScout) EventRankings(ctx context.Context, season, eventCode string) ([]ftcscout.EventTeam, error) {
+	return f.Client.EventTeams(ctx, season, eventCode)
+}
+
+func (f *FTCScout) EventAwards(ctx context.Context, season, eventCode string) ([]ftcscout.Award, error) {
+	return f.Client.EventAwards(ctx, season, eventCode)
+}
+
+// FTCScout doesn't have alliances, but the whole first round of the playoffs gets scheduled once
+// selection is over and every alliance plays in it, so they're read off those matches
+func (f *FTCScout) EventAlliances(ctx context.Context, season, eventCode string) ([]ftcscout.EventAlliance, error) {
+	matches, err := f.Client.EventMatches(ctx, season, eventCode)
+	if err != nil {
+		return nil, err
+	}
+	return alliancesFromMatches(matches), nil
+}
+
+func alliancesFromMatches(matches []ftcscout.Match) []ftcscout.EventAlliance {
+	alliances := make(map[int]*ftcscout.EventAlliance)
+	// the captain of each color in the first game of every series
+	seriesCaptains := make(map[int]map[string]int)
+	// sorted on a copy, the matches can be the ones in a shared cache
+	matches = slices.Clone(matches)
+	sort.Slice(matches, func(a, b int) bool { return matches[a].ID < matches[b].ID })
+
+	for _, match := range matches {
+		if match.TournamentLevel != "DoubleElim" {
+			continue
+		}
+
+		captains := make(map[string]int)
+		for _, team := range match.Teams {
+			if team.AllianceRole == "Captain" {
+				captains[team.AllianceColor] = team.TeamNumber
+			}
+		}
+		for _, team := range match.Teams {
+			captain := captains[team.AllianceColor]
+			if captain == 0 {
+				continue
+			}
+			alliance, ok := alliances[captain]
+			if !ok {
+				alliance = &ftcscout.EventAlliance{Captain: captain}
+				alliances[captain] = alliance
+			}
+			switch team.AllianceRole {
+			case "FirstPick":
+				alliance.FirstPick = team.TeamNumber
+			case "SecondPick":
+				alliance.SecondPick = team.TeamNumber
+			}
+		}
+		if _, ok := seriesCaptains[match.Series]; !ok {
+			seriesCaptains[match.Series] = captains
+		}
+	}
+
+	// seeds come from the first round slots, which only line up once every alliance has shown up
+	format, err := bracket.ForAlliances(len(alliances))
+	if err != nil {
+		return []ftcscout.EventAlliance{}
+	}
+	for _, series := range format.InRound(bracket.Upper, 1) {
+		captains := seriesCaptains[series.Number]
+		for color, source := range map[string]bracket.Source{"Red": series.Red, "Blue": series.Blue} {
+			if alliance, ok := alliances[captains[color]]; ok {
+				alliance.Seed = source.Seed
+			}
+		}
+	}
+
+	result := make([]ftcscout.EventAlliance, 0, len(alliances))
+	for _, alliance := range alliances {
+		if alliance.Seed == 0 || alliance.FirstPick == 0 {
+			return []ftcscout.EventAlliance{}
+		}
+		result = append(result, *alliance)
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Seed < result[b].Seed })
+	return result
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+)
+
+func playoffMatch(id int, red, blue [2]int) ftcscout.Match {
+	return ftcscout.Match{
+		ID:              id,
+		TournamentLevel: "DoubleElim",
+		Series:          id / 1000,
+		Teams: []ftcscout.MatchTeam{
+			{AllianceColor: "Red", AllianceRole: "Captain", TeamNumber: red[0]},
+			{AllianceColor: "Red", AllianceRole: "FirstPick", TeamNumber: red[1]},
+			{AllianceColor: "Blue", AllianceRole: "Captain", TeamNumber: blue[0]},
+			{AllianceColor: "Blue", AllianceRole: "FirstPick", TeamNumber: blue[1]},
+		},
+	}
+}
+
+func TestAlliancesFromMatchesLeavesInputAlone(t *testing.T) {
+	// newest first, the way a cache could hold them
+	matches := []ftcscout.Match{
+		playoffMatch(3001, [2]int{44444, 45555}, [2]int{33333, 34444}),
+		playoffMatch(2001, [2]int{22222, 23333}, [2]int{33333, 34444}),
+		playoffMatch(1001, [2]int{16271, 11111}, [2]int{44444, 45555}),
+		{ID: 1, TournamentLevel: "Quals"},
+	}
+	before := slices.Clone(matches)
+
+	alliances := alliancesFromMatches(matches)
+
+	for idx := range matches {
+		if matches[idx].ID != before[idx].ID {
+			t.Fatalf("input was reordered: id %d at %d, want %d", matches[idx].ID, idx, before[idx].ID)
+		}
+	}
+	want := []ftcscout.EventAlliance{
+		{Seed: 1, Captain: 16271, FirstPick: 11111},
+		{Seed: 2, Captain: 22222, FirstPick: 23333},
+		{Seed: 3, Captain: 33333, FirstPick: 34444},
+		{Seed: 4, Captain: 44444, FirstPick: 45555},
+	}
+	if !slices.Equal(alliances, want) {
+		t.Errorf("alliances = %+v, want %+v", alliances, want)
+	}
+}
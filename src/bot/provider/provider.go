@@ -0,0 +1,122 @@
+// Package provider hides where the team, event, match, ranking and award data comes from, so that
+// commands keep working when one source (usually FTCScout) is down or lagging on event day.
+//
+// The results use the ftcscout types since that's what the rest of the bot was written against,
+// other backends convert into them.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcevents"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/logging"
+)
+
+type Provider interface {
+	Name() string
+	Team(ctx context.Context, teamNumber string) (*ftcscout.Team, error)
+	TeamAwards(ctx context.Context, teamNumber string) ([]ftcscout.Award, error)
+	// TeamEvents is every event a team went to in a season, Stats is nil where it hasn't played yet
+	TeamEvents(ctx context.Context, teamNumber, season string) ([]ftcscout.EventTeam, error)
+	Event(ctx context.Context, season, eventCode string) (*ftcscout.Event, error)
+	EventMatches(ctx context.Context, season, eventCode string) ([]ftcscout.Match, error)
+	EventRankings(ctx context.Context, season, eventCode string) ([]ftcscout.EventTeam, error)
+	EventAwards(ctx context.Context, season, eventCode string) ([]ftcscout.Award, error)
+	// EventAlliances is ordered by seed and empty until alliance selection is over
+	EventAlliances(ctx context.Context, season, eventCode string) ([]ftcscout.EventAlliance, error)
+}
+
+// ErrNotFound is matched by the not found errors of every backend
+var ErrNotFound = errors.New("not found")
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || ftcscout.IsNotFound(err) || errors.Is(err, ftcevents.ErrNotFound)
+}
+
+// the season used for lookups that don't take one, like team info
+var CurrentSeason = "2025"
+
+const (
+	FTCScoutName  = "ftcscout"
+	FTCEventsName = "ftcevents"
+)
+
+var providers = map[string]Provider{
+	FTCScoutName:  &FTCScout{Client: ftcscout.Default},
+	FTCEventsName: &FTCEvents{Client: ftcevents.Default},
+}
+
+// Names lists every backend that can be selected
+func Names() []string {
+	return []string{FTCScoutName, FTCEventsName}
+}
+
+// Register makes another backend selectable by its name, it has to happen before the bot starts
+func Register(p Provider) {
+	providers[p.Name()] = p
+}
+
+func Get(name string) (Provider, bool) {
+	p, ok := providers[name]
+	return p, ok
+}
+
+// Parse turns a comma separated list like "ftcscout,ftcevents" into a provider, where every
+// backend after the first is only used when the ones before it fail
+func Parse(spec string) (Provider, error) {
+	var chain Chain
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		p, ok := providers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown data provider %q (options: %s)", name, strings.Join(Names(), ", "))
+		}
+		chain = append(chain, p)
+	}
+
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no data provider given")
+	}
+	if len(chain) == 1 {
+		return chain[0], nil
+	}
+	return chain, nil
+}
+
+// current is used when a guild hasn't picked a provider, set BJORN_DATA_PROVIDER to change it at startup
+var current atomic.Value
+
+func init() {
+	spec := os.Getenv("BJORN_DATA_PROVIDER")
+	if spec == "" {
+		spec = FTCScoutName
+	}
+	p, err := Parse(spec)
+	if err != nil {
+		logging.For("provider").Warn("Invalid BJORN_DATA_PROVIDER, using ftcscout", "err", err)
+		p = providers[FTCScoutName]
+	}
+	SetDefault(p)
+}
+
+// providerHolder exists because atomic.Value needs every stored value to have the same concrete type
+type providerHolder struct {
+	Provider
+}
+
+func Default() Provider {
+	return current.Load().(providerHolder).Provider
+}
+
+func SetDefault(p Provider) {
+	current.Store(providerHolder{p})
+}
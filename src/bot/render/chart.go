@@ -0,0 +1,129 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+type BarSeries struct {
+	Name   string
+	Values []float64
+}
+
+// BarChart is a grouped bar chart, one group per category with a bar for each series
+type BarChart struct {
+	Title      string
+	Categories []string
+	Series     []BarSeries
+
+	// Best[category] is the index of the series to highlight in that category, -1 for none
+	Best []int
+}
+
+const (
+	chartWidth      = 800
+	chartHeight     = 420
+	chartMargin     = 20
+	chartTitleSpace = 40
+	chartLegend     = 30
+	chartAxisSpace  = 50
+)
+
+// Draw renders the chart in the given format and theme
+func (c BarChart) Draw(format Format, theme Theme, scale float64) (*bytes.Buffer, error) {
+	if len(c.Categories) == 0 || len(c.Series) == 0 {
+		return nil, fmt.Errorf("a chart needs at least one category and one series")
+	}
+
+	canvas, err := NewCanvas(format, chartWidth, chartHeight, scale)
+	if err != nil {
+		return nil, err
+	}
+	canvas.Fill(theme.Background)
+
+	title, titleSize := FitText(canvas, c.Title, 18, 11, chartWidth-2*chartMargin, true)
+	canvas.Text(chartWidth/2, chartMargin+16, title, titleSize, true, theme.Title, AnchorMiddle)
+
+	// legend, spread evenly across the top
+	legendY := float64(chartMargin + chartTitleSpace + 10)
+	legendWidth := float64(chartWidth-2*chartMargin) / float64(len(c.Series))
+	for i, series := range c.Series {
+		x := chartMargin + legendWidth*float64(i)
+		canvas.Rect(x, legendY-10, 12, 12, theme.SeriesColor(i), theme.SeriesColor(i), 0)
+		name, size := FitText(canvas, series.Name, 12, 8, legendWidth-24, false)
+		canvas.Text(x+18, legendY, name, size, false, theme.Text, AnchorStart)
+	}
+
+	plotLeft := float64(chartMargin + chartAxisSpace)
+	plotRight := float64(chartWidth - chartMargin)
+	plotTop := legendY + chartLegend
+	plotBottom := float64(chartHeight - chartMargin - 20)
+
+	maxValue := 0.0
+	for _, series := range c.Series {
+		for _, value := range series.Values {
+			maxValue = math.Max(maxValue, value)
+		}
+	}
+	maxValue = niceCeiling(maxValue)
+	y := func(value float64) float64 {
+		return plotBottom - (math.Max(value, 0)/maxValue)*(plotBottom-plotTop)
+	}
+
+	// gridlines every quarter
+	for step := 0; step <= 4; step++ {
+		value := maxValue * float64(step) / 4
+		canvas.Line(plotLeft, y(value), plotRight, y(value), theme.Box, 1)
+		canvas.Text(plotLeft-8, y(value)+4, formatChartValue(value), 11, false, theme.Muted, AnchorEnd)
+	}
+	canvas.Line(plotLeft, plotBottom, plotRight, plotBottom, theme.Line, 1.5)
+
+	groupWidth := (plotRight - plotLeft) / float64(len(c.Categories))
+	barWidth := math.Min(groupWidth*0.8/float64(len(c.Series)), 60)
+	for category, name := range c.Categories {
+		groupLeft := plotLeft + groupWidth*float64(category) + (groupWidth-barWidth*float64(len(c.Series)))/2
+		canvas.Text(plotLeft+groupWidth*(float64(category)+0.5), plotBottom+18, name, 13, true, theme.Text, AnchorMiddle)
+
+		for i, series := range c.Series {
+			if category >= len(series.Values) {
+				continue
+			}
+			value := series.Values[category]
+			x := groupLeft + barWidth*float64(i)
+			best := category < len(c.Best) && c.Best[category] == i
+
+			border, borderWidth := theme.SeriesColor(i), 0.0
+			labelColor := theme.Muted
+			if best {
+				border, borderWidth = theme.Title, 2.5
+				labelColor = theme.Title
+			}
+			canvas.Rect(x+2, y(value), barWidth-4, plotBottom-y(value), theme.SeriesColor(i), border, borderWidth)
+			canvas.Text(x+barWidth/2, y(value)-5, formatChartValue(value), 11, best, labelColor, AnchorMiddle)
+		}
+	}
+
+	return Encode(canvas)
+}
+
+// niceCeiling rounds up to 1, 2 or 5 times a power of ten so gridlines land on round numbers
+func niceCeiling(value float64) float64 {
+	if value <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(value)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		if value <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+func formatChartValue(value float64) string {
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
@@ -0,0 +1,85 @@
+package render
+
+import (
+	"image/color"
+	"image/png"
+	"io"
+
+	"github.com/fogleman/gg"
+)
+
+type pngCanvas struct {
+	ctx           *gg.Context
+	width, height int
+	scale         float64
+	faces         faceCache
+}
+
+func newPNGCanvas(width, height int, scale float64) *pngCanvas {
+	if scale <= 0 {
+		scale = 1
+	}
+	ctx := gg.NewContext(int(float64(width)*scale), int(float64(height)*scale))
+	// draw in logical pixels and let gg scale everything up
+	ctx.Scale(scale, scale)
+	return &pngCanvas{ctx: ctx, width: width, height: height, scale: scale, faces: make(faceCache)}
+}
+
+func (c *pngCanvas) Width() int  { return c.width }
+func (c *pngCanvas) Height() int { return c.height }
+
+func (c *pngCanvas) Fill(fill color.Color) {
+	c.ctx.SetColor(fill)
+	c.ctx.DrawRectangle(0, 0, float64(c.width), float64(c.height))
+	c.ctx.Fill()
+}
+
+func (c *pngCanvas) Rect(x, y, w, h float64, fill, border color.Color, borderWidth float64) {
+	c.ctx.DrawRoundedRectangle(x, y, w, h, 4)
+	c.ctx.SetColor(fill)
+	if borderWidth <= 0 {
+		c.ctx.Fill()
+		return
+	}
+	c.ctx.FillPreserve()
+	c.ctx.SetColor(border)
+	c.ctx.SetLineWidth(borderWidth)
+	c.ctx.Stroke()
+}
+
+func (c *pngCanvas) Line(x1, y1, x2, y2 float64, col color.Color, width float64) {
+	c.ctx.SetColor(col)
+	c.ctx.SetLineWidth(width)
+	c.ctx.SetLineCapSquare()
+	c.ctx.DrawLine(x1, y1, x2, y2)
+	c.ctx.Stroke()
+}
+
+func (c *pngCanvas) Text(x, y float64, text string, size float64, bold bool, col color.Color, anchor Anchor) {
+	// faces are made at the real pixel size then drawn with the scale undone so glyphs stay crisp
+	c.ctx.Push()
+	c.ctx.Scale(1/c.scale, 1/c.scale)
+	c.ctx.SetFontFace(c.faces.get(size*c.scale, bold))
+	c.ctx.SetColor(col)
+	c.ctx.DrawStringAnchored(text, x*c.scale, y*c.scale, anchorX(anchor), 0)
+	c.ctx.Pop()
+}
+
+func (c *pngCanvas) MeasureText(text string, size float64, bold bool) float64 {
+	return c.faces.measure(text, size, bold)
+}
+
+func (c *pngCanvas) Encode(w io.Writer) error {
+	return png.Encode(w, c.ctx.Image())
+}
+
+func anchorX(anchor Anchor) float64 {
+	switch anchor {
+	case AnchorMiddle:
+		return 0.5
+	case AnchorEnd:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,157 @@
+// Package render draws images through a Canvas so the same drawing code can output
+// a scalable PNG (fogleman/gg with the Go fonts) or an SVG (ajstarks/svgo).
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+)
+
+type Format string
+
+const (
+	PNG Format = "png"
+	SVG Format = "svg"
+)
+
+var Formats = []Format{PNG, SVG}
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case PNG, "":
+		return PNG, nil
+	case SVG:
+		return SVG, nil
+	default:
+		return "", fmt.Errorf("unknown image format %q (options: png, svg)", s)
+	}
+}
+
+// Extension is the file extension including the dot
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+func (f Format) ContentType() string {
+	if f == SVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+type Anchor int
+
+const (
+	AnchorStart Anchor = iota
+	AnchorMiddle
+	AnchorEnd
+)
+
+// Canvas is something that can be drawn on in logical pixels, backends handle the actual resolution
+type Canvas interface {
+	Width() int
+	Height() int
+	Fill(fill color.Color)
+	// a border width of 0 means no border
+	Rect(x, y, w, h float64, fill, border color.Color, borderWidth float64)
+	Line(x1, y1, x2, y2 float64, col color.Color, width float64)
+	// y is the baseline
+	Text(x, y float64, text string, size float64, bold bool, col color.Color, anchor Anchor)
+	MeasureText(text string, size float64, bold bool) float64
+	Encode(w io.Writer) error
+}
+
+// NewCanvas makes a canvas of the given logical size. scale only matters for PNG, where it's how many
+// real pixels each logical pixel gets (2 looks sharp on phones).
+func NewCanvas(format Format, width, height int, scale float64) (Canvas, error) {
+	switch format {
+	case PNG:
+		return newPNGCanvas(width, height, scale), nil
+	case SVG:
+		return newSVGCanvas(width, height), nil
+	default:
+		return nil, fmt.Errorf("unknown image format %q", format)
+	}
+}
+
+// Encode is a shortcut for encoding a canvas into a buffer to send as an attachment
+func Encode(canvas Canvas) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	if err := canvas.Encode(&buf); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// FitText shrinks text until it fits in maxWidth, and cuts it off with "..." if it's still too long at minSize
+func FitText(canvas Canvas, text string, size, minSize, maxWidth float64, bold bool) (string, float64) {
+	for ; size > minSize; size-- {
+		if canvas.MeasureText(text, size, bold) <= maxWidth {
+			return text, size
+		}
+	}
+	size = minSize
+	if canvas.MeasureText(text, size, bold) <= maxWidth {
+		return text, size
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && canvas.MeasureText(string(runes)+"...", size, bold) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "...", size
+}
+
+// the parsed fonts are read only so every render shares them, the faces made from them are not
+var (
+	regularFont = mustParseFont(goregular.TTF)
+	boldFont    = mustParseFont(gobold.TTF)
+)
+
+func mustParseFont(ttf []byte) *truetype.Font {
+	f, err := truetype.Parse(ttf)
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse embedded font: %v", err))
+	}
+	return f
+}
+
+// faceCache holds the faces one canvas uses, key is "<bold> <size>". truetype faces keep a glyph
+// cache that isn't safe to share between goroutines, so each render makes its own
+type faceCache map[string]font.Face
+
+func (faces faceCache) get(size float64, bold bool) font.Face {
+	key := fmt.Sprintf("%t %.2f", bold, size)
+	if f, ok := faces[key]; ok {
+		return f
+	}
+
+	ttf := regularFont
+	if bold {
+		ttf = boldFont
+	}
+	f := truetype.NewFace(ttf, &truetype.Options{Size: size, Hinting: font.HintingFull})
+	faces[key] = f
+	return f
+}
+
+// measure uses the real font metrics, the SVG backend uses it too since viewers render with the same font
+// family when it's installed and something close to it when not
+func (faces faceCache) measure(text string, size float64, bold bool) float64 {
+	width := font.MeasureString(faces.get(size, bold), text)
+	return float64(width) / float64(fixed.I(1))
+}
+
+func rgbaString(c color.Color) string {
+	r, g, b, a := c.RGBA()
+	return fmt.Sprintf("rgba(%d,%d,%d,%.3f)", r>>8, g>>8, b>>8, float64(a)/0xffff)
+}
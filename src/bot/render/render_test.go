@@ -0,0 +1,45 @@
+package render
+
+import (
+	"image/color"
+	"sync"
+	"testing"
+)
+
+// renders share the parsed fonts but not faces, run with -race to catch a face being shared again
+func TestConcurrentRenders(t *testing.T) {
+	var wg sync.WaitGroup
+	for idx := 0; idx < 8; idx++ {
+		format := Formats[idx%len(Formats)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			canvas, err := NewCanvas(format, 200, 100, 2)
+			if err != nil {
+				t.Errorf("NewCanvas(%s): %v", format, err)
+				return
+			}
+			for size := 10.0; size < 20; size++ {
+				text, _ := FitText(canvas, "Qualcomm/Google/Del Norte High School", size, 8, 150, size > 15)
+				canvas.Text(10, size*4, text, size, size > 15, color.Black, AnchorStart)
+			}
+			if _, err := Encode(canvas); err != nil {
+				t.Errorf("Encode(%s): %v", format, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMeasureText(t *testing.T) {
+	canvas, err := NewCanvas(PNG, 100, 100, 1)
+	if err != nil {
+		t.Fatalf("NewCanvas: %v", err)
+	}
+	short := canvas.MeasureText("16271", 12, false)
+	long := canvas.MeasureText("16271 Roboknights", 12, false)
+	bold := canvas.MeasureText("16271 Roboknights", 12, true)
+	if short <= 0 || long <= short || bold <= long {
+		t.Errorf("widths short=%v long=%v bold=%v, want them increasing", short, long, bold)
+	}
+}
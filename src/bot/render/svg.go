@@ -0,0 +1,70 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+
+	svg "github.com/ajstarks/svgo"
+)
+
+type svgCanvas struct {
+	buf           bytes.Buffer
+	svg           *svg.SVG
+	width, height int
+	faces         faceCache
+}
+
+func newSVGCanvas(width, height int) *svgCanvas {
+	c := &svgCanvas{width: width, height: height, faces: make(faceCache)}
+	c.svg = svg.New(&c.buf)
+	c.svg.Start(width, height, fmt.Sprintf(`viewBox="0 0 %d %d"`, width, height))
+	return c
+}
+
+func (c *svgCanvas) Width() int  { return c.width }
+func (c *svgCanvas) Height() int { return c.height }
+
+func (c *svgCanvas) Fill(fill color.Color) {
+	c.svg.Rect(0, 0, c.width, c.height, "fill:"+rgbaString(fill))
+}
+
+// svgo only takes ints, everything here is laid out on whole logical pixels anyway
+func (c *svgCanvas) Rect(x, y, w, h float64, fill, border color.Color, borderWidth float64) {
+	style := "fill:" + rgbaString(fill)
+	if borderWidth > 0 {
+		style += fmt.Sprintf(";stroke:%s;stroke-width:%g", rgbaString(border), borderWidth)
+	}
+	c.svg.Roundrect(int(x), int(y), int(w), int(h), 4, 4, style)
+}
+
+func (c *svgCanvas) Line(x1, y1, x2, y2 float64, col color.Color, width float64) {
+	c.svg.Line(int(x1), int(y1), int(x2), int(y2),
+		fmt.Sprintf("stroke:%s;stroke-width:%g;stroke-linecap:square", rgbaString(col), width))
+}
+
+func (c *svgCanvas) Text(x, y float64, text string, size float64, bold bool, col color.Color, anchor Anchor) {
+	style := fmt.Sprintf("font-family:'Go','Helvetica','Arial',sans-serif;font-size:%gpx;fill:%s", size, rgbaString(col))
+	if bold {
+		style += ";font-weight:bold"
+	}
+	switch anchor {
+	case AnchorMiddle:
+		style += ";text-anchor:middle"
+	case AnchorEnd:
+		style += ";text-anchor:end"
+	}
+	// svgo escapes the text
+	c.svg.Text(int(x), int(y), text, style)
+}
+
+func (c *svgCanvas) MeasureText(text string, size float64, bold bool) float64 {
+	return c.faces.measure(text, size, bold)
+}
+
+func (c *svgCanvas) Encode(w io.Writer) error {
+	c.svg.End()
+	_, err := w.Write(c.buf.Bytes())
+	return err
+}
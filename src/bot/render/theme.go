@@ -0,0 +1,101 @@
+package render
+
+import (
+	"image/color"
+	"sort"
+)
+
+// Theme is the set of colors a drawing uses, so the same drawing code can do light and dark versions
+type Theme struct {
+	Name       string
+	Background color.RGBA
+	Line       color.RGBA
+	Box        color.RGBA
+	BoxBorder  color.RGBA
+	Text       color.RGBA
+	Muted      color.RGBA
+	Title      color.RGBA
+	Red        color.RGBA
+	Blue       color.RGBA
+	Winner     color.RGBA
+	Loser      color.RGBA
+	Highlight  color.RGBA
+
+	// colors for telling series apart in charts, in order
+	Palette []color.RGBA
+}
+
+// matches discord's dark mode
+var Dark = Theme{
+	Name:       "dark",
+	Background: color.RGBA{32, 34, 37, 255},
+	Line:       color.RGBA{185, 187, 190, 255},
+	Box:        color.RGBA{64, 68, 75, 255},
+	BoxBorder:  color.RGBA{114, 137, 218, 255},
+	Text:       color.RGBA{255, 255, 255, 255},
+	Muted:      color.RGBA{185, 187, 190, 255},
+	Title:      color.RGBA{255, 215, 0, 255},
+	Red:        color.RGBA{255, 82, 82, 255},
+	Blue:       color.RGBA{85, 170, 255, 255},
+	Winner:     color.RGBA{67, 181, 129, 255},
+	Loser:      color.RGBA{240, 71, 71, 255},
+	Highlight:  color.RGBA{40, 100, 60, 255},
+	Palette: []color.RGBA{
+		{114, 207, 221, 255},
+		{255, 159, 67, 255},
+		{162, 155, 254, 255},
+		{85, 239, 196, 255},
+	},
+}
+
+var Light = Theme{
+	Name:       "light",
+	Background: color.RGBA{255, 255, 255, 255},
+	Line:       color.RGBA{116, 127, 141, 255},
+	Box:        color.RGBA{242, 243, 245, 255},
+	BoxBorder:  color.RGBA{88, 101, 242, 255},
+	Text:       color.RGBA{6, 6, 7, 255},
+	Muted:      color.RGBA{79, 86, 96, 255},
+	Title:      color.RGBA{176, 122, 0, 255},
+	Red:        color.RGBA{210, 35, 42, 255},
+	Blue:       color.RGBA{0, 102, 204, 255},
+	Winner:     color.RGBA{36, 128, 70, 255},
+	Loser:      color.RGBA{196, 43, 43, 255},
+	Highlight:  color.RGBA{214, 240, 222, 255},
+	Palette: []color.RGBA{
+		{0, 130, 155, 255},
+		{214, 110, 0, 255},
+		{108, 92, 231, 255},
+		{0, 148, 115, 255},
+	},
+}
+
+var Themes = map[string]Theme{
+	Dark.Name:  Dark,
+	Light.Name: Light,
+}
+
+// DefaultTheme is used when nothing else picks one
+var DefaultTheme = Dark
+
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetTheme looks up a theme by name, falling back to the default for unknown or empty names
+func GetTheme(name string) Theme {
+	if theme, ok := Themes[name]; ok {
+		return theme
+	}
+	return DefaultTheme
+}
+
+// SeriesColor is the palette color for the nth series, wrapping around if there are more series than colors
+func (t Theme) SeriesColor(n int) color.RGBA {
+	return t.Palette[n%len(t.Palette)]
+}
@@ -1,18 +1,14 @@
 package bot
 
 import (
-	"bufio"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
 	"github.com/shuban-789/bjorn/src/bot/search"
-	"github.com/shuban-789/bjorn/src/bot/util"
 )
 
 func init() {
@@ -29,9 +25,15 @@ func init() {
 					Autocomplete: true,
 					ChannelTypes: interactions.GUILDS_ONLY,
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "member",
+					Description: "Give the role to someone else instead (admin only).",
+					Required:    false,
+				},
 			},
 		},
-		func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
 			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
 			data := i.ApplicationCommandData()
 			teamID := interactions.GetStringOption(data.Options, "team")
@@ -39,43 +41,42 @@ func init() {
 				interactions.SendMessage(s, i, "", "Please provide a team number.")
 				return
 			}
-			rolemeCmd(s, nil, i, []string{teamID})
+
+			args := []string{teamID}
+			// user options come through as the user's ID
+			if memberID := interactions.GetStringOption(data.Options, "member"); memberID != "" {
+				args = append(args, memberID)
+			}
+			rolemeCmd(s, nil, i, args)
 		},
 	)
 
-	// interactions.RegisterCommand(
-	// 	&discordgo.ApplicationCommand{
-	// 		Name:        "blacklist",
-	// 		Description: "Blacklists someone from using the role command (admin only).",
-	// 		Options: []*discordgo.ApplicationCommandOption{
-	// 			{
-	// 				Type:         discordgo.ApplicationCommandOptionUser,
-	// 				Name:         "user",
-	// 				Description:  "The user to blacklist.",
-	// 				Required:     true,
-	// 				Autocomplete: true,
-	// 				ChannelTypes: interactions.GUILDS_ONLY,
-	// 			},
-	// 		},
-	// 	},
-	// 	func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	// 		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
-	// 		data := i.ApplicationCommandData()
-	// 		user := interactions.GetUserOption(data.Options, "user")
-	// 		if user == "" {
-	// 			interactions.SendMessage(s, i, "", "Please provide a user to blacklist.")
-	// 			return
-	// 		}
-	// 	},
-	// )
-
 	// Team autocomplete for /roleme team
-	interactions.RegisterAutocomplete("roleme/team", func(opts map[string]string, query string) []*discordgo.ApplicationCommandOptionChoice {
-		results, err := search.SearchTeamNames(query, 25, "USCASD")
+	interactions.RegisterGuildAutocomplete("roleme/team", func(guildID string, opts map[string]string, query string) []*discordgo.ApplicationCommandOptionChoice {
+		results, err := search.SearchTeamNames(query, 25, guildconfig.Get(guildID).HomeRegion)
 		if err != nil {
-			fmt.Println(util.Fail("Error searching team names: %v", err))
+			commandLog.Error("Error searching team names", "query", query, "err", err)
 			return nil
 		}
+
+		// home region teams go first, then fill the rest of the list with teams from anywhere
+		if len(results) < 25 {
+			others, err := search.SearchTeamNames(query, 25, "All")
+			if err == nil {
+				seen := make(map[int]bool)
+				for _, team := range results {
+					seen[team.Number] = true
+				}
+				for _, team := range others {
+					if len(results) >= 25 {
+						break
+					}
+					if !seen[team.Number] {
+						results = append(results, team)
+					}
+				}
+			}
+		}
 		choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(results))
 		for _, team := range results {
 			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
@@ -87,14 +88,8 @@ func init() {
 	})
 }
 
-func hash(ID string) string {
-	hash := sha256.Sum256([]byte(ID))
-	hashString := hex.EncodeToString(hash[:])
-	return hashString
-}
-
 // func rolemeCmd(ChannelID string, args []string, session *discordgo.Session, guildId string, authorID string) {
-func rolemeCmd(session *discordgo.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
+func rolemeCmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
 	ChannelID := interactions.GetChannelId(message, i)
 	guildId, guildRetrieved := interactions.GetGuildId(message, i)
 	authorID, authorRetrieved := interactions.GetAuthorId(message, i)
@@ -104,40 +99,38 @@ func rolemeCmd(session *discordgo.Session, message *discordgo.MessageCreate, i *
 		return
 	}
 
-	if len(args) != 1 {
-		interactions.SendMessage(session, i, ChannelID, "Please provide a team number, and nothing more.")
+	if len(args) < 1 || len(args) > 2 {
+		interactions.SendMessage(session, i, ChannelID, "Please provide a team number, and optionally a member to give the role to (admins only).")
 		return
 	}
 
-	// shuban's blacklist code
-	blacklistFile, err := os.Open("src/bot/data/blacklist.txt")
-	if HandleErr(err) {
-		interactions.SendMessage(session, i, ChannelID, "Sorry, but I couldn't load the list of team names")
-		return
-	}
-	defer blacklistFile.Close()
-
-	blacklist := bufio.NewScanner(blacklistFile)
-	for blacklist.Scan() {
-		ban := blacklist.Text()
-		hashedID := hash(authorID)
-		if strings.Compare(ban, hashedID) == 0 {
-			interactions.SendMessage(session, i, ChannelID, "Sorry, but you are banned from using this command.")
-			return
+	// admins can hand out roles to other people, e.g. `>>roleme 12345 @someone`
+	targetID := authorID
+	if len(args) == 2 {
+		memberID := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(args[1], "<@"), "!"), ">")
+		if memberID != authorID {
+			hasPerms, err := isAdmin(session, guildId, authorID)
+			if err != nil {
+				interactions.SendMessage(session, i, ChannelID, "Unable to check permissions of user.")
+				return
+			}
+			if !hasPerms {
+				interactions.SendMessage(session, i, ChannelID, "Only admins can give team roles to other members.")
+				return
+			}
 		}
-	}
-
-	if err := blacklist.Err(); err != nil {
-		HandleErr(err)
-		interactions.SendMessage(session, i, ChannelID, "Sorry, but I couldn't read the list of team names")
-		return
+		targetID = memberID
 	}
 
 	teamNumber := args[0]
-	teamName, err := search.GetSDTeamNameFromNumber(teamNumber)
+	homeRegion := guildconfig.Get(guildId).HomeRegion
+	teamName, err := search.GetTeamNameFromNumber(teamNumber, homeRegion)
+	if err != nil && err.Error() == "team number not found" {
+		teamName, err = search.GetTeamNameFromAnyRegion(teamNumber)
+	}
 	if err != nil {
 		if err.Error() == "team number not found" {
-			interactions.SendMessage(session, i, ChannelID, "Sorry, but I couldn't find a team in San Diego with that ID competing in the DECODE:registered: season.")
+			interactions.SendMessage(session, i, ChannelID, "Sorry, but I couldn't find a team with that ID competing in the DECODE:registered: season.")
 		} else {
 			interactions.SendMessage(session, i, ChannelID, "Sorry, an error occurred while searching for your team: "+err.Error())
 		}
@@ -158,9 +151,12 @@ func rolemeCmd(session *discordgo.Session, message *discordgo.MessageCreate, i *
 		return
 	}
 
+	// already checked to be a number when looking up the team name
+	wantedTeamNumber, _ := strconv.Atoi(teamNumber)
+
 	var roleID string
 	for _, role := range roles {
-		if strings.HasPrefix(role.Name, teamNumber) {
+		if roleTeamNumber, ok := teamNumberFromRoleName(role.Name); ok && roleTeamNumber == wantedTeamNumber {
 			roleID = role.ID
 			roleName = role.Name
 			break
@@ -169,7 +165,7 @@ func rolemeCmd(session *discordgo.Session, message *discordgo.MessageCreate, i *
 
 	// if role doesn't exist
 	if roleID == "" {
-		roleName = teamNumber + " " + teamName
+		roleName = strconv.Itoa(wantedTeamNumber) + " " + teamName
 		color := 0x1ABC9C
 		hoist := false
 		mentionable := true
@@ -221,11 +217,34 @@ func rolemeCmd(session *discordgo.Session, message *discordgo.MessageCreate, i *
 	}
 
 	// add role to person
-	err = session.GuildMemberRoleAdd(guildId, authorID, roleID)
+	err = session.GuildMemberRoleAdd(guildId, targetID, roleID)
 	if HandleErr(err) {
-		interactions.SendMessage(session, i, ChannelID, "Sorry, but I couldn't assign the role to you.")
+		interactions.SendMessage(session, i, ChannelID, "Sorry, but I couldn't assign the role.")
 		return
 	}
 
+	if targetID != authorID {
+		interactions.SendMessage(session, i, ChannelID, fmt.Sprintf("<@%s> has been given the `%s` role!", targetID, roleName))
+		return
+	}
 	interactions.SendMessage(session, i, ChannelID, "You have been given the `"+roleName+"` role!")
 }
+
+// teamNumberFromRoleName reads the team number at the start of a team role like "12345 Foo",
+// so that team 123 doesn't match the role for 12345
+func teamNumberFromRoleName(roleName string) (int, bool) {
+	name := strings.TrimSpace(roleName)
+	end := 0
+	for end < len(name) && name[end] >= '0' && name[end] <= '9' {
+		end++
+	}
+	if end == 0 || (end < len(name) && name[end] != ' ') {
+		return 0, false
+	}
+
+	number, err := strconv.Atoi(name[:end])
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/blacklist"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+)
+
+func rolemeInteraction(guildID, userID string, options ...string) *discordgo.InteractionCreate {
+	return sessiontest.NewInteraction(guildID, userID, discordgo.ApplicationCommandInteractionData{
+		Name:    "roleme",
+		Options: stringOptions(options...),
+	})
+}
+
+// lastContent is the last message sent, messages can have a joke tacked onto the end so check the start of it
+func lastContent(t *testing.T, session *sessiontest.Session) string {
+	t.Helper()
+	sent, ok := session.Last()
+	if !ok {
+		t.Fatal("nothing was sent")
+	}
+	return sent.Content
+}
+
+func TestRolemeCreatesRole(t *testing.T) {
+	session := sessiontest.New()
+	session.Members["roles-guild"] = []*discordgo.Member{{User: &discordgo.User{ID: "member"}}}
+
+	interactions.CommandHandlers["roleme"](session, rolemeInteraction("roles-guild", "member", "team", "16271"))
+
+	if session.Deferred() != 1 {
+		t.Errorf("deferred %d times, want 1", session.Deferred())
+	}
+	roles := session.Roles["roles-guild"]
+	if len(roles) != 1 || roles[0].Name != "16271 Roboknights" {
+		t.Fatalf("roles = %+v, want a new 16271 Roboknights role", roles)
+	}
+	if adds := session.RoleAdds("roles-guild", "member"); !slices.Equal(adds, []string{roles[0].ID}) {
+		t.Errorf("role adds = %v, want [%s]", adds, roles[0].ID)
+	}
+
+	// the color question is answered in a follow up message
+	session.Dispatch(sessiontest.NewMessage("roles-guild", "channel", "member", "#ff0000"))
+	if roles[0].Color != 0xff0000 {
+		t.Errorf("role color = %x, want ff0000", roles[0].Color)
+	}
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Color set for the role.") {
+		t.Errorf("last message = %q", got)
+	}
+}
+
+func TestRolemeReusesExistingRole(t *testing.T) {
+	session := sessiontest.New()
+	session.Roles["roles-guild"] = []*discordgo.Role{
+		{ID: "other", Name: "162710 Not Them"},
+		{ID: "existing", Name: "16271 Roboknights"},
+	}
+
+	rolemeCmd(session, nil, rolemeInteraction("roles-guild", "member"), []string{"16271"})
+
+	if adds := session.RoleAdds("roles-guild", "member"); !slices.Equal(adds, []string{"existing"}) {
+		t.Errorf("role adds = %v, want [existing]", adds)
+	}
+	if len(session.Roles["roles-guild"]) != 2 {
+		t.Errorf("a role was created when one already existed")
+	}
+	if got := lastContent(t, session); !strings.HasPrefix(got, "You have been given the `16271 Roboknights` role!") {
+		t.Errorf("last message = %q", got)
+	}
+}
+
+func TestRolemeUnknownTeam(t *testing.T) {
+	session := sessiontest.New()
+
+	rolemeCmd(session, nil, rolemeInteraction("roles-guild", "member"), []string{"99999"})
+
+	if !strings.Contains(lastContent(t, session), "couldn't find a team") {
+		t.Errorf("last message = %q", lastContent(t, session))
+	}
+	if len(session.Roles["roles-guild"]) != 0 {
+		t.Error("a role was created for a team that doesn't exist")
+	}
+}
+
+func TestRolemeForSomeoneElseNeedsAdmin(t *testing.T) {
+	session := sessiontest.New()
+	session.Roles["admin-guild"] = []*discordgo.Role{
+		{ID: "admins", Name: "Admins", Permissions: discordgo.PermissionAdministrator},
+		{ID: "team", Name: "11111 Gearheads"},
+	}
+	session.Members["admin-guild"] = []*discordgo.Member{
+		{User: &discordgo.User{ID: "admin"}, Roles: []string{"admins"}},
+		{User: &discordgo.User{ID: "member"}},
+		{User: &discordgo.User{ID: "target"}},
+	}
+
+	rolemeCmd(session, nil, rolemeInteraction("admin-guild", "member"), []string{"11111", "<@target>"})
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Only admins can give team roles to other members.") {
+		t.Errorf("non-admin got %q", got)
+	}
+	if adds := session.RoleAdds("admin-guild", "target"); len(adds) != 0 {
+		t.Errorf("non-admin gave out roles %v", adds)
+	}
+
+	rolemeCmd(session, nil, rolemeInteraction("admin-guild", "admin"), []string{"11111", "<@!target>"})
+	if adds := session.RoleAdds("admin-guild", "target"); !slices.Equal(adds, []string{"team"}) {
+		t.Errorf("role adds = %v, want [team]", adds)
+	}
+	if got := lastContent(t, session); !strings.HasPrefix(got, "<@target> has been given the `11111 Gearheads` role!") {
+		t.Errorf("admin got %q", got)
+	}
+}
+
+func TestBlacklistedMemberIsStopped(t *testing.T) {
+	if err := blacklist.Add(blacklist.Ban{GuildId: "banned-guild", UserId: "banned", Reason: "spam"}); err != nil {
+		t.Fatalf("blacklist.Add: %v", err)
+	}
+	session := sessiontest.New()
+
+	interactionCreateHandler(session, rolemeInteraction("banned-guild", "banned", "team", "16271"))
+
+	sent := session.Sent()
+	if len(sent) != 1 || !sent[0].Ephemeral || !strings.Contains(sent[0].Content, "Reason: spam") {
+		t.Fatalf("sent %+v, want one ephemeral ban notice", sent)
+	}
+	if session.Deferred() != 0 || len(session.RoleAdds("banned-guild", "banned")) != 0 {
+		t.Error("the command ran for a blacklisted member")
+	}
+}
@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/pagination"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/search"
+	"github.com/shuban-789/bjorn/src/bot/util"
+)
+
+var (
+	schedulePaginator *pagination.Paginator[ftcscout.Match]
+
+	// keyed by "<provider> <year> <eventCode>", only needed for the timezone so it can stick around
+	eventDetailsCache = util.NewCache(
+		100,
+		time.Hour,
+		getEventDetails,
+	)
+)
+
+func init() {
+	schedulePaginator = pagination.New[ftcscout.Match]("event;schedule").
+		ItemsPerPage(8).
+		AddExtraKey("provider").
+		AddExtraKey("year").
+		AddExtraKey("eventCode").
+		AddExtraKey("team").
+		OnUpdate(updateScheduleEmbed).
+		WithDataGetter(func(state pagination.PaginationState) ([]ftcscout.Match, error) {
+			return fetchSchedule(state.ExtraData["provider"], state.ExtraData["year"], state.ExtraData["eventCode"], state.ExtraData["team"])
+		}).
+		Register()
+}
+
+func getEventDetails(key string) (search.EventData, error) {
+	splitted := strings.Fields(key)
+	p, err := provider.Parse(splitted[0])
+	if err != nil {
+		return search.EventData{}, err
+	}
+	return search.FetchEventDataFrom(p, splitted[1], splitted[2])
+}
+
+// fetchSchedule returns the qualification matches at an event in order, only the ones with team in them if it's set
+func fetchSchedule(providerName, year, eventCode, team string) ([]ftcscout.Match, error) {
+	matches, err := eventMatchesCache.GetOrFetch(fmt.Sprintf("%s %s %s", providerName, year, eventCode))
+	if err != nil {
+		return nil, err
+	}
+
+	teamNumber, _ := strconv.Atoi(team)
+	schedule := []ftcscout.Match{}
+	for _, match := range matches {
+		if match.TournamentLevel != "Quals" {
+			continue
+		}
+		if teamNumber != 0 && !matchHasTeam(match, teamNumber) {
+			continue
+		}
+		schedule = append(schedule, match)
+	}
+
+	if len(schedule) == 0 {
+		if teamNumber != 0 {
+			return nil, fmt.Errorf("team %d isn't in any qualification matches at %s", teamNumber, eventCode)
+		}
+		return nil, fmt.Errorf("the qualification schedule for %s hasn't been published yet", eventCode)
+	}
+
+	sortMatchesByID(schedule)
+	return schedule, nil
+}
+
+func matchHasTeam(match ftcscout.Match, teamNumber int) bool {
+	for _, team := range match.Teams {
+		if team.TeamNumber == teamNumber {
+			return true
+		}
+	}
+	return false
+}
+
+func updateScheduleEmbed(state pagination.PaginationState, matches []ftcscout.Match, embed *discordgo.MessageEmbed) (*discordgo.MessageEmbed, error) {
+	providerName, year, eventCode := state.ExtraData["provider"], state.ExtraData["year"], state.ExtraData["eventCode"]
+	team, _ := strconv.Atoi(state.ExtraData["team"])
+
+	// without the timezone, ftc events times can't be placed, they'll just show as unknown
+	timezone := ""
+	if details, err := eventDetailsCache.GetOrFetch(fmt.Sprintf("%s %s %s", providerName, year, eventCode)); err == nil {
+		timezone = details.Timezone
+	}
+
+	embed.Title = fmt.Sprintf("%s %s Qualification Schedule", year, eventCode)
+	if team != 0 {
+		embed.Title = fmt.Sprintf("%s %s Schedule for Team %d", year, eventCode, team)
+	}
+	embed.Description = "Times are shown in your timezone."
+	embed.Color = 0x72cfdd
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("Page %d of %d", state.CurrentPage+1, state.TotalPages),
+	}
+
+	embed.Fields = []*discordgo.MessageEmbedField{}
+	for _, match := range matches {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  formatScheduleHeading(match),
+			Value: formatScheduleEntry(match, timezone, team),
+		})
+	}
+	return embed, nil
+}
+
+func formatScheduleHeading(match ftcscout.Match) string {
+	heading := getMatchName(match)
+	if match.Field != "" {
+		heading += fmt.Sprintf(" · Field %s", match.Field)
+	}
+	if match.HasBeenPlayed {
+		heading += " ✅"
+	}
+	return heading
+}
+
+func formatScheduleEntry(match ftcscout.Match, timezone string, highlightTeam int) string {
+	var entry strings.Builder
+	if start, ok := parseMatchTime(match.ScheduledStartTime, timezone); ok {
+		entry.WriteString(fmt.Sprintf("<t:%d:t> (<t:%d:R>)\n", start.Unix(), start.Unix()))
+	} else {
+		entry.WriteString("Time not scheduled\n")
+	}
+
+	redTeams, blueTeams := splitAlliances(match)
+	alliance := func(teams []TeamDTO) string {
+		numbers := []string{}
+		for _, team := range teams {
+			if team.TeamNumber == highlightTeam {
+				numbers = append(numbers, fmt.Sprintf("**%d**", team.TeamNumber))
+			} else {
+				numbers = append(numbers, strconv.Itoa(team.TeamNumber))
+			}
+		}
+		return strings.Join(numbers, ", ")
+	}
+
+	entry.WriteString(fmt.Sprintf("🔴 %s\n🔵 %s", alliance(redTeams), alliance(blueTeams)))
+	if match.HasBeenPlayed {
+		entry.WriteString(fmt.Sprintf("\nFinal: %d - %d", match.Scores.Red.Total, match.Scores.Blue.Total))
+	}
+	return entry.String()
+}
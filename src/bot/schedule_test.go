@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+)
+
+func scheduleInteraction(options ...string) *discordgo.InteractionCreate {
+	return sessiontest.NewInteraction("schedule-guild", "user", discordgo.ApplicationCommandInteractionData{
+		Name: "event",
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+			Name:    "schedule",
+			Type:    discordgo.ApplicationCommandOptionSubCommand,
+			Options: stringOptions(options...),
+		}},
+	})
+}
+
+// scheduleEvent is Q1 played, Q2 scheduled for an hour from now with a different lineup, and a playoff match that shouldn't show up
+func scheduleEvent(t *testing.T, eventCode string) time.Time {
+	t.Helper()
+	start := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	second := fakeQual(2, false)
+	second.ScheduledStartTime = start.Format(time.RFC3339)
+	second.Teams = []ftcscout.MatchTeam{
+		{AllianceColor: "Red", TeamNumber: 44444},
+		{AllianceColor: "Red", TeamNumber: 55555},
+		{AllianceColor: "Blue", TeamNumber: 22222},
+		{AllianceColor: "Blue", TeamNumber: 66666},
+	}
+	playoff := fakeQual(3, false)
+	playoff.TournamentLevel = "DoubleElim"
+	fakeEvent(t, eventCode, 0, 1, []ftcscout.Match{second, fakeQual(1, true), playoff})
+	return start
+}
+
+func TestScheduleCommand(t *testing.T) {
+	start := scheduleEvent(t, "USSCHED1")
+	session := sessiontest.New()
+	interactions.CommandHandlers["event"](session, scheduleInteraction("event", "ussched1", "year", "2025"))
+
+	embeds := session.Embeds()
+	if len(embeds) != 1 {
+		t.Fatalf("sent %+v, want the schedule", session.Sent())
+	}
+	embed := embeds[0]
+	if embed.Title != "2025 USSCHED1 Qualification Schedule" {
+		t.Errorf("title = %q", embed.Title)
+	}
+	if len(embed.Fields) != 2 {
+		t.Fatalf("got %d fields, want Q1 and Q2 only", len(embed.Fields))
+	}
+	if embed.Fields[0].Name != "Qualification 1 ✅" || !strings.Contains(embed.Fields[0].Value, "Time not scheduled") || !strings.HasSuffix(embed.Fields[0].Value, "Final: 120 - 95") {
+		t.Errorf("Q1 = %q: %q", embed.Fields[0].Name, embed.Fields[0].Value)
+	}
+	wantTime := fmt.Sprintf("<t:%d:t> (<t:%d:R>)", start.Unix(), start.Unix())
+	if embed.Fields[1].Name != "Qualification 2" || !strings.HasPrefix(embed.Fields[1].Value, wantTime) || !strings.Contains(embed.Fields[1].Value, "🔴 44444, 55555\n🔵 22222, 66666") {
+		t.Errorf("Q2 = %q: %q", embed.Fields[1].Name, embed.Fields[1].Value)
+	}
+}
+
+func TestScheduleForTeam(t *testing.T) {
+	scheduleEvent(t, "USSCHED2")
+	session := sessiontest.New()
+	interactions.CommandHandlers["event"](session, scheduleInteraction("event", "USSCHED2", "year", "2025", "team", "16271"))
+
+	embeds := session.Embeds()
+	if len(embeds) != 1 {
+		t.Fatalf("sent %+v, want the schedule", session.Sent())
+	}
+	if embeds[0].Title != "2025 USSCHED2 Schedule for Team 16271" {
+		t.Errorf("title = %q", embeds[0].Title)
+	}
+	if len(embeds[0].Fields) != 1 || !strings.Contains(embeds[0].Fields[0].Value, "🔴 **16271**, 11111") {
+		t.Errorf("fields = %+v, want only Q1 with 16271 in bold", embeds[0].Fields)
+	}
+}
+
+func TestScheduleErrors(t *testing.T) {
+	scheduleEvent(t, "USSCHED3")
+	fakeEvent(t, "USSCHED4", 1, 2, []ftcscout.Match{})
+	session := sessiontest.New()
+	event := interactions.CommandHandlers["event"]
+
+	event(session, scheduleInteraction("event", "USNOSCHED", "year", "2025"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Error sending schedule: ") {
+		t.Errorf("unknown event got %q", got)
+	}
+	event(session, scheduleInteraction("event", "USSCHED4", "year", "2025"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Error sending schedule: ") || !strings.Contains(got, "the qualification schedule for USSCHED4 hasn't been published yet") {
+		t.Errorf("no matches got %q", got)
+	}
+	event(session, scheduleInteraction("event", "USSCHED3", "year", "2025", "team", "77777"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Error sending schedule: ") || !strings.Contains(got, "team 77777 isn't in any qualification matches at USSCHED3") {
+		t.Errorf("team not at the event got %q", got)
+	}
+	event(session, scheduleInteraction("event", "USSCHED3", "year", "2025", "team", "roboknights"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Please provide a valid team number.") {
+		t.Errorf("bad team got %q", got)
+	}
+
+	if err := guildconfig.Update("schedule-text-guild", func(cfg *guildconfig.Config) { cfg.CommandPrefix = "?" }); err != nil {
+		t.Fatal(err)
+	}
+	eventcmd(session, sessiontest.NewMessage("schedule-text-guild", "channel", "user", "?event schedule 2025"), nil, []string{"schedule", "2025"})
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Usage: `?event schedule <year> <eventCode> [team]`") {
+		t.Errorf("missing event got %q", got)
+	}
+}
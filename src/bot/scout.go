@@ -0,0 +1,375 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/pagination"
+	"github.com/shuban-789/bjorn/src/bot/presets"
+	"github.com/shuban-789/bjorn/src/bot/scouting"
+	"github.com/shuban-789/bjorn/src/bot/search"
+)
+
+const (
+	scoutNoteModalId = "scout;note"
+
+	scoutDrivetrainInputId  = "drivetrain"
+	scoutAutoInputId        = "auto"
+	scoutReliabilityInputId = "reliability"
+	scoutTextInputId        = "text"
+)
+
+var scoutPaginator *pagination.Paginator[scouting.Note]
+
+func init() {
+	interactions.RegisterCommand(
+		&discordgo.ApplicationCommand{
+			Name:        "scout",
+			Description: "Write and read this server's scouting notes.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "note",
+					Description: "Write notes on a team at an event.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "event",
+							Description: "Event code the team is at.",
+							Required:    true,
+						},
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "team",
+							Description:  "The FTC team number.",
+							Required:     true,
+							Autocomplete: true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
+							Choices:     interactions.FtcYearChoices,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "match",
+					Description: "Fill in this server's match scouting form for a team in a match.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "event",
+							Description: "Event code of the match.",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
+							Choices:     interactions.FtcYearChoices,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "form",
+					Description: "Set up the match scouting form.",
+					Options:     scoutFormOptions(),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "export",
+					Description: "Download each team's averages and maxima from match scouting as a CSV.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "event",
+							Description: "Event code to export.",
+							Required:    true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "year",
+							Description: "Year of the event (defaults to this server's season).",
+							Required:    false,
+							Choices:     interactions.FtcYearChoices,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "view",
+					Description: "Read this server's notes on a team.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "team",
+							Description:  "The FTC team number.",
+							Required:     true,
+							Autocomplete: true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "event",
+							Description: "Only show notes from this event.",
+							Required:    false,
+						},
+					},
+				},
+			},
+		},
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
+			data := i.ApplicationCommandData()
+			if len(data.Options) == 0 {
+				interactions.SendEphemeralMessage(s, i, "Please provide a subcommand for scout.")
+				return
+			}
+
+			sub := data.Options[0]
+			switch sub.Name {
+			case "note":
+				// the modal has to be the first response, so this one can't be deferred
+				launchScoutNoteModal(s, i, guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(sub.Options, "year")),
+					interactions.GetStringOption(sub.Options, "event"), interactions.GetStringOption(sub.Options, "team"))
+			case "match":
+				scoutMatch(s, i, guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(sub.Options, "year")),
+					strings.ToUpper(interactions.GetStringOption(sub.Options, "event")))
+			case "form":
+				handleScoutFormCommand(s, i, sub)
+			case "export":
+				_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
+				scoutcmd(s, nil, i, []string{"export", guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(sub.Options, "year")), interactions.GetStringOption(sub.Options, "event")})
+			case "view":
+				_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
+				scoutcmd(s, nil, i, []string{"view", interactions.GetStringOption(sub.Options, "team"), interactions.GetStringOption(sub.Options, "event")})
+			}
+		},
+	)
+
+	interactions.RegisterAutocomplete("scout/note/team", presets.TeamsAutocomplete)
+	interactions.RegisterAutocomplete("scout/view/team", presets.TeamsAutocomplete)
+	interactions.RegisterModalHandler(scoutNoteModalId, handleScoutNoteSubmit)
+
+	scoutPaginator = pagination.New[scouting.Note]("scout;view").
+		ItemsPerPage(3).
+		AddExtraKey("guild").
+		AddExtraKey("team").
+		AddExtraKey("eventCode").
+		OnUpdate(updateScoutNotesEmbed).
+		WithDataGetter(func(state pagination.PaginationState) ([]scouting.Note, error) {
+			team, _ := strconv.Atoi(state.ExtraData["team"])
+			notes := scouting.TeamNotes(state.ExtraData["guild"], team, state.ExtraData["eventCode"])
+			if len(notes) == 0 {
+				return nil, fmt.Errorf("no scouting notes on team %d yet, add some with /scout note", team)
+			}
+			return notes, nil
+		}).
+		Register()
+}
+
+func scoutcmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
+	channelID := interactions.GetChannelId(message, i)
+	guildID, inGuild := interactions.GetGuildId(message, i)
+	if !inGuild || guildID == "" {
+		interactions.SendMessage(session, i, channelID, "Scouting notes belong to a server, use this in one.")
+		return
+	}
+	if len(args) < 1 {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Usage: `%sscout <view|form|export> ...`", guildconfig.Get(guildID).CommandPrefix))
+		return
+	}
+
+	switch args[0] {
+	case "note", "match":
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Scouting is done in a form, use `/scout %s` to open it.", args[0]))
+	case "form":
+		season := guildconfig.SeasonOrDefault(guildID, "")
+		if len(args) > 1 {
+			season = args[1]
+		}
+		interactions.SendEmbed(session, i, channelID, createScoutFormEmbed(season, scouting.Form(guildID, season)))
+	case "export":
+		if len(args) < 3 {
+			interactions.SendMessage(session, i, channelID, fmt.Sprintf("Usage: `%sscout export <year> <eventCode>`", guildconfig.Get(guildID).CommandPrefix))
+			return
+		}
+		exportScouting(channelID, guildID, args[1], strings.ToUpper(args[2]), session, i)
+	case "view":
+		if len(args) < 2 {
+			interactions.SendMessage(session, i, channelID, fmt.Sprintf("Usage: `%sscout view <team> [event]`", guildconfig.Get(guildID).CommandPrefix))
+			return
+		}
+		team, err := strconv.Atoi(args[1])
+		if err != nil {
+			interactions.SendMessage(session, i, channelID, "Please provide a valid team number.")
+			return
+		}
+		eventCode := ""
+		if len(args) > 2 {
+			eventCode = strings.ToUpper(args[2])
+		}
+
+		err = scoutPaginator.Setup(session, i, channelID, map[string]string{
+			"guild":     guildID,
+			"team":      strconv.Itoa(team),
+			"eventCode": eventCode,
+		})
+		if err != nil {
+			interactions.SendMessage(session, i, channelID, fmt.Sprintf("Error sending scouting notes: %v", err))
+		}
+	default:
+		interactions.SendMessage(session, i, channelID, "Unknown subcommand. Use 'view', 'form' or 'export'.")
+	}
+}
+
+func launchScoutNoteModal(s interactions.Session, i *discordgo.InteractionCreate, year, eventCode, team string) {
+	if i.GuildID == "" {
+		interactions.SendEphemeralMessage(s, i, "Scouting notes belong to a server, use this in one.")
+		return
+	}
+	if _, err := strconv.Atoi(team); err != nil {
+		interactions.SendEphemeralMessage(s, i, "Please provide a valid team number.")
+		return
+	}
+	eventCode = strings.ToUpper(eventCode)
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("%s %s %s %s", scoutNoteModalId, year, eventCode, team),
+			Title:    fmt.Sprintf("Scouting Team %s at %s", team, eventCode),
+			Components: []discordgo.MessageComponent{
+				scoutTextInput(scoutDrivetrainInputId, "Drivetrain", "Mecanum, tank, swerve...", discordgo.TextInputShort, 100),
+				scoutTextInput(scoutAutoInputId, "Auto", "What do they score in auto?", discordgo.TextInputShort, 200),
+				scoutTextInput(scoutReliabilityInputId, fmt.Sprintf("Reliability (%d-%d)", scouting.MinReliability, scouting.MaxReliability), "5 means nothing ever went wrong", discordgo.TextInputShort, 1),
+				scoutTextInput(scoutTextInputId, "Notes", "Anything else worth knowing", discordgo.TextInputParagraph, 700),
+			},
+		},
+	})
+	if err != nil {
+		logging.WithInteraction(commandLog, i).Error("Error launching scouting note modal", "err", err)
+		interactions.SendEphemeralMessage(s, i, "Error displaying the scouting form.")
+	}
+}
+
+func scoutTextInput(id, label, placeholder string, style discordgo.TextInputStyle, maxLength int) discordgo.ActionsRow {
+	return discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.TextInput{
+				CustomID:    id,
+				Label:       label,
+				Style:       style,
+				Placeholder: placeholder,
+				Required:    false,
+				MaxLength:   maxLength,
+			},
+		},
+	}
+}
+
+func scoutInputValue(modalData discordgo.ModalSubmitInteractionData, id string) string {
+	input, ok := interactions.GetComponentWithId(modalData.Components, id).(*discordgo.TextInput)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(input.Value)
+}
+
+// handleScoutNoteSubmit saves the form, id_data is the year, event code and team from the modal's custom ID
+func handleScoutNoteSubmit(s interactions.Session, i *discordgo.InteractionCreate, id_data []string, modalData discordgo.ModalSubmitInteractionData) {
+	// only the scout needs to see that it saved
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+
+	if len(id_data) < 3 {
+		interactions.SendMessage(s, i, "", "That scouting form is broken, please open a new one.")
+		return
+	}
+	year, eventCode := id_data[0], id_data[1]
+	team, err := strconv.Atoi(id_data[2])
+	if err != nil {
+		interactions.SendMessage(s, i, "", "Please provide a valid team number.")
+		return
+	}
+	authorID, _ := interactions.GetAuthorId(nil, i)
+
+	note := scouting.Note{
+		Season:     year,
+		EventCode:  eventCode,
+		TeamNumber: team,
+		AuthorID:   authorID,
+		Drivetrain: scoutInputValue(modalData, scoutDrivetrainInputId),
+		Auto:       scoutInputValue(modalData, scoutAutoInputId),
+		Text:       scoutInputValue(modalData, scoutTextInputId),
+	}
+	if reliability := scoutInputValue(modalData, scoutReliabilityInputId); reliability != "" {
+		// a bad number gets caught by AddNote along with out of range ones
+		note.Reliability, err = strconv.Atoi(reliability)
+		if err != nil {
+			note.Reliability = -1
+		}
+	}
+
+	// the form can't be reopened with what was typed, so anything that didn't save is handed back
+	if _, err := search.FetchEventDataFrom(guildconfig.Provider(i.GuildID), year, eventCode); err != nil {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("Couldn't find event %s in %s, so your notes weren't saved:\n%s", eventCode, year, formatScoutNote(note)))
+		return
+	}
+	if err := scouting.AddNote(i.GuildID, note); err != nil {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("Couldn't save your notes (%v):\n%s", err, formatScoutNote(note)))
+		return
+	}
+	interactions.SendMessage(s, i, "", fmt.Sprintf("Saved your notes on Team %d at %s.", team, eventCode))
+}
+
+func formatScoutNote(note scouting.Note) string {
+	lines := []string{}
+	if note.Drivetrain != "" {
+		lines = append(lines, fmt.Sprintf("**Drivetrain:** %s", note.Drivetrain))
+	}
+	if note.Auto != "" {
+		lines = append(lines, fmt.Sprintf("**Auto:** %s", note.Auto))
+	}
+	if note.Reliability >= scouting.MinReliability && note.Reliability <= scouting.MaxReliability {
+		stars := strings.Repeat("★", note.Reliability) + strings.Repeat("☆", scouting.MaxReliability-note.Reliability)
+		lines = append(lines, fmt.Sprintf("**Reliability:** %s (%d/%d)", stars, note.Reliability, scouting.MaxReliability))
+	}
+	if note.Text != "" {
+		lines = append(lines, note.Text)
+	}
+	if len(lines) == 0 {
+		return "*Nothing written down.*"
+	}
+	return strings.Join(lines, "\n")
+}
+
+func updateScoutNotesEmbed(state pagination.PaginationState, notes []scouting.Note, embed *discordgo.MessageEmbed) (*discordgo.MessageEmbed, error) {
+	embed.Title = fmt.Sprintf("Scouting Notes on Team %s", state.ExtraData["team"])
+	if eventCode := state.ExtraData["eventCode"]; eventCode != "" {
+		embed.Title += " at " + eventCode
+	}
+	embed.Color = 0x72cfdd
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("Page %d of %d", state.CurrentPage+1, state.TotalPages),
+	}
+
+	embed.Fields = []*discordgo.MessageEmbedField{}
+	for _, note := range notes {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s %s", note.Season, note.EventCode),
+			Value: fmt.Sprintf("By <@%s> <t:%d:R>\n%s", note.AuthorID, note.CreatedAt.Unix(), formatScoutNote(note)),
+		})
+	}
+	return embed, nil
+}
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+)
+
+func scoutInteraction(guildID, subcommand string, options ...string) *discordgo.InteractionCreate {
+	return sessiontest.NewInteraction(guildID, "scout", discordgo.ApplicationCommandInteractionData{
+		Name: "scout",
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+			Name:    subcommand,
+			Type:    discordgo.ApplicationCommandOptionSubCommand,
+			Options: stringOptions(options...),
+		}},
+	})
+}
+
+// scoutNoteForm is the note form as discord sends it back, filled in with drivetrain, auto and reliability
+func scoutNoteForm(drivetrain, auto, reliability string) discordgo.ModalSubmitInteractionData {
+	row := func(id, value string) discordgo.MessageComponent {
+		return &discordgo.ActionsRow{Components: []discordgo.MessageComponent{&discordgo.TextInput{CustomID: id, Value: value}}}
+	}
+	return discordgo.ModalSubmitInteractionData{
+		CustomID: scoutNoteModalId,
+		Components: []discordgo.MessageComponent{
+			row(scoutDrivetrainInputId, drivetrain),
+			row(scoutAutoInputId, auto),
+			row(scoutReliabilityInputId, reliability),
+			row(scoutTextInputId, ""),
+		},
+	}
+}
+
+func TestScoutNote(t *testing.T) {
+	fakeEvent(t, "USSCOUTN1", 0, 1, []ftcscout.Match{})
+	session := sessiontest.New()
+	scout := interactions.CommandHandlers["scout"]
+
+	scout(session, scoutInteraction("scout-notes-guild", "note", "event", "usscoutn1", "team", "16271", "year", "2025"))
+	modal, ok := session.Last()
+	if !ok || modal.Kind != "respond" || len(modal.Components) != 4 {
+		t.Fatalf("responded with %+v, want the note form", modal)
+	}
+	row, ok := modal.Components[0].(discordgo.ActionsRow)
+	if !ok || row.Components[0].(discordgo.TextInput).CustomID != scoutDrivetrainInputId {
+		t.Errorf("first input is %+v, want the drivetrain", modal.Components[0])
+	}
+
+	submit := scoutInteraction("scout-notes-guild", "note")
+	handleScoutNoteSubmit(session, submit, []string{"2025", "USSCOUTN1", "16271"}, scoutNoteForm(" Mecanum ", "Two samples", "4"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Saved your notes on Team 16271 at USSCOUTN1.") {
+		t.Fatalf("submit got %q", got)
+	}
+
+	scout(session, scoutInteraction("scout-notes-guild", "view", "team", "16271"))
+	embeds := session.Embeds()
+	if len(embeds) != 1 || embeds[0].Title != "Scouting Notes on Team 16271" || len(embeds[0].Fields) != 1 {
+		t.Fatalf("view sent %+v", session.Sent())
+	}
+	field := embeds[0].Fields[0]
+	if field.Name != "2025 USSCOUTN1" || !strings.Contains(field.Value, "By <@scout>") {
+		t.Errorf("note heading = %q: %q", field.Name, field.Value)
+	}
+	if !strings.Contains(field.Value, "**Drivetrain:** Mecanum\n**Auto:** Two samples\n**Reliability:** ★★★★☆ (4/5)") {
+		t.Errorf("note = %q", field.Value)
+	}
+}
+
+func TestScoutNoteErrors(t *testing.T) {
+	fakeEvent(t, "USSCOUTN2", 0, 1, []ftcscout.Match{})
+	session := sessiontest.New()
+	scout := interactions.CommandHandlers["scout"]
+	submit := scoutInteraction("scout-errors-guild", "note")
+
+	// nothing typed into the form is lost when it can't be saved
+	handleScoutNoteSubmit(session, submit, []string{"2025", "USNOSCOUT", "16271"}, scoutNoteForm("Tank", "", ""))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Couldn't find event USNOSCOUT in 2025, so your notes weren't saved:\n**Drivetrain:** Tank") {
+		t.Errorf("unknown event got %q", got)
+	}
+	handleScoutNoteSubmit(session, submit, []string{"2025", "USSCOUTN2", "16271"}, scoutNoteForm("Tank", "", "9"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Couldn't save your notes (reliability has to be between 1 and 5):\n**Drivetrain:** Tank") {
+		t.Errorf("bad reliability got %q", got)
+	}
+	handleScoutNoteSubmit(session, submit, []string{"2025"}, scoutNoteForm("Tank", "", ""))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "That scouting form is broken, please open a new one.") {
+		t.Errorf("broken custom id got %q", got)
+	}
+
+	scout(session, scoutInteraction("scout-errors-guild", "note", "event", "USSCOUTN2", "team", "roboknights"))
+	if sent, _ := session.Last(); !sent.Ephemeral || !strings.HasPrefix(sent.Content, "Please provide a valid team number.") {
+		t.Errorf("bad team got %+v", sent)
+	}
+	scout(session, scoutInteraction("", "note", "event", "USSCOUTN2", "team", "16271"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Scouting notes belong to a server, use this in one.") {
+		t.Errorf("note in a DM got %q", got)
+	}
+
+	scout(session, scoutInteraction("scout-errors-guild", "view", "team", "11111"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Error sending scouting notes: ") || !strings.Contains(got, "no scouting notes on team 11111 yet") {
+		t.Errorf("view without notes got %q", got)
+	}
+
+	if err := guildconfig.Update("scout-text-guild", func(cfg *guildconfig.Config) { cfg.CommandPrefix = "$" }); err != nil {
+		t.Fatal(err)
+	}
+	scoutcmd(session, sessiontest.NewMessage("scout-text-guild", "channel", "scout", "$scout view"), nil, []string{"view"})
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Usage: `$scout view <team> [event]`") {
+		t.Errorf("view without a team got %q", got)
+	}
+}
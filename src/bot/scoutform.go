@@ -0,0 +1,523 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/scouting"
+)
+
+// match scouting goes pick a match -> pick a team in it -> fill in the season's form in a modal,
+// each step carries the year, event and what's been picked so far in its custom ID
+const (
+	scoutMatchSelectId = "scout;match"
+	scoutTeamSelectId  = "scout;team"
+	scoutEntryModalId  = "scout;entry"
+
+	// a select menu can only have 25 options
+	maxScoutMatchOptions = 25
+	// how many already played matches the match menu starts with, for scouts catching up
+	scoutMatchesBehind = 5
+)
+
+func init() {
+	interactions.RegisterComponentHandler(scoutMatchSelectId, handleScoutMatchSelect)
+	interactions.RegisterComponentHandler(scoutTeamSelectId, handleScoutTeamSelect)
+	interactions.RegisterModalHandler(scoutEntryModalId, handleScoutEntrySubmit)
+}
+
+func scoutFormOptions() []*discordgo.ApplicationCommandOption {
+	fieldTypeChoices := []*discordgo.ApplicationCommandOptionChoice{}
+	for _, fieldType := range scouting.FieldTypes {
+		fieldTypeChoices = append(fieldTypeChoices, &discordgo.ApplicationCommandOptionChoice{Name: string(fieldType), Value: string(fieldType)})
+	}
+	yearOption := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "year",
+		Description: "Season the form is for (defaults to this server's season).",
+		Required:    false,
+		Choices:     interactions.FtcYearChoices,
+	}
+
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "add",
+			Description: "Add a field to the match scouting form, or change the one with the same name (admin only).",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "name",
+					Description: "What scouts are asked, e.g. \"Samples scored in auto\".",
+					Required:    true,
+					MaxLength:   45,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "type",
+					Description: "What kind of answer it takes.",
+					Required:    true,
+					Choices:     fieldTypeChoices,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionNumber,
+					Name:        "min",
+					Description: "Lowest allowed answer, for number fields.",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionNumber,
+					Name:        "max",
+					Description: "Highest allowed answer, for number fields.",
+					Required:    false,
+				},
+				yearOption,
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "remove",
+			Description: "Remove a field from the match scouting form (admin only).",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "name",
+					Description: "Name of the field.",
+					Required:    true,
+				},
+				yearOption,
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "view",
+			Description: "Show the match scouting form.",
+			Options:     []*discordgo.ApplicationCommandOption{yearOption},
+		},
+	}
+}
+
+// handleScoutFormCommand runs /scout form <add|remove|view>, changing the form is for admins only
+func handleScoutFormCommand(s interactions.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	if i.GuildID == "" || i.Member == nil {
+		interactions.SendEphemeralMessage(s, i, "Scouting forms belong to a server, use this in one.")
+		return
+	}
+	if len(sub.Options) == 0 {
+		interactions.SendEphemeralMessage(s, i, "Please provide a subcommand for scout form.")
+		return
+	}
+	action := sub.Options[0]
+
+	if action.Name != "view" {
+		isAdminUser, err := isAdmin(s, i.GuildID, i.Member.User.ID)
+		if err != nil {
+			interactions.SendEphemeralMessage(s, i, "Unable to check permissions.")
+			return
+		}
+		if !isAdminUser {
+			interactions.SendEphemeralMessage(s, i, "Only admins can change the scouting form.")
+			return
+		}
+	}
+
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
+	season := guildconfig.SeasonOrDefault(i.GuildID, interactions.GetStringOption(action.Options, "year"))
+	name := interactions.GetStringOption(action.Options, "name")
+
+	switch action.Name {
+	case "add":
+		field := scouting.Field{
+			Name: name,
+			Type: scouting.FieldType(interactions.GetStringOption(action.Options, "type")),
+		}
+		if min, ok := interactions.GetFloatOption(action.Options, "min"); ok {
+			field.Min = &min
+		}
+		if max, ok := interactions.GetFloatOption(action.Options, "max"); ok {
+			field.Max = &max
+		}
+		if err := scouting.AddField(i.GuildID, season, field); err != nil {
+			interactions.SendMessage(s, i, "", fmt.Sprintf("Couldn't add %s: %v", name, err))
+			return
+		}
+	case "remove":
+		removed, err := scouting.RemoveField(i.GuildID, season, name)
+		if err != nil {
+			interactions.SendMessage(s, i, "", fmt.Sprintf("Couldn't remove %s: %v", name, err))
+			return
+		}
+		if !removed {
+			interactions.SendMessage(s, i, "", fmt.Sprintf("The %s form doesn't have a field called %s.", season, name))
+			return
+		}
+	}
+	interactions.SendEmbed(s, i, "", createScoutFormEmbed(season, scouting.Form(i.GuildID, season)))
+}
+
+func createScoutFormEmbed(season string, form []scouting.Field) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("%s Match Scouting Form", formatSeason(season)),
+		Color: 0x72cfdd,
+	}
+	if len(form) == 0 {
+		embed.Description = "There are no fields yet, admins can add some with `/scout form add`."
+		return embed
+	}
+
+	embed.Description = "Scouts fill this in with `/scout match`."
+	for idx, field := range form {
+		value := string(field.Type)
+		if fieldRange := field.Range(); fieldRange != "" {
+			value += ", " + fieldRange
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%d. %s", idx+1, field.Name),
+			Value: value,
+		})
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("%d of %d fields", len(form), scouting.MaxFields),
+	}
+	return embed
+}
+
+// scoutMatch starts match scouting by asking which match, only the scout sees the menus
+func scoutMatch(s interactions.Session, i *discordgo.InteractionCreate, year, eventCode string) {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+	if i.GuildID == "" {
+		interactions.SendMessage(s, i, "", "Scouting forms belong to a server, use this in one.")
+		return
+	}
+	if len(scouting.Form(i.GuildID, year)) == 0 {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("There's no %s scouting form yet, ask an admin to set one up with `/scout form add`.", year))
+		return
+	}
+
+	matches, err := eventMatchesCache.GetOrFetch(fmt.Sprintf("%s %s %s", guildconfig.Provider(i.GuildID).Name(), year, eventCode))
+	if err != nil {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("Failed to fetch matches for %s: %v", eventCode, err))
+		return
+	}
+
+	options := scoutMatchOptions(matches)
+	if len(options) == 0 {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("%s doesn't have any matches scheduled yet.", eventCode))
+		return
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.StringSelectMenu,
+					CustomID:    fmt.Sprintf("%s %s %s", scoutMatchSelectId, year, eventCode),
+					Placeholder: "Pick a match",
+					Options:     options,
+				},
+			},
+		},
+	}
+	content := fmt.Sprintf("Which match at %s are you scouting?", eventCode)
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content, Components: &components}); err != nil {
+		logging.WithInteraction(commandLog, i).Error("Failed to send scouting match menu", "err", err)
+	}
+}
+
+// scoutMatchOptions lists the matches around where the event is at, starting a few matches back
+func scoutMatchOptions(matches []ftcscout.Match) []discordgo.SelectMenuOption {
+	withTeams := []ftcscout.Match{}
+	for _, match := range matches {
+		if len(match.Teams) > 0 {
+			withTeams = append(withTeams, match)
+		}
+	}
+	sortMatchesByID(withTeams)
+
+	start := len(withTeams)
+	for idx, match := range withTeams {
+		if !match.HasBeenPlayed {
+			start = idx
+			break
+		}
+	}
+	start = max(0, min(start-scoutMatchesBehind, len(withTeams)-maxScoutMatchOptions))
+	end := min(len(withTeams), start+maxScoutMatchOptions)
+
+	options := []discordgo.SelectMenuOption{}
+	for _, match := range withTeams[start:end] {
+		redTeams, blueTeams := splitAlliances(match)
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       getMatchName(match),
+			Value:       strconv.Itoa(match.ID),
+			Description: fmt.Sprintf("🔴 %s vs 🔵 %s", teamNumbersOf(redTeams), teamNumbersOf(blueTeams)),
+		})
+	}
+	return options
+}
+
+func teamNumbersOf(teams []TeamDTO) string {
+	numbers := []string{}
+	for _, team := range teams {
+		numbers = append(numbers, strconv.Itoa(team.TeamNumber))
+	}
+	return strings.Join(numbers, ", ")
+}
+
+// findScoutedMatch looks the match up again from the cache, data is the year, event and match ID from a custom ID
+func findScoutedMatch(guildID string, data []string) (ftcscout.Match, error) {
+	if len(data) < 3 {
+		return ftcscout.Match{}, fmt.Errorf("that menu is broken, please start over with /scout match")
+	}
+	matches, err := eventMatchesCache.GetOrFetch(fmt.Sprintf("%s %s %s", guildconfig.Provider(guildID).Name(), data[0], data[1]))
+	if err != nil {
+		return ftcscout.Match{}, err
+	}
+	for _, match := range matches {
+		if strconv.Itoa(match.ID) == data[2] {
+			return match, nil
+		}
+	}
+	return ftcscout.Match{}, fmt.Errorf("couldn't find that match anymore, please start over with /scout match")
+}
+
+// handleScoutMatchSelect swaps the match menu for one with the teams in the picked match
+func handleScoutMatchSelect(s interactions.Session, i *discordgo.InteractionCreate, data []string) {
+	values := i.MessageComponentData().Values
+	if len(data) < 2 || len(values) == 0 {
+		return
+	}
+	year, eventCode := data[0], data[1]
+
+	match, err := findScoutedMatch(i.GuildID, []string{year, eventCode, values[0]})
+	if err != nil {
+		interactions.SendEphemeralMessage(s, i, err.Error())
+		return
+	}
+
+	options := []discordgo.SelectMenuOption{}
+	for _, team := range match.Teams {
+		emoji := "🔵"
+		if team.AllianceColor == "Red" {
+			emoji = "🔴"
+		}
+		options = append(options, discordgo.SelectMenuOption{
+			Label: fmt.Sprintf("%s %d", emoji, team.TeamNumber),
+			Value: strconv.Itoa(team.TeamNumber),
+		})
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Which team in %s are you scouting?", getMatchName(match)),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.SelectMenu{
+							MenuType:    discordgo.StringSelectMenu,
+							CustomID:    fmt.Sprintf("%s %s %s %d", scoutTeamSelectId, year, eventCode, match.ID),
+							Placeholder: "Pick a team",
+							Options:     options,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		logging.WithInteraction(commandLog, i).Error("Failed to send scouting team menu", "err", err)
+	}
+}
+
+// handleScoutTeamSelect opens the season's form for the picked team
+func handleScoutTeamSelect(s interactions.Session, i *discordgo.InteractionCreate, data []string) {
+	values := i.MessageComponentData().Values
+	if len(data) < 3 || len(values) == 0 {
+		return
+	}
+	year, eventCode, team := data[0], data[1], values[0]
+
+	match, err := findScoutedMatch(i.GuildID, data)
+	if err != nil {
+		interactions.SendEphemeralMessage(s, i, err.Error())
+		return
+	}
+	form := scouting.Form(i.GuildID, year)
+	if len(form) == 0 {
+		interactions.SendEphemeralMessage(s, i, fmt.Sprintf("The %s scouting form was removed, ask an admin to set one up with `/scout form add`.", year))
+		return
+	}
+
+	inputs := []discordgo.MessageComponent{}
+	for _, field := range form {
+		input := discordgo.TextInput{
+			CustomID: field.Name,
+			Label:    field.Name,
+			Style:    discordgo.TextInputShort,
+			Required: field.Type != scouting.Text,
+		}
+		switch field.Type {
+		case scouting.Number:
+			input.Placeholder = field.Range()
+			input.MaxLength = 20
+		case scouting.YesNo:
+			input.Placeholder = "yes or no"
+			input.MaxLength = 5
+		default:
+			input.Style = discordgo.TextInputParagraph
+			input.MaxLength = 500
+		}
+		inputs = append(inputs, discordgo.ActionsRow{Components: []discordgo.MessageComponent{input}})
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID:   fmt.Sprintf("%s %s %s %d %s", scoutEntryModalId, year, eventCode, match.ID, team),
+			Title:      fmt.Sprintf("Team %s in %s", team, getMatchName(match)),
+			Components: inputs,
+		},
+	})
+	if err != nil {
+		logging.WithInteraction(commandLog, i).Error("Error launching scouting form modal", "err", err)
+		interactions.SendEphemeralMessage(s, i, "Error displaying the scouting form.")
+	}
+}
+
+// handleScoutEntrySubmit checks the answers against the form and saves them,
+// id_data is the year, event code, match ID and team from the modal's custom ID
+func handleScoutEntrySubmit(s interactions.Session, i *discordgo.InteractionCreate, id_data []string, modalData discordgo.ModalSubmitInteractionData) {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+	if len(id_data) < 4 {
+		interactions.SendMessage(s, i, "", "That scouting form is broken, please start over with /scout match.")
+		return
+	}
+
+	match, err := findScoutedMatch(i.GuildID, id_data)
+	if err != nil {
+		interactions.SendMessage(s, i, "", err.Error())
+		return
+	}
+	team, err := strconv.Atoi(id_data[3])
+	if err != nil {
+		interactions.SendMessage(s, i, "", "Please provide a valid team number.")
+		return
+	}
+	authorID, _ := interactions.GetAuthorId(nil, i)
+
+	entry := scouting.Entry{
+		Season:     id_data[0],
+		EventCode:  id_data[1],
+		MatchID:    match.ID,
+		TeamNumber: team,
+		AuthorID:   authorID,
+		Values:     make(map[string]string),
+	}
+	// the answers are all handed back on a mistake since the modal can't be reopened with them
+	problems := []string{}
+	answers := []string{}
+	for _, field := range scouting.Form(i.GuildID, entry.Season) {
+		raw := scoutInputValue(modalData, field.Name)
+		answers = append(answers, fmt.Sprintf("**%s:** %s", field.Name, raw))
+		if raw == "" {
+			continue
+		}
+		value, err := field.Parse(raw)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		entry.Values[field.Name] = value
+	}
+	if len(problems) > 0 {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("Your scouting wasn't saved, %s.\n%s", strings.Join(problems, ", "), strings.Join(answers, "\n")))
+		return
+	}
+
+	if err := scouting.SubmitEntry(i.GuildID, entry); err != nil {
+		interactions.SendMessage(s, i, "", fmt.Sprintf("Couldn't save your scouting (%v):\n%s", err, strings.Join(answers, "\n")))
+		return
+	}
+	interactions.SendMessage(s, i, "", fmt.Sprintf("Saved your scouting of Team %d in %s at %s.", team, getMatchName(match), entry.EventCode))
+}
+
+// scoutSummaryRecord is one team's row in the scouting export, the columns depend on the form
+type scoutSummaryRecord []string
+
+func (r scoutSummaryRecord) csvRow() []string {
+	return r
+}
+
+// buildScoutSummaryTable has teamNumber, entries and matches, then "<field> avg" and "<field> max" for number fields
+// and "<field> %" (how often the answer was yes) for yes/no fields. Text fields are left out.
+func buildScoutSummaryTable(form []scouting.Field, summaries []scouting.TeamSummary) exportTable {
+	table := exportTable{Columns: []string{"teamNumber", "entries", "matches"}}
+	for _, field := range form {
+		switch field.Type {
+		case scouting.Number:
+			table.Columns = append(table.Columns, field.Name+" avg", field.Name+" max")
+		case scouting.YesNo:
+			table.Columns = append(table.Columns, field.Name+" %")
+		}
+	}
+
+	for _, summary := range summaries {
+		row := scoutSummaryRecord{strconv.Itoa(summary.TeamNumber), strconv.Itoa(summary.Entries), strconv.Itoa(summary.Matches)}
+		for _, field := range form {
+			stats, answered := summary.Stats[field.Name]
+			switch field.Type {
+			case scouting.Number:
+				if !answered {
+					row = append(row, "", "")
+					continue
+				}
+				row = append(row, strconv.FormatFloat(stats.Average, 'f', 2, 64), formatExportFloat(stats.Max))
+			case scouting.YesNo:
+				if !answered {
+					row = append(row, "")
+					continue
+				}
+				row = append(row, strconv.FormatFloat(stats.Average*100, 'f', 1, 64))
+			}
+		}
+		table.Records = append(table.Records, row)
+	}
+	return table
+}
+
+func exportScouting(channelID, guildID, year, eventCode string, session interactions.Session, i *discordgo.InteractionCreate) {
+	form, summaries := scouting.Summarize(guildID, year, eventCode)
+	if len(summaries) == 0 {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Nobody has scouted any matches at %s %s yet.", year, eventCode))
+		return
+	}
+
+	buf, err := encodeExportCSV(buildScoutSummaryTable(form, summaries))
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to build export: %v", err))
+		return
+	}
+
+	content := fmt.Sprintf("%s %s scouting (%d teams)", year, eventCode, len(summaries))
+	export := interactions.Attachment{
+		Name:        fmt.Sprintf("%s-%s-scouting.csv", year, eventCode),
+		ContentType: "text/csv",
+		Data:        buf.Bytes(),
+	}
+	if err := interactions.EditOrSend(session, i, channelID, content, nil, export); err != nil {
+		logging.WithInteraction(commandLog, i).Error("Failed to send scouting export", "channel", channelID, "err", err)
+	}
+}
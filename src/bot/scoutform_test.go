@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+	"github.com/shuban-789/bjorn/src/bot/scouting"
+)
+
+func TestExportScouting(t *testing.T) {
+	for _, field := range []scouting.Field{{Name: "Samples", Type: scouting.Number}, {Name: "Parked", Type: scouting.YesNo}} {
+		if err := scouting.AddField("scout-guild", "2025", field); err != nil {
+			t.Fatalf("AddField: %v", err)
+		}
+	}
+	entries := []scouting.Entry{
+		{MatchID: 1, TeamNumber: 16271, Values: map[string]string{"Samples": "4", "Parked": "yes"}},
+		{MatchID: 2, TeamNumber: 16271, Values: map[string]string{"Samples": "6", "Parked": "no"}},
+		{MatchID: 1, TeamNumber: 11111, Values: map[string]string{"Samples": "2"}},
+	}
+	for _, entry := range entries {
+		entry.Season, entry.EventCode, entry.AuthorID = "2025", "USSCOUTQ1", "scout"
+		if err := scouting.SubmitEntry("scout-guild", entry); err != nil {
+			t.Fatalf("SubmitEntry: %v", err)
+		}
+	}
+	session := sessiontest.New()
+	i := sessiontest.NewInteraction("scout-guild", "member", discordgo.ApplicationCommandInteractionData{Name: "scout"})
+
+	exportScouting("channel", "scout-guild", "2025", "USSCOUTQ1", session, i)
+
+	sent, _ := session.Last()
+	if sent.Kind != "edit" || len(sent.Files) != 1 || !strings.HasPrefix(sent.Content, "2025 USSCOUTQ1 scouting (2 teams)") {
+		t.Fatalf("sent %+v, want the summary edited into the response", sent)
+	}
+	if sent.Files[0].Name != "2025-USSCOUTQ1-scouting.csv" {
+		t.Errorf("file name = %q", sent.Files[0].Name)
+	}
+	csv, _ := io.ReadAll(sent.Files[0].Reader)
+	want := "teamNumber,entries,matches,Samples avg,Samples max,Parked %\n" +
+		"11111,1,1,2.00,2,\n" +
+		"16271,2,2,5.00,6,50.0\n"
+	if string(csv) != want {
+		t.Errorf("csv =\n%s\nwant\n%s", csv, want)
+	}
+}
+
+func TestExportScoutingNothingScouted(t *testing.T) {
+	session := sessiontest.New()
+	i := sessiontest.NewInteraction("scout-guild", "member", discordgo.ApplicationCommandInteractionData{Name: "scout"})
+
+	exportScouting("channel", "scout-guild", "2025", "USSCOUTQ9", session, i)
+
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Nobody has scouted any matches at 2025 USSCOUTQ9 yet.") {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,294 @@
+package scouting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+// FieldType is what kind of answer a form field takes
+type FieldType string
+
+const (
+	Number FieldType = "number"
+	YesNo  FieldType = "yesno"
+	Text   FieldType = "text"
+)
+
+var FieldTypes = []FieldType{Number, YesNo, Text}
+
+// MaxFields is how many fields a form can have, since a discord modal only fits 5 inputs
+const MaxFields = 5
+
+// Field is one question on a match scouting form. Min and Max only apply to number fields.
+type Field struct {
+	Name string    `json:"name"`
+	Type FieldType `json:"type"`
+	Min  *float64  `json:"min,omitempty"`
+	Max  *float64  `json:"max,omitempty"`
+}
+
+// Range is how the field's limits are shown, e.g. "0-10", "at least 0" or "" when there are none
+func (f Field) Range() string {
+	switch {
+	case f.Min != nil && f.Max != nil:
+		return fmt.Sprintf("%s-%s", formatNumber(*f.Min), formatNumber(*f.Max))
+	case f.Min != nil:
+		return fmt.Sprintf("at least %s", formatNumber(*f.Min))
+	case f.Max != nil:
+		return fmt.Sprintf("at most %s", formatNumber(*f.Max))
+	}
+	return ""
+}
+
+// Parse checks an answer and returns it the way it's stored: numbers without extra zeros and yes/no as "yes" or "no"
+func (f Field) Parse(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	switch f.Type {
+	case Number:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", fmt.Errorf("%s has to be a number", f.Name)
+		}
+		if (f.Min != nil && number < *f.Min) || (f.Max != nil && number > *f.Max) {
+			return "", fmt.Errorf("%s has to be %s", f.Name, f.Range())
+		}
+		return formatNumber(number), nil
+	case YesNo:
+		switch strings.ToLower(value) {
+		case "y", "yes", "true", "1":
+			return "yes", nil
+		case "n", "no", "false", "0":
+			return "no", nil
+		}
+		return "", fmt.Errorf("%s has to be yes or no", f.Name)
+	}
+	return value, nil
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+// Entry is one scout's answers for one team in one match
+type Entry struct {
+	Season     string    `json:"season"`
+	EventCode  string    `json:"eventCode"`
+	MatchID    int       `json:"matchId"`
+	TeamNumber int       `json:"teamNumber"`
+	AuthorID   string    `json:"authorId"`
+	CreatedAt  time.Time `json:"createdAt"`
+	// field name -> answer, already checked by Field.Parse
+	Values map[string]string `json:"values"`
+}
+
+var (
+	formStore  = store.New[map[string]map[string][]Field]("scouting_forms")
+	entryStore = store.New[map[string][]Entry]("scouting_entries")
+
+	// guild ID -> season -> fields, in the order they're asked
+	forms map[string]map[string][]Field
+	// guild ID -> entries
+	entries map[string][]Entry
+)
+
+// loadForms is called with mu held, forms and entries share the lock with notes
+func loadForms() {
+	if forms != nil {
+		return
+	}
+
+	loadedForms, err := formStore.Load()
+	if err != nil {
+		logger.Error("Failed to load scouting forms", "err", err)
+	}
+	if loadedForms == nil {
+		loadedForms = make(map[string]map[string][]Field)
+	}
+	forms = loadedForms
+
+	loadedEntries, err := entryStore.Load()
+	if err != nil {
+		logger.Error("Failed to load scouting entries", "err", err)
+	}
+	if loadedEntries == nil {
+		loadedEntries = make(map[string][]Entry)
+	}
+	entries = loadedEntries
+}
+
+// Form returns the guild's form for a season, which is empty until an admin adds fields
+func Form(guildID, season string) []Field {
+	mu.Lock()
+	defer mu.Unlock()
+	loadForms()
+
+	return append([]Field(nil), forms[guildID][season]...)
+}
+
+// AddField adds a field to the end of the form, or replaces the field with the same name
+func AddField(guildID, season string, field Field) error {
+	field.Name = strings.TrimSpace(field.Name)
+	if field.Name == "" {
+		return fmt.Errorf("the field needs a name")
+	}
+	if field.Type != Number {
+		field.Min, field.Max = nil, nil
+	}
+	if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+		return fmt.Errorf("the minimum can't be more than the maximum")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	loadForms()
+
+	if forms[guildID] == nil {
+		forms[guildID] = make(map[string][]Field)
+	}
+	form := forms[guildID][season]
+	for idx, existing := range form {
+		if strings.EqualFold(existing.Name, field.Name) {
+			form[idx] = field
+			return formStore.Save(forms)
+		}
+	}
+	if len(form) >= MaxFields {
+		return fmt.Errorf("a form can have at most %d fields, remove one first", MaxFields)
+	}
+	forms[guildID][season] = append(form, field)
+	return formStore.Save(forms)
+}
+
+// RemoveField takes a field off the form, returning false if there was no field with that name.
+// Answers already given for it are kept so they still show up in exports.
+func RemoveField(guildID, season, name string) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	loadForms()
+
+	form := forms[guildID][season]
+	for idx, existing := range form {
+		if strings.EqualFold(existing.Name, name) {
+			forms[guildID][season] = append(form[:idx], form[idx+1:]...)
+			return true, formStore.Save(forms)
+		}
+	}
+	return false, nil
+}
+
+// SubmitEntry saves a scout's answers, replacing what they sent before for the same team and match
+func SubmitEntry(guildID string, entry Entry) error {
+	if guildID == "" {
+		return fmt.Errorf("scouting entries can only be submitted in a server")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	loadForms()
+
+	entry.EventCode = strings.ToUpper(entry.EventCode)
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	guildEntries := entries[guildID]
+	for idx, existing := range guildEntries {
+		if existing.Season == entry.Season && existing.EventCode == entry.EventCode && existing.MatchID == entry.MatchID &&
+			existing.TeamNumber == entry.TeamNumber && existing.AuthorID == entry.AuthorID {
+			guildEntries[idx] = entry
+			return entryStore.Save(entries)
+		}
+	}
+	entries[guildID] = append(guildEntries, entry)
+	return entryStore.Save(entries)
+}
+
+// FieldStats sums up a number or yes/no field over a team's entries, yes/no answers count as 1 and 0
+type FieldStats struct {
+	Count   int
+	Average float64
+	Max     float64
+}
+
+// TeamSummary is everything scouted about one team at an event
+type TeamSummary struct {
+	TeamNumber int
+	Entries    int
+	Matches    int
+	Stats      map[string]FieldStats
+}
+
+// Summarize averages every number and yes/no field of the season's form per team at an event, sorted by team number
+func Summarize(guildID, season, eventCode string) ([]Field, []TeamSummary) {
+	mu.Lock()
+	defer mu.Unlock()
+	loadForms()
+
+	form := append([]Field(nil), forms[guildID][season]...)
+	byTeam := make(map[int]*TeamSummary)
+	matchesByTeam := make(map[int]map[int]bool)
+	sums := make(map[int]map[string]float64)
+
+	for _, entry := range entries[guildID] {
+		if entry.Season != season || !strings.EqualFold(entry.EventCode, eventCode) {
+			continue
+		}
+
+		summary, ok := byTeam[entry.TeamNumber]
+		if !ok {
+			summary = &TeamSummary{TeamNumber: entry.TeamNumber, Stats: make(map[string]FieldStats)}
+			byTeam[entry.TeamNumber] = summary
+			matchesByTeam[entry.TeamNumber] = make(map[int]bool)
+			sums[entry.TeamNumber] = make(map[string]float64)
+		}
+		summary.Entries++
+		matchesByTeam[entry.TeamNumber][entry.MatchID] = true
+
+		for _, field := range form {
+			value, ok := numericValue(field, entry.Values[field.Name])
+			if !ok {
+				continue
+			}
+			stats := summary.Stats[field.Name]
+			if stats.Count == 0 || value > stats.Max {
+				stats.Max = value
+			}
+			stats.Count++
+			sums[entry.TeamNumber][field.Name] += value
+			summary.Stats[field.Name] = stats
+		}
+	}
+
+	result := make([]TeamSummary, 0, len(byTeam))
+	for team, summary := range byTeam {
+		summary.Matches = len(matchesByTeam[team])
+		for name, stats := range summary.Stats {
+			stats.Average = sums[team][name] / float64(stats.Count)
+			summary.Stats[name] = stats
+		}
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].TeamNumber < result[b].TeamNumber })
+	return form, result
+}
+
+// numericValue is the answer as a number, for fields that have one
+func numericValue(field Field, value string) (float64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	switch field.Type {
+	case Number:
+		number, err := strconv.ParseFloat(value, 64)
+		return number, err == nil
+	case YesNo:
+		return map[string]float64{"yes": 1, "no": 0}[value], value == "yes" || value == "no"
+	}
+	return 0, false
+}
@@ -0,0 +1,102 @@
+// Package scouting stores what a server's scouts write down about other teams.
+// Everything is kept per guild and only ever read back for that guild, so one server's notes never show up in another.
+package scouting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+var logger = logging.For("scouting")
+
+// Note is one scout's notes on a team at an event
+type Note struct {
+	Season     string    `json:"season"`
+	EventCode  string    `json:"eventCode"`
+	TeamNumber int       `json:"teamNumber"`
+	AuthorID   string    `json:"authorId"`
+	CreatedAt  time.Time `json:"createdAt"`
+
+	Drivetrain string `json:"drivetrain,omitempty"`
+	Auto       string `json:"auto,omitempty"`
+	// 1 (breaks down every match) to 5 (never had a problem), 0 if the scout didn't say
+	Reliability int    `json:"reliability,omitempty"`
+	Text        string `json:"text,omitempty"`
+}
+
+const (
+	MinReliability = 1
+	MaxReliability = 5
+)
+
+var (
+	noteStore = store.New[map[string][]Note]("scouting_notes")
+
+	// guild ID -> notes, in the order they were written
+	notes map[string][]Note
+	mu    sync.Mutex
+)
+
+func load() {
+	if notes != nil {
+		return
+	}
+
+	loaded, err := noteStore.Load()
+	if err != nil {
+		logger.Error("Failed to load scouting notes", "err", err)
+	}
+	if loaded == nil {
+		loaded = make(map[string][]Note)
+	}
+	notes = loaded
+}
+
+// AddNote saves a note for the guild
+func AddNote(guildID string, note Note) error {
+	if guildID == "" {
+		return fmt.Errorf("scouting notes can only be written in a server")
+	}
+	if note.Reliability != 0 && (note.Reliability < MinReliability || note.Reliability > MaxReliability) {
+		return fmt.Errorf("reliability has to be between %d and %d", MinReliability, MaxReliability)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+
+	note.EventCode = strings.ToUpper(note.EventCode)
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = time.Now()
+	}
+	notes[guildID] = append(notes[guildID], note)
+	return noteStore.Save(notes)
+}
+
+// TeamNotes returns the guild's notes on a team, newest first. An empty eventCode gives notes from every event.
+func TeamNotes(guildID string, team int, eventCode string) []Note {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+
+	result := []Note{}
+	for _, note := range notes[guildID] {
+		if note.TeamNumber != team {
+			continue
+		}
+		if eventCode != "" && !strings.EqualFold(note.EventCode, eventCode) {
+			continue
+		}
+		result = append(result, note)
+	}
+	sort.SliceStable(result, func(a, b int) bool {
+		return result[a].CreatedAt.After(result[b].CreatedAt)
+	})
+	return result
+}
@@ -4,41 +4,17 @@
 package search
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"sort"
 	"time"
 
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/provider"
 	"github.com/shuban-789/bjorn/src/bot/util"
 )
 
-type EventData struct {
-	Season        int    `json:"season"`
-	Code          string `json:"code"`
-	DivisionCode  string `json:"divisionCode"`
-	Name          string `json:"name"`
-	FieldCount    int    `json:"fieldCount"`
-	Published     bool   `json:"published"`
-	Type          string `json:"type"`
-	RegionCode    string `json:"regionCode"`
-	LeagueCode    string `json:"leagueCode"`
-	DistrictCode  string `json:"districtCode"`
-	Venue         string `json:"venue"`
-	Address       string `json:"address"`
-	Country       string `json:"country"`
-	State         string `json:"state"`
-	City          string `json:"city"`
-	Website       string `json:"website"`
-	LiveStreamUrl string `json:"liveStreamUrl"`
-	Timezone      string `json:"timezone"`
-	Start         string `json:"start"`
-	End           string `json:"end"`
-	Ongoing       bool   `json:"ongoing"`
-	ModifiedRules bool   `json:"modifiedRules"`
-	HasMatches    bool   `json:"hasMatches"`
-}
+type EventData = ftcscout.Event
 
 type EventInfo struct {
 	Code     string
@@ -63,30 +39,10 @@ func FetchEvents() map[string][]EventInfo {
 		return cachedEventData
 	}
 
-	fmt.Println(util.Info("Fetching events data from API..."))
-	api := "https://api.ftcscout.org/rest/v1/events/search/2025"
-
-	resp, err := http.Get(api)
+	logger.Info("Fetching events data from API")
+	events, err := ftcscout.Default.SearchEvents(context.Background(), "2025")
 	if err != nil {
-		fmt.Println(util.Fail("Failed to fetch events data from API: %v", err))
-		return nil
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Println(util.Fail("Events API returned status code: %d", resp.StatusCode))
-		return nil
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println(util.Fail("Failed to read events response body: %v", err))
-		return nil
-	}
-
-	var events []EventData
-	if err := json.Unmarshal(body, &events); err != nil {
-		fmt.Println(util.Fail("Failed to parse events JSON response: %v", err))
+		logger.Error("Failed to fetch events data from API", "err", err)
 		return nil
 	}
 
@@ -113,14 +69,27 @@ func FetchEvents() map[string][]EventInfo {
 	return cachedEventData
 }
 
-func startEventFetcher() {
-	go func() {
-		for {
-			FetchEvents()
-			fmt.Println(util.Info("Event data refreshed"))
-			time.Sleep(24 * time.Hour)
+// RunEventFetcher keeps the event data fresh until ctx is cancelled
+func RunEventFetcher(ctx context.Context) {
+	for {
+		FetchEvents()
+		logger.Info("Event data refreshed")
+		if !sleep(ctx, 24*time.Hour) {
+			return
 		}
-	}()
+	}
+}
+
+// sleep waits for d, returning false if ctx was cancelled first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
 }
 
 func GetEventsData() map[string][]EventInfo {
@@ -176,34 +145,20 @@ func GetEventCodeFromName(name string, regionCode string) (code string, ok bool)
 	return "", false
 }
 
-func init() {
-	startEventFetcher()
-}
-
 func FetchEventData(year, eventCode string) (EventData, error) {
-	url := fmt.Sprintf("https://api.ftcscout.org/rest/v1/events/%s/%s", year, eventCode)
-	resp, err := http.Get(url)
-	if err != nil {
-		return EventData{}, fmt.Errorf("failed to fetch match data: %v", err)
-	}
-	defer resp.Body.Close()
+	return FetchEventDataFrom(provider.Default(), year, eventCode)
+}
 
-	if resp.StatusCode == http.StatusNotFound {
+func FetchEventDataFrom(p provider.Provider, year, eventCode string) (EventData, error) {
+	eventData, err := p.Event(context.Background(), year, eventCode)
+	if provider.IsNotFound(err) {
 		return EventData{}, fmt.Errorf("that event does not exist!")
 	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return EventData{}, fmt.Errorf("failed to read response: %v", err)
-	}
-
-	var eventData EventData
-	err = json.Unmarshal(body, &eventData)
 	if err != nil {
-		return EventData{}, fmt.Errorf("failed to parse event details: %v", err)
+		return EventData{}, fmt.Errorf("failed to fetch event details: %v", err)
 	}
 
-	return eventData, nil
+	return *eventData, nil
 }
 
 func GetEventStartEndTime(eventData EventData, today time.Time, location *time.Location) (time.Time, time.Time, error) {
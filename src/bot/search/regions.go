@@ -2,12 +2,14 @@ package search
 
 import (
 	"encoding/csv"
-	"fmt"
 	"os"
 
+	"github.com/shuban-789/bjorn/src/bot/logging"
 	"github.com/shuban-789/bjorn/src/bot/util"
 )
 
+var logger = logging.For("search")
+
 type RegionInfo struct {
 	Code 	 string
 	Name string
@@ -34,14 +36,14 @@ func GetRegionsData() []RegionInfo {
 	}
 	file, err := os.Open("src/bot/data/regions.csv")
 	if err != nil {
-		fmt.Println(util.Fail("Failed to open regions data file: %v", err))
+		logger.Error("Failed to open regions data file", "err", err)
 	}
 	defer file.Close()
 
 	reader := csv.NewReader(file)
 	lines, err := reader.ReadAll()
 	if err != nil {
-		fmt.Println(util.Fail("Failed to read regions data file via csv reader: %v", err))
+		logger.Error("Failed to read regions data file via csv reader", "err", err)
 	}
 
 	for _, line := range lines {
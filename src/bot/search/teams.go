@@ -1,14 +1,12 @@
 package search
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"fmt"
-	"io"
-	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
 	"github.com/shuban-789/bjorn/src/bot/util"
 )
 
@@ -30,15 +28,14 @@ func SearchTeamNames(query string, maxResults int, regionCode string) ([]TeamInf
 	return util.TokenizedSearch(teams[regionCode], query, maxResults), nil
 }
 
-func GetSDTeamNameFromNumber(teamNumber string) (string, error) {
-	teams := FetchTeams()
-
-	for _, team := range teams["USCASD"] { // hardcode to uscasd for now
-		num, err := strconv.Atoi(teamNumber)
-		if err != nil {
-			return "", errors.New("invalid team number")
-		}
+func GetTeamNameFromNumber(teamNumber string, regionCode string) (string, error) {
+	num, err := strconv.Atoi(teamNumber)
+	if err != nil {
+		return "", errors.New("invalid team number")
+	}
 
+	teams := FetchTeams()
+	for _, team := range teams[regionCode] {
 		if team.Number == num {
 			return team.Name, nil
 		}
@@ -46,6 +43,24 @@ func GetSDTeamNameFromNumber(teamNumber string) (string, error) {
 	return "", errors.New("team number not found")
 }
 
+// GetTeamNameFromAnyRegion looks for the team in every region's team list, for teams
+// outside of a server's home region
+func GetTeamNameFromAnyRegion(teamNumber string) (string, error) {
+	num, err := strconv.Atoi(teamNumber)
+	if err != nil {
+		return "", errors.New("invalid team number")
+	}
+
+	for _, teams := range FetchTeams() {
+		for _, team := range teams {
+			if team.Number == num {
+				return team.Name, nil
+			}
+		}
+	}
+	return "", errors.New("team number not found")
+}
+
 var lastTeamDataFetch time.Time
 
 func FetchTeams() map[string][]TeamInfo {
@@ -57,37 +72,21 @@ func FetchTeams() map[string][]TeamInfo {
 		teamNames = make(map[string][]TeamInfo)
 	}
 
-	fmt.Println(util.Info("Fetching teams data from API..."))
-	api := "https://api.ftcscout.org/rest/v1/teams/search?region="
-
+	logger.Info("Fetching teams data from API")
 	for _, region := range GetRegionsData() {
-		fullApi := api + region.Code
-		resp, err := http.Get(fullApi)
+		results, err := ftcscout.Default.SearchTeams(context.Background(), region.Code)
 		if err != nil {
-			fmt.Println(util.Fail("Failed to fetch teams data from API for region %s: %v", region.Code, err))
-			continue
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			fmt.Println(util.Fail("Teams API returned status code: %d for region %s", resp.StatusCode, region.Code))
+			logger.Error("Failed to fetch teams data from API", "region", region.Code, "err", err)
 			continue
 		}
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			fmt.Println(util.Fail("Failed to read teams response body: %v", err))
-			return nil
-		}
-
-		var teams []TeamInfo
-		if err := json.Unmarshal(body, &teams); err != nil {
-			fmt.Println(util.Fail("Failed to parse teams JSON response: %v", err))
-			return nil
-		}
-
-		for i := range teams {
-			teams[i].Tokens = util.GenerateNormalizedTokens(strconv.Itoa(teams[i].Number) + " " + teams[i].Name)
+		teams := make([]TeamInfo, 0, len(results))
+		for _, team := range results {
+			teams = append(teams, TeamInfo{
+				Name:   team.Name,
+				Number: team.Number,
+				Tokens: util.GenerateNormalizedTokens(strconv.Itoa(team.Number) + " " + team.Name),
+			})
 		}
 
 		teamNames[region.Code] = teams
@@ -96,14 +95,15 @@ func FetchTeams() map[string][]TeamInfo {
 	return teamNames
 }
 
-func startTeamFetcher() {
-	go func() {
-		for {
-			FetchTeams()
-			fmt.Println(util.Info("Team data refreshed"))
-			time.Sleep(20 * 24 * time.Hour) // refresh every 10 days, this lowk shouldn't change more than once a year
+// RunTeamFetcher keeps the team data fresh until ctx is cancelled
+func RunTeamFetcher(ctx context.Context) {
+	for {
+		FetchTeams()
+		logger.Info("Team data refreshed", "regions", len(teamNames))
+		if !sleep(ctx, 20*24*time.Hour) { // refresh every 10 days, this lowk shouldn't change more than once a year
+			return
 		}
-	}();
+	}
 }
 
 func GetTeams() map[string][]TeamInfo{
@@ -120,7 +120,3 @@ func GetTeamCodeFromName(name string, regionCode string) (code int, ok bool) {
 	}
 	return -1, false
 }
-
-func init() {
-	startTeamFetcher()
-}	
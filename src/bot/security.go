@@ -3,16 +3,21 @@ package bot
 import (
 	"fmt"
 	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
 )
 
-func isAdmin(s *discordgo.Session, guildID, userID string) (bool, error) {
+func isAdmin(s interactions.Session, guildID, userID string) (bool, error) {
 	member, err := s.GuildMember(guildID, userID)
 	if err != nil {
 		return false, fmt.Errorf("failed to get guild member: %w", err)
 	}
 
-	guild, err := s.State.Guild(guildID)
-	if err != nil {
+	// use the state cache when we have a real session and only ask discord if the guild isn't cached
+	var guild *discordgo.Guild
+	if session, ok := s.(*discordgo.Session); ok && session.State != nil {
+		guild, _ = session.State.Guild(guildID)
+	}
+	if guild == nil {
 		guild, err = s.Guild(guildID)
 		if err != nil {
 			return false, fmt.Errorf("failed to get guild: %w", err)
@@ -33,4 +38,8 @@ func isAdmin(s *discordgo.Session, guildID, userID string) (bool, error) {
 	}
 
 	return false, nil
-}
\ No newline at end of file
+}
+
+func isOwner(userID string) bool {
+	return OwnerId != "" && userID == OwnerId
+}
@@ -0,0 +1,92 @@
+// This package persists small bits of bot state (tracked events, configs, etc) as JSON files on disk
+// so that they survive crashes, redeploys and /mech restart.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Dir is the folder all stores are written to, set BJORN_DATA_DIR to change it
+// (e.g. to a mounted volume when running in docker)
+var Dir = func() string {
+	if dir := os.Getenv("BJORN_DATA_DIR"); dir != "" {
+		return dir
+	}
+	return "state"
+}()
+
+// Store reads and writes a single value of type T to <Dir>/<name>.json
+type Store[T any] struct {
+	name string
+
+	// guards the file so two goroutines don't write over each other
+	mu sync.Mutex
+}
+
+func New[T any](name string) *Store[T] {
+	return &Store[T]{name: name}
+}
+
+func (s *Store[T]) Path() string {
+	return filepath.Join(Dir, s.name+".json")
+}
+
+// Load returns the stored value, or the zero value of T if nothing has been saved yet
+func (s *Store[T]) Load() (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var value T
+	data, err := os.ReadFile(s.Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return value, nil
+	}
+	if err != nil {
+		return value, fmt.Errorf("failed to read %s: %v", s.Path(), err)
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, fmt.Errorf("failed to parse %s: %v", s.Path(), err)
+	}
+	return value, nil
+}
+
+// Save writes the value to a temp file and renames it over the old one so a crash
+// mid-write never leaves a half written file behind
+func (s *Store[T]) Save(value T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %v", s.name, err)
+	}
+
+	if err := os.MkdirAll(Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create data dir %s: %v", Dir, err)
+	}
+
+	tmp, err := os.CreateTemp(Dir, s.name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for %s: %v", s.name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %v", s.name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %v", s.name, err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.Path()); err != nil {
+		return fmt.Errorf("failed to save %s: %v", s.name, err)
+	}
+	return nil
+}
@@ -1,17 +1,19 @@
 package bot
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
 	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/logging"
 	"github.com/shuban-789/bjorn/src/bot/pagination"
 	"github.com/shuban-789/bjorn/src/bot/presets"
+	"github.com/shuban-789/bjorn/src/bot/provider"
 	"github.com/shuban-789/bjorn/src/bot/util"
 )
 
@@ -26,17 +28,7 @@ var (
 	)
 )
 
-type TeamAward struct {
-	Season       int    `json:"season"`
-	EventCode    string `json:"eventCode"`
-	TeamNumber   int    `json:"teamNumber"`
-	Type         string `json:"type"`
-	Placement    int    `json:"placement"`
-	DivisionName string `json:"divisionName"`
-	PersonName   string `json:"personName"`
-	CreatedAt    string `json:"createdAt"`
-	UpdatedAt    string `json:"updatedAt"`
-}
+type TeamAward = ftcscout.Award
 
 func init() {
 	interactions.RegisterCommand(
@@ -86,9 +78,36 @@ func init() {
 						},
 					},
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "history",
+					Description: "Show the events a team attended in a season.",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "team",
+							Description:  "The FTC team to look up.",
+							Required:     true,
+							Autocomplete: true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "season",
+							Description: "Season to show (defaults to this server's season).",
+							Required:    false,
+							Choices:     interactions.FtcYearChoices,
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "compare",
+					Description: "Compare two to four teams side by side.",
+					Options:     compareTeamOptions(),
+				},
 			},
 		},
-		func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
 			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
 			data := i.ApplicationCommandData()
 			var args []string
@@ -96,7 +115,7 @@ func init() {
 				sub := data.Options[0]
 				subName := sub.Name
 				switch subName {
-				case "info", "stats", "awards":
+				case "info", "stats", "awards", "history":
 					if subName == "info" {
 						subName = ""
 					}
@@ -107,6 +126,16 @@ func init() {
 						return
 					}
 					args = []string{teamID, subName}
+					if subName == "history" {
+						args = append(args, interactions.GetStringOption(sub.Options, "season"))
+					}
+				case "compare":
+					args = []string{"compare"}
+					for n := 1; n <= maxCompareTeams; n++ {
+						if teamID := interactions.GetStringOption(sub.Options, fmt.Sprintf("team%d", n)); teamID != "" {
+							args = append(args, teamID)
+						}
+					}
 				default:
 					interactions.SendMessage(s, i, "", "Unknown subcommand for team.")
 					return
@@ -131,30 +160,42 @@ func init() {
 	interactions.RegisterAutocomplete("team/stats/team", presets.TeamsAutocomplete)
 	interactions.RegisterAutocomplete("team/awards/team", presets.TeamsAutocomplete)
 	interactions.RegisterAutocomplete("team/info/team", presets.TeamsAutocomplete)
+	interactions.RegisterAutocomplete("team/history/team", presets.TeamsAutocomplete)
+	for n := 1; n <= maxCompareTeams; n++ {
+		interactions.RegisterAutocomplete(fmt.Sprintf("team/compare/team%d", n), presets.TeamsAutocomplete)
+	}
 }
 
-type TeamInfo struct {
-	Number     int      `json:"number"`
-	Name       string   `json:"name"`
-	SchoolName string   `json:"schoolName"`
-	Sponsors   []string `json:"sponsors"`
-	Country    string   `json:"country"`
-	State      string   `json:"state"`
-	City       string   `json:"city"`
-	RookieYear int      `json:"rookieYear"`
-	Website    string   `json:"website"`
-	CreatedAt  string   `json:"createdAt"`
-	UpdatedAt  string   `json:"updatedAt"`
+func compareTeamOptions() []*discordgo.ApplicationCommandOption {
+	options := []*discordgo.ApplicationCommandOption{}
+	for n := 1; n <= maxCompareTeams; n++ {
+		options = append(options, &discordgo.ApplicationCommandOption{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         fmt.Sprintf("team%d", n),
+			Description:  fmt.Sprintf("Team number %d to compare.", n),
+			Required:     n <= minCompareTeams,
+			Autocomplete: true,
+		})
+	}
+	return options
 }
 
-// func teamcmd(channelID string, args []string, session *discordgo.Session, i *discordgo.InteractionCreate) {
-func teamcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
+type TeamInfo = ftcscout.Team
+
+// func teamcmd(channelID string, args []string, session interactions.Session, i *discordgo.InteractionCreate) {
+func teamcmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
 	channelID := interactions.GetChannelId(message, i)
 	if len(args) < 1 {
 		interactions.SendMessage(session, i, channelID, "Please provide a team number.")
 		return
 	}
 
+	if args[0] == "compare" {
+		guildID, _ := interactions.GetGuildId(message, i)
+		teamCompare(channelID, args[1:], session, i, guildID)
+		return
+	}
+
 	teamNumber := args[0]
 	if len(args) > 1 && args[1] != "" {
 		subCommand := args[1]
@@ -163,8 +204,15 @@ func teamcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *di
 			teamStats(channelID, teamNumber, session, i)
 		case "awards":
 			teamAwards(channelID, teamNumber, session, i)
+		case "history":
+			season := ""
+			if len(args) > 2 {
+				season = args[2]
+			}
+			guildID, _ := interactions.GetGuildId(message, i)
+			teamHistory(channelID, guildID, teamNumber, guildconfig.SeasonOrDefault(guildID, season), session, i)
 		default:
-			interactions.SendMessage(session, i, channelID, "Unknown subcommand. Use 'stats' or 'awards'.")
+			interactions.SendMessage(session, i, channelID, "Unknown subcommand. Use 'stats', 'awards' or 'history'.")
 		}
 	} else {
 		showTeamInfo(channelID, teamNumber, session, i)
@@ -172,50 +220,26 @@ func teamcmd(session *discordgo.Session, message *discordgo.MessageCreate, i *di
 }
 
 func fetchTeamInfo(teamNumber string) (*TeamInfo, error) {
-	url := fmt.Sprintf("https://api.ftcscout.org/rest/v1/teams/%s", teamNumber)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch info for Team %s: %v", teamNumber, err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response for Team %s: %v", teamNumber, err)
+	team, err := provider.Default().Team(context.Background(), teamNumber)
+	if provider.IsNotFound(err) {
+		return nil, fmt.Errorf("team %s does not exist", teamNumber)
 	}
-
-	var team TeamInfo
-	err = json.Unmarshal(body, &team)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse info for Team %s: %v", teamNumber, err)
+		return nil, fmt.Errorf("failed to fetch info for Team %s: %v", teamNumber, err)
 	}
-
-	return &team, nil
+	return team, nil
 }
 
 func fetchTeamAwards(teamNumber string) ([]TeamAward, error) {
-	url := fmt.Sprintf("https://api.ftcscout.org/rest/v1/teams/%s/awards", teamNumber)
-	resp, err := http.Get(url)
+	awards, err := provider.Default().TeamAwards(context.Background(), teamNumber)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch awards for Team %s: %v", teamNumber, err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response for Team %s: %v", teamNumber, err)
-	}
-
-	var awards []TeamAward
-	err = json.Unmarshal(body, &awards)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse awards for Team %s: %v", teamNumber, err)
-	}
 	return awards, nil
 }
 
 // Default FTCScout API
-func showTeamInfo(channelID string, teamNumber string, session *discordgo.Session, i *discordgo.InteractionCreate) {
+func showTeamInfo(channelID string, teamNumber string, session interactions.Session, i *discordgo.InteractionCreate) {
 	team, err := fetchTeamInfo(teamNumber)
 	if err != nil {
 		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Error: %v", err))
@@ -245,55 +269,18 @@ func showTeamInfo(channelID string, teamNumber string, session *discordgo.Sessio
 }
 
 // Stats FTCScout API
-func teamStats(channelID string, teamNumber string, session *discordgo.Session, i *discordgo.InteractionCreate) {
+func teamStats(channelID string, teamNumber string, session interactions.Session, i *discordgo.InteractionCreate) {
 	team, err := fetchTeamInfo(teamNumber)
 	if err != nil {
 		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Error: %v", err))
 		return
 	}
 
-	type TeamStats struct {
-		Season int `json:"season"`
-		Number int `json:"number"`
-		Tot    struct {
-			Value float64 `json:"value"`
-			Rank  int     `json:"rank"`
-		} `json:"tot"`
-		Auto struct {
-			Value float64 `json:"value"`
-			Rank  int     `json:"rank"`
-		} `json:"auto"`
-		Dc struct {
-			Value float64 `json:"value"`
-			Rank  int     `json:"rank"`
-		} `json:"dc"`
-		Eg struct {
-			Value float64 `json:"value"`
-			Rank  int     `json:"rank"`
-		} `json:"eg"`
-		Count int `json:"count"`
-	}
-
-	url := fmt.Sprintf("https://api.ftcscout.org/rest/v1/teams/%s/quick-stats", teamNumber)
-	resp, err := http.Get(url)
+	stats, err := ftcscout.Default.TeamQuickStats(context.Background(), teamNumber)
 	if err != nil {
 		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to fetch stats for Team %s: %v", teamNumber, err))
 		return
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to read response for Team %s: %v", teamNumber, err))
-		return
-	}
-
-	var stats TeamStats
-	err = json.Unmarshal(body, &stats)
-	if err != nil {
-		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to parse stats for Team %s: %v", teamNumber, err))
-		return
-	}
 
 	embed := &discordgo.MessageEmbed{
 		Title:       fmt.Sprintf("Stats for Team %d (%s)", team.Number, team.Name),
@@ -333,7 +320,7 @@ func teamStats(channelID string, teamNumber string, session *discordgo.Session,
 }
 
 // Awards FTCScout API
-func teamAwards(channelID string, teamNumber string, session *discordgo.Session, i *discordgo.InteractionCreate) {
+func teamAwards(channelID string, teamNumber string, session interactions.Session, i *discordgo.InteractionCreate) {
 	team, err := fetchTeamInfo(teamNumber) // Reuse fetchTeamInfo to get the team name
 	if err != nil {
 		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Error: %v", err))
@@ -343,7 +330,7 @@ func teamAwards(channelID string, teamNumber string, session *discordgo.Session,
 	extraData := map[string]string{"teamNumber": fmt.Sprintf("%d", team.Number)}
 	err = awardsPaginator.Setup(session, i, channelID, extraData, team.Name)
 	if err != nil {
-		fmt.Println(util.Fail(err.Error()))
+		logging.WithInteraction(commandLog, i).Error("Failed to set up awards paginator", "team", teamNumber, "err", err)
 		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Failed to setup awards paginator for Team %s: %v", teamNumber, err))
 		return
 	}
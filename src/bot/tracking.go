@@ -0,0 +1,128 @@
+// Persistence for the match tracker so /match eventstart survives crashes, redeploys and /mech restart.
+package bot
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+var (
+	trackedEventsStore = store.New[[]EventTracked]("tracked_events")
+
+	// eventsBeingTracked is touched by both the updater goroutine and command handlers
+	trackedMu sync.Mutex
+)
+
+// loadTrackedEvents replaces the in-memory tracked events with whatever was last saved.
+// It replaces instead of appending so calling Deploy again doesn't track everything twice.
+func loadTrackedEvents() {
+	events, err := trackedEventsStore.Load()
+	if err != nil {
+		trackerLog.Error("Failed to load tracked events", "path", trackedEventsStore.Path(), "err", err)
+		return
+	}
+
+	for i := range events {
+		events[i].Tournament.ensureMaps()
+	}
+
+	trackedMu.Lock()
+	eventsBeingTracked = events
+	trackedMu.Unlock()
+
+	trackerLog.Info("Loaded tracked events", "count", len(events), "path", trackedEventsStore.Path())
+	for _, event := range events {
+		eventLog(&event).Info("Tracking event", "channel", event.UpdateChannelId, "lastMatch", event.LastProcessedMatchId)
+	}
+}
+
+// clone copies an event along with its maps, so the tracker can work on it without holding trackedMu
+func (event EventTracked) clone() EventTracked {
+	event.CachedMatches = slices.Clone(event.CachedMatches)
+	event.NotifiedAwards = maps.Clone(event.NotifiedAwards)
+	event.Tournament.WinnersBracket = maps.Clone(event.Tournament.WinnersBracket)
+	event.Tournament.LosersBracket = maps.Clone(event.Tournament.LosersBracket)
+	event.Tournament.Eliminated = maps.Clone(event.Tournament.Eliminated)
+	event.Tournament.MatchHistory = slices.Clone(event.Tournament.MatchHistory)
+	return event
+}
+
+// sameTrackedEvent is how tracked events are told apart, an event can be tracked once per channel
+func sameTrackedEvent(a, b *EventTracked) bool {
+	return a.Year == b.Year && a.EventCode == b.EventCode && a.UpdateChannelId == b.UpdateChannelId
+}
+
+// storeTrackedEvent writes a polled copy of an event back and saves it. Nothing happens if the event
+// stopped being tracked in the meantime.
+func storeTrackedEvent(event EventTracked) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	for idx := range eventsBeingTracked {
+		if sameTrackedEvent(&eventsBeingTracked[idx], &event) {
+			// keep the poll time from the list, it's set when the copy is taken
+			event.LastUpdateTime = eventsBeingTracked[idx].LastUpdateTime
+			eventsBeingTracked[idx] = event.clone()
+			saveTrackedEvents()
+			return
+		}
+	}
+}
+
+func removeTrackedEvent(event EventTracked) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	eventsBeingTracked = slices.DeleteFunc(eventsBeingTracked, func(tracked EventTracked) bool {
+		return sameTrackedEvent(&tracked, &event)
+	})
+	saveTrackedEvents()
+}
+
+// saveTrackedEvents writes the current tracked events to disk, callers must hold trackedMu
+func saveTrackedEvents() {
+	if err := trackedEventsStore.Save(eventsBeingTracked); err != nil {
+		trackerLog.Error("Failed to save tracked events", "path", trackedEventsStore.Path(), "err", err)
+	}
+}
+
+// saveTrackedEventsOnStop is the last save before shutting down. Stop gives up on stuck workers and one of them
+// could be holding trackedMu, every change is saved while the lock is held so skipping this only loses poll times.
+func saveTrackedEventsOnStop() {
+	if !trackedMu.TryLock() {
+		trackerLog.Warn("Tracked events are locked by a worker that didn't stop, keeping the last save")
+		return
+	}
+	defer trackedMu.Unlock()
+	saveTrackedEvents()
+}
+
+// maps with struct keys can't be json keys, so alliances are written as "captain-firstpick-color"
+func (a TwoTeamAlliance) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d-%d-%d", a.Captain, a.FirstPick, a.Color)), nil
+}
+
+func (a *TwoTeamAlliance) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d-%d-%d", &a.Captain, &a.FirstPick, &a.Color)
+	if err != nil {
+		return fmt.Errorf("invalid alliance key %q: %v", string(text), err)
+	}
+	return nil
+}
+
+// old saves (or events with no playoff matches yet) can have nil maps
+func (t *DoubleElimTournament) ensureMaps() {
+	if t.WinnersBracket == nil {
+		t.WinnersBracket = make(map[TwoTeamAlliance]int)
+	}
+	if t.LosersBracket == nil {
+		t.LosersBracket = make(map[TwoTeamAlliance]int)
+	}
+	if t.Eliminated == nil {
+		t.Eliminated = make(map[TwoTeamAlliance]bool)
+	}
+}
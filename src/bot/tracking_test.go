@@ -0,0 +1,98 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+)
+
+// run with -race, polls work on copies and only write back through storeTrackedEvent
+func TestPollWhileStartingEvents(t *testing.T) {
+	// every upcoming match is on deck, so the poll has plenty to note while events are being started
+	matches := []ftcscout.Match{fakeQual(1, true)}
+	for id := 2; id <= 21; id++ {
+		matches = append(matches, fakeQual(id, false))
+	}
+	fakeEvent(t, "USPOLLQ1", 0, 0, matches)
+	lead := 20
+	if err := guildconfig.Update("poll-guild", func(cfg *guildconfig.Config) { cfg.OnDeckMatches = &lead }); err != nil {
+		t.Fatalf("guildconfig.Update: %v", err)
+	}
+	session := sessiontest.New()
+	eventStart("updates", "poll-guild", "2025", "USPOLLQ1", true, session, nil)
+	trackedEvent(t, "USPOLLQ1")
+
+	var codes []string
+	for n := 2; n <= 30; n++ {
+		code := fmt.Sprintf("USPOLLQ%d", n)
+		fakeEvent(t, code, 3, 3, []ftcscout.Match{fakeQual(1, false)})
+		trackedEvent(t, code)
+		codes = append(codes, code)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 5 {
+			eventUpdate(context.Background(), 0, session)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for _, code := range codes {
+			eventStart("channel", "poll-guild", "2025", code, false, session, nil)
+		}
+	}()
+	wg.Wait()
+
+	polled, ok := trackedEvent(t, "USPOLLQ1")
+	if !ok {
+		t.Fatal("the polled event stopped being tracked")
+	}
+	if polled.LastProcessedMatchId != 1 || polled.LastOnDeckMatchId != 21 {
+		t.Errorf("tracked %+v, want match 1 posted and every match on deck noted", polled)
+	}
+
+	// everything the poll changed made it to disk, along with every started event
+	saved, err := trackedEventsStore.Load()
+	if err != nil {
+		t.Fatalf("loading tracked events: %v", err)
+	}
+	found := map[string]EventTracked{}
+	for _, event := range saved {
+		found[event.EventCode] = event
+	}
+	if found["USPOLLQ1"].LastProcessedMatchId != 1 || found["USPOLLQ1"].LastOnDeckMatchId != polled.LastOnDeckMatchId {
+		t.Errorf("saved %+v, want the poll's progress", found["USPOLLQ1"])
+	}
+	for _, code := range codes {
+		if _, ok := found[code]; !ok {
+			t.Errorf("%s wasn't saved", code)
+		}
+	}
+}
+
+func TestSaveOnStopDoesNotWaitForStuckWorker(t *testing.T) {
+	// a worker that didn't stop in time is still holding the lock
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		saveTrackedEventsOnStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the shutdown save is waiting on trackedMu")
+	}
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/golang-lru/v2/expirable"
@@ -28,6 +29,17 @@ type Cache[V any] struct {
 
 	// function that fetches value when not present in cache
 	fetch FetchFunc[V]
+
+	// counted for the metrics endpoint
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// CacheStats is how well a cache is doing, Size is how many values are in it right now
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
 }
 
 // NewCache creates a new Cache with the given max size, persistence duration, and fetch function
@@ -45,9 +57,11 @@ func (c *Cache[V]) GetOrFetch(key string) (V, error) {
 	c.mu.Lock()
 	if val, exists := c.lru.Get(key); exists {
 		c.mu.Unlock()
+		c.hits.Add(1)
 		return val, nil
 	}
 	c.mu.Unlock()
+	c.misses.Add(1)
 
 	// note: we let go of the lock while fetching to avoid blocking other operations
 	// also here we basically index by team num, so if it sees one team num is there it doesn't repeat the request
@@ -73,3 +87,13 @@ func (c *Cache[V]) Set(key string, value V) {
 	defer c.mu.Unlock()
 	c.lru.Add(key, value)
 }
+
+func (c *Cache[V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Size:   c.lru.Len(),
+	}
+}
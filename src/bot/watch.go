@@ -0,0 +1,292 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/presets"
+	"github.com/shuban-789/bjorn/src/bot/provider"
+	"github.com/shuban-789/bjorn/src/bot/search"
+	"github.com/shuban-789/bjorn/src/bot/watchlist"
+)
+
+// the same match can be picked up by several servers tracking the event,
+// this makes sure watchers still only get one DM for it
+var (
+	watchNotified   = make(map[string]time.Time)
+	watchNotifiedMu sync.Mutex
+)
+
+const watchNotifiedTTL = 48 * time.Hour
+
+func init() {
+	teamOption := []*discordgo.ApplicationCommandOption{
+		{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "team",
+			Description:  "The FTC team number.",
+			Required:     true,
+			Autocomplete: true,
+		},
+	}
+
+	interactions.RegisterCommand(
+		&discordgo.ApplicationCommand{
+			Name:        "watch",
+			Description: "Get DMs when a team plays a match or wins an award.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "add",
+					Description: "Start watching a team.",
+					Options:     teamOption,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "remove",
+					Description: "Stop watching a team.",
+					Options:     teamOption,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "list",
+					Description: "Show the teams you're watching.",
+				},
+			},
+		},
+		func(s interactions.Session, i *discordgo.InteractionCreate) {
+			_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
+			data := i.ApplicationCommandData()
+			if len(data.Options) == 0 {
+				interactions.SendMessage(s, i, "", "Please provide a subcommand for watch.")
+				return
+			}
+
+			sub := data.Options[0]
+			args := []string{sub.Name}
+			if team := interactions.GetStringOption(sub.Options, "team"); team != "" {
+				args = append(args, team)
+			}
+			watchcmd(s, nil, i, args)
+		},
+	)
+
+	interactions.RegisterAutocomplete("watch/add/team", presets.TeamsAutocomplete)
+	interactions.RegisterAutocomplete("watch/remove/team", presets.TeamsAutocomplete)
+}
+
+func watchcmd(session interactions.Session, message *discordgo.MessageCreate, i *discordgo.InteractionCreate, args []string) {
+	channelID := interactions.GetChannelId(message, i)
+	userID, ok := interactions.GetAuthorId(message, i)
+	if !ok {
+		interactions.SendMessage(session, i, channelID, "Couldn't figure out who you are.")
+		return
+	}
+	// watches follow the user everywhere, but the prefix is still the one for wherever they typed it
+	guildID, _ := interactions.GetGuildId(message, i)
+	prefix := guildconfig.Get(guildID).CommandPrefix
+	if len(args) < 1 {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Usage: `%swatch <add|remove|list> [team]`", prefix))
+		return
+	}
+
+	switch args[0] {
+	case "add", "remove":
+		if len(args) < 2 {
+			interactions.SendMessage(session, i, channelID, fmt.Sprintf("Usage: `%swatch %s <team>`", prefix, args[0]))
+			return
+		}
+		team, err := strconv.Atoi(args[1])
+		if err != nil {
+			interactions.SendMessage(session, i, channelID, "Please provide a valid team number.")
+			return
+		}
+
+		if args[0] == "add" {
+			watchAdd(channelID, userID, team, session, i)
+		} else {
+			watchRemove(channelID, userID, team, session, i)
+		}
+	case "list":
+		watchList(channelID, userID, session, i)
+	default:
+		interactions.SendMessage(session, i, channelID, "Unknown subcommand. Use 'add', 'remove' or 'list'.")
+	}
+}
+
+func watchAdd(channelID, userID string, team int, session interactions.Session, i *discordgo.InteractionCreate) {
+	info, err := fetchTeamInfo(strconv.Itoa(team))
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	added, err := watchlist.Add(userID, team)
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Couldn't watch Team %d: %v", team, err))
+		return
+	}
+	if !added {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("You're already watching Team %d (%s).", team, info.Name))
+		return
+	}
+	interactions.SendMessage(session, i, channelID, fmt.Sprintf("Now watching Team %d (%s). You'll get a DM when they play a match or win an award at a tracked event, so make sure your DMs are open!", team, info.Name))
+}
+
+func watchRemove(channelID, userID string, team int, session interactions.Session, i *discordgo.InteractionCreate) {
+	removed, err := watchlist.Remove(userID, team)
+	if err != nil {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("Couldn't stop watching Team %d: %v", team, err))
+		return
+	}
+	if !removed {
+		interactions.SendMessage(session, i, channelID, fmt.Sprintf("You weren't watching Team %d.", team))
+		return
+	}
+	interactions.SendMessage(session, i, channelID, fmt.Sprintf("Stopped watching Team %d.", team))
+}
+
+func watchList(channelID, userID string, session interactions.Session, i *discordgo.InteractionCreate) {
+	teams := watchlist.Teams(userID)
+	if len(teams) == 0 {
+		interactions.SendMessage(session, i, channelID, "You aren't watching any teams. Use `/watch add` to start.")
+		return
+	}
+
+	lines := []string{}
+	for _, team := range teams {
+		if name, err := search.GetTeamNameFromAnyRegion(strconv.Itoa(team)); err == nil {
+			lines = append(lines, fmt.Sprintf("• **%d** %s", team, name))
+		} else {
+			lines = append(lines, fmt.Sprintf("• **%d**", team))
+		}
+	}
+
+	interactions.SendEmbed(session, i, channelID, &discordgo.MessageEmbed{
+		Title:       "Your Watched Teams",
+		Description: strings.Join(lines, "\n"),
+		Color:       0x72cfdd,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%d of %d teams", len(teams), watchlist.MaxTeams),
+		},
+	})
+}
+
+// markWatchNotified returns false if this notification already went out recently
+func markWatchNotified(key string) bool {
+	watchNotifiedMu.Lock()
+	defer watchNotifiedMu.Unlock()
+
+	now := time.Now()
+	for oldKey, sentAt := range watchNotified {
+		if now.Sub(sentAt) > watchNotifiedTTL {
+			delete(watchNotified, oldKey)
+		}
+	}
+
+	if _, ok := watchNotified[key]; ok {
+		return false
+	}
+	watchNotified[key] = now
+	return true
+}
+
+func sendWatchDM(session interactions.Session, userID string, content string, embed *discordgo.MessageEmbed) {
+	channel, err := session.UserChannelCreate(userID)
+	if err != nil {
+		trackerLog.Error("Failed to open DM with watcher", "user", userID, "err", err)
+		return
+	}
+
+	// this fails when someone has DMs from server members turned off, nothing we can do about that
+	_, err = session.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+		Content: content,
+		Embeds:  []*discordgo.MessageEmbed{embed},
+	})
+	if err != nil {
+		trackerLog.Warn("Failed to DM watcher", "user", userID, "err", err)
+	}
+}
+
+func formatTeamNumbers(teams []int) string {
+	sort.Ints(teams)
+	names := []string{}
+	for _, team := range teams {
+		names = append(names, strconv.Itoa(team))
+	}
+	if len(names) == 1 {
+		return "Team " + names[0]
+	}
+	return "Teams " + strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// notifyMatchWatchers DMs the result of a played match to everyone watching a team in it
+func notifyMatchWatchers(session interactions.Session, event *EventTracked, match ftcscout.Match) {
+	teams := []int{}
+	for _, team := range match.Teams {
+		teams = append(teams, team.TeamNumber)
+	}
+
+	watchers := watchlist.Watchers(teams...)
+	if len(watchers) == 0 {
+		return
+	}
+
+	embed := createMatchResultEmbed(event.EventCode, match)
+	for userID, watched := range watchers {
+		if !markWatchNotified(fmt.Sprintf("%s %s match %d %s", event.Year, event.EventCode, match.ID, userID)) {
+			continue
+		}
+		content := fmt.Sprintf("%s just played %s at %s.", formatTeamNumbers(watched), getMatchName(match), event.EventCode)
+		sendWatchDM(session, userID, content, embed)
+	}
+}
+
+// notifyAwardWatchers DMs new awards at a tracked event to anyone watching the winners.
+// Awards already sent for this event are remembered in NotifiedAwards so they don't go out again after a restart,
+// event is the tracker's copy and it's saved by the caller.
+func notifyAwardWatchers(session interactions.Session, event *EventTracked, dataProvider provider.Provider) {
+	// no point asking for awards every poll if nobody would get them
+	if watchlist.Empty() {
+		return
+	}
+
+	awards, err := dataProvider.EventAwards(context.Background(), event.Year, event.EventCode)
+	if err != nil {
+		eventLog(event).Error("Failed to fetch awards", "err", err)
+		return
+	}
+
+	if event.NotifiedAwards == nil {
+		event.NotifiedAwards = make(map[string]bool)
+	}
+
+	for _, award := range awards {
+		awardKey := fmt.Sprintf("%s %d %d", award.Type, award.Placement, award.TeamNumber)
+		if event.NotifiedAwards[awardKey] {
+			continue
+		}
+		event.NotifiedAwards[awardKey] = true
+
+		embed := &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("🏆 %s", award.Type),
+			Description: fmt.Sprintf("Team %d won **%s** (#%d) at %s!", award.TeamNumber, award.Type, award.Placement, event.EventCode),
+			Color:       0xf1c40f,
+		}
+		for userID := range watchlist.Watchers(award.TeamNumber) {
+			if !markWatchNotified(fmt.Sprintf("%s %s award %s %s", event.Year, event.EventCode, awardKey, userID)) {
+				continue
+			}
+			sendWatchDM(session, userID, fmt.Sprintf("Team %d won an award at %s!", award.TeamNumber, event.EventCode), embed)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/shuban-789/bjorn/src/bot/ftcscout"
+	"github.com/shuban-789/bjorn/src/bot/guildconfig"
+	"github.com/shuban-789/bjorn/src/bot/interactions"
+	"github.com/shuban-789/bjorn/src/bot/interactions/sessiontest"
+	"github.com/shuban-789/bjorn/src/bot/watchlist"
+)
+
+func watchInteraction(userID, subcommand string, options ...string) *discordgo.InteractionCreate {
+	return sessiontest.NewInteraction("watch-guild", userID, discordgo.ApplicationCommandInteractionData{
+		Name: "watch",
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{{
+			Name:    subcommand,
+			Type:    discordgo.ApplicationCommandOptionSubCommand,
+			Options: stringOptions(options...),
+		}},
+	})
+}
+
+// watchTeam makes 16271 a team fakeData knows about and forgets whatever userID watched when the test ends
+func watchTeam(t *testing.T, userID string) {
+	t.Helper()
+	fakeData.teams["16271"] = &ftcscout.Team{Number: 16271, Name: "Roboknights"}
+	t.Cleanup(func() {
+		delete(fakeData.teams, "16271")
+		for _, team := range watchlist.Teams(userID) {
+			watchlist.Remove(userID, team)
+		}
+	})
+}
+
+func TestWatchCommand(t *testing.T) {
+	watchTeam(t, "watcher")
+	session := sessiontest.New()
+	watch := interactions.CommandHandlers["watch"]
+
+	watch(session, watchInteraction("watcher", "add", "team", "16271"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Now watching Team 16271 (Roboknights).") {
+		t.Errorf("add got %q", got)
+	}
+	watch(session, watchInteraction("watcher", "add", "team", "16271"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "You're already watching Team 16271 (Roboknights).") {
+		t.Errorf("second add got %q", got)
+	}
+
+	watch(session, watchInteraction("watcher", "list"))
+	embeds := session.Embeds()
+	if len(embeds) != 1 || embeds[0].Title != "Your Watched Teams" || !strings.Contains(embeds[0].Description, "**16271**") {
+		t.Fatalf("list sent %+v", embeds)
+	}
+	if !strings.HasPrefix(embeds[0].Footer.Text, "1 of 10 teams") {
+		t.Errorf("list footer = %q", embeds[0].Footer.Text)
+	}
+
+	watch(session, watchInteraction("watcher", "remove", "team", "16271"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Stopped watching Team 16271.") {
+		t.Errorf("remove got %q", got)
+	}
+	if teams := watchlist.Teams("watcher"); len(teams) != 0 {
+		t.Errorf("still watching %v", teams)
+	}
+}
+
+func TestWatchCommandErrors(t *testing.T) {
+	watchTeam(t, "watch-errors")
+	session := sessiontest.New()
+	watch := interactions.CommandHandlers["watch"]
+
+	watch(session, watchInteraction("watch-errors", "add", "team", "99999"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Error: team 99999 does not exist") {
+		t.Errorf("unknown team got %q", got)
+	}
+	watch(session, watchInteraction("watch-errors", "remove", "team", "16271"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "You weren't watching Team 16271.") {
+		t.Errorf("remove without watching got %q", got)
+	}
+	watch(session, watchInteraction("watch-errors", "list"))
+	if got := lastContent(t, session); !strings.HasPrefix(got, "You aren't watching any teams.") {
+		t.Errorf("empty list got %q", got)
+	}
+
+	// text commands show the server's own prefix in the usage
+	if err := guildconfig.Update("watch-text-guild", func(cfg *guildconfig.Config) { cfg.CommandPrefix = "!" }); err != nil {
+		t.Fatal(err)
+	}
+	message := sessiontest.NewMessage("watch-text-guild", "channel", "watch-errors", "!watch")
+	watchcmd(session, message, nil, nil)
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Usage: `!watch <add|remove|list> [team]`") {
+		t.Errorf("no subcommand got %q", got)
+	}
+	watchcmd(session, message, nil, []string{"add", "sixteen"})
+	if got := lastContent(t, session); !strings.HasPrefix(got, "Please provide a valid team number.") {
+		t.Errorf("bad team got %q", got)
+	}
+}
+
+func TestNotifyMatchWatchers(t *testing.T) {
+	watchTeam(t, "dm-watcher")
+	if _, err := watchlist.Add("dm-watcher", 16271); err != nil {
+		t.Fatal(err)
+	}
+	event := &EventTracked{Year: "2025", EventCode: "USWATCHQ1"}
+
+	session := sessiontest.New()
+	notifyMatchWatchers(session, event, fakeQual(1, true))
+	sent := session.Sent()
+	if len(sent) != 1 || sent[0].ChannelID != "dm-dm-watcher" || !strings.HasPrefix(sent[0].Content, "Team 16271 just played Qualification 1 at USWATCHQ1.") {
+		t.Fatalf("sent %+v, want one DM about the match", sent)
+	}
+
+	// another server tracking the same event doesn't DM it again
+	notifyMatchWatchers(session, event, fakeQual(1, true))
+	if len(session.Sent()) != 1 {
+		t.Errorf("the match was DMed again: %+v", session.Sent())
+	}
+
+	// closed DMs are logged and skipped
+	closed := sessiontest.New()
+	closed.Errors["UserChannelCreate"] = errors.New("cannot send messages to this user")
+	notifyMatchWatchers(closed, event, fakeQual(2, true))
+	if len(closed.Sent()) != 0 {
+		t.Errorf("sent %+v without a DM channel", closed.Sent())
+	}
+}
@@ -0,0 +1,144 @@
+// Package watchlist keeps track of which teams each user wants DMs about.
+// Subscriptions belong to the user rather than a server, so they follow them across every guild Bjorn is in.
+package watchlist
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/shuban-789/bjorn/src/bot/logging"
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+var logger = logging.For("watchlist")
+
+// MaxTeams is how many teams one user can watch, mostly so nobody subscribes to a whole event
+const MaxTeams = 10
+
+var (
+	watchStore = store.New[map[string][]int]("watchlist")
+
+	// user ID -> watched team numbers, kept sorted
+	watches map[string][]int
+	mu      sync.Mutex
+
+	// set when the stored watchlist couldn't be read, saving then would wipe everyone else's teams
+	loadErr error
+)
+
+func load() {
+	if watches != nil {
+		return
+	}
+
+	loaded, err := watchStore.Load()
+	loadErr = err
+	if err != nil {
+		logger.Error("Failed to load watchlist", "err", err)
+	}
+	if loaded == nil {
+		loaded = make(map[string][]int)
+	}
+	watches = loaded
+}
+
+// loadForSave is load for anything that saves, it tries again after a failed load and
+// returns an error instead if the watchlist still can't be read
+func loadForSave() error {
+	if loadErr != nil {
+		watches = nil
+	}
+	load()
+	if loadErr != nil {
+		return fmt.Errorf("the watchlist couldn't be loaded so nothing was saved: %w", loadErr)
+	}
+	return nil
+}
+
+// Add starts watching the team for the user, returning false if they were already watching it
+func Add(userID string, team int) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if err := loadForSave(); err != nil {
+		return false, err
+	}
+
+	teams := watches[userID]
+	for _, watched := range teams {
+		if watched == team {
+			return false, nil
+		}
+	}
+	if len(teams) >= MaxTeams {
+		return false, fmt.Errorf("you can watch at most %d teams, remove one first", MaxTeams)
+	}
+
+	teams = append(teams, team)
+	sort.Ints(teams)
+	watches[userID] = teams
+	return true, watchStore.Save(watches)
+}
+
+// Remove stops watching the team, returning false if the user wasn't watching it
+func Remove(userID string, team int) (bool, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if err := loadForSave(); err != nil {
+		return false, err
+	}
+
+	teams := watches[userID]
+	for idx, watched := range teams {
+		if watched == team {
+			teams = append(teams[:idx], teams[idx+1:]...)
+			if len(teams) == 0 {
+				delete(watches, userID)
+			} else {
+				watches[userID] = teams
+			}
+			return true, watchStore.Save(watches)
+		}
+	}
+	return false, nil
+}
+
+// Teams returns the teams the user is watching, lowest number first
+func Teams(userID string) []int {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+
+	return append([]int(nil), watches[userID]...)
+}
+
+// Watchers returns every user watching any of the teams, mapped to which of those teams they watch
+func Watchers(teams ...int) map[string][]int {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+
+	wanted := make(map[int]bool)
+	for _, team := range teams {
+		wanted[team] = true
+	}
+
+	result := make(map[string][]int)
+	for userID, watched := range watches {
+		for _, team := range watched {
+			if wanted[team] {
+				result[userID] = append(result[userID], team)
+			}
+		}
+	}
+	return result
+}
+
+// Empty is true when nobody is watching anything, so the tracker can skip fetching awards
+func Empty() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+
+	return len(watches) == 0
+}
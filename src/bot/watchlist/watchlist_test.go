@@ -0,0 +1,43 @@
+package watchlist
+
+import (
+	"os"
+	"testing"
+
+	"github.com/shuban-789/bjorn/src/bot/store"
+)
+
+func TestAddRefusesToSaveAfterFailedLoad(t *testing.T) {
+	dir := store.Dir
+	store.Dir = t.TempDir()
+	watches, loadErr = nil, nil
+	t.Cleanup(func() {
+		store.Dir = dir
+		watches, loadErr = nil, nil
+	})
+
+	const broken = `{"someone": [16271`
+	if err := os.WriteFile(watchStore.Path(), []byte(broken), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Add("user", 11111); err == nil {
+		t.Fatal("Add saved even though the watchlist failed to load")
+	}
+	if _, err := Remove("someone", 16271); err == nil {
+		t.Fatal("Remove saved even though the watchlist failed to load")
+	}
+	if data, _ := os.ReadFile(watchStore.Path()); string(data) != broken {
+		t.Fatalf("file was overwritten with %s", data)
+	}
+
+	if err := os.WriteFile(watchStore.Path(), []byte(`{"someone": [16271]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if added, err := Add("user", 11111); err != nil || !added {
+		t.Fatalf("Add = %v, %v", added, err)
+	}
+	if got := Watchers(16271, 11111); len(got["someone"]) != 1 || len(got["user"]) != 1 {
+		t.Errorf("watchers = %v, want someone and user", got)
+	}
+}
@@ -1,16 +1,16 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/shuban-789/bjorn/src/bot"
+	"github.com/shuban-789/bjorn/src/bot/logging"
 )
 
 func main() {
 	BotToken := os.Getenv("DSC_BOT_TOKEN")
 	if BotToken == "" {
-		fmt.Println("\033[31m[FAIL]\033[0m No Bot Token found")
+		logging.For("main").Error("No bot token found, set DSC_BOT_TOKEN")
 		return
 	}
 	bot.Deploy(BotToken)